@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"net/url"
 	"os"
+	"time"
 
 	klatest "github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
@@ -13,7 +15,14 @@ import (
 	"github.com/openshift/origin/pkg/deploy/deployer/customimage"
 )
 
+var (
+	namespace    = flag.String("namespace", os.Getenv("KUBERNETES_DEPLOYMENT_NAMESPACE"), "The namespace of the deployment")
+	timeout      = flag.Duration("timeout", 2*time.Minute, "How long to wait for the pods of previous deployments to terminate")
+	pollInterval = flag.Duration("poll-interval", 1*time.Second, "How often to check whether the pods of previous deployments have terminated")
+)
+
 func main() {
+	flag.Parse()
 	util.InitLogs()
 	defer util.FlushLogs()
 
@@ -28,13 +37,14 @@ func main() {
 		glog.Fatalf("Unable to parse %v as a URL\n", err)
 	}
 
-	kClient, err := kclient.New(&kclient.Config{Host: masterServer, Version: klatest.Version})
+	token := os.Getenv("BEARER_TOKEN")
+	kClient, err := kclient.New(&kclient.Config{Host: masterServer, Version: klatest.Version, BearerToken: token})
 	if err != nil {
 		glog.Errorf("Unable to connect to kubernetes master: %v", err)
 		os.Exit(1)
 	}
 
-	osClient, err := osclient.New(&kclient.Config{Host: masterServer, Version: oslatest.Version})
+	osClient, err := osclient.New(&kclient.Config{Host: masterServer, Version: oslatest.Version, BearerToken: token})
 	if err != nil {
 		glog.Errorf("Unable to connect to openshift master: %v", err)
 		os.Exit(1)
@@ -42,10 +52,25 @@ func main() {
 
 	deploymentID := os.Getenv("KUBERNETES_DEPLOYMENT_ID")
 	if len(deploymentID) == 0 {
-		glog.Fatal("No deployment id was specified. Expected KUBERNETES_DEPLOYMENT_ID variable.")
-		return
+		glog.Errorf("No deployment id was specified. Expected KUBERNETES_DEPLOYMENT_ID variable.")
+		os.Exit(1)
 	}
 
-	d := customimage.CustomImageDeployer{kClient, osClient}
-	d.Deploy(deploymentID)
+	if len(*namespace) == 0 {
+		glog.Errorf("No deployment namespace was specified. Expected KUBERNETES_DEPLOYMENT_NAMESPACE variable.")
+		os.Exit(1)
+	}
+
+	d := customimage.CustomImageDeployer{
+		KClient:      kClient,
+		OSClient:     osClient,
+		Namespace:    *namespace,
+		PollInterval: *pollInterval,
+		Timeout:      *timeout,
+	}
+	if err := d.Deploy(deploymentID); err != nil {
+		glog.Errorf("Deployment %s failed: %v", deploymentID, err)
+		util.FlushLogs()
+		os.Exit(1)
+	}
 }
@@ -7,20 +7,35 @@ import (
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	errors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
+	oscache "github.com/openshift/origin/pkg/client/cache"
 )
 
 // BuildController watches build resources and manages their state
 type BuildController struct {
-	BuildStore    cache.Store
+	// BuildStore is indexed by BuildPodIndex.
+	BuildStore    oscache.Indexer
 	NextBuild     func() *buildapi.Build
 	NextPod       func() *kapi.Pod
 	BuildUpdater  buildUpdater
 	PodCreator    podCreator
 	BuildStrategy BuildStrategy
+	// ProvenanceRecorder, if set, records the provenance of the images of completed builds.
+	ProvenanceRecorder provenanceRecorder
+}
+
+// BuildPodIndex is the name of the index of Builds by the ID of their pod.
+const BuildPodIndex = "pod"
+
+// IndexBuildByPod returns the ID of the pod of a Build, if it has one.
+func IndexBuildByPod(obj interface{}) []string {
+	build := obj.(*buildapi.Build)
+	if len(build.PodID) == 0 {
+		return []string{}
+	}
+	return []string{build.PodID}
 }
 
 // BuildStrategy knows how to create a pod spec for a pod which can execute a build.
@@ -32,6 +47,10 @@ type buildUpdater interface {
 	UpdateBuild(ctx kapi.Context, build *buildapi.Build) (*buildapi.Build, error)
 }
 
+type provenanceRecorder interface {
+	RecordProvenance(build *buildapi.Build) error
+}
+
 type podCreator interface {
 	CreatePod(ctx kapi.Context, pod *kapi.Pod) (*kapi.Pod, error)
 }
@@ -81,12 +100,8 @@ func (bc *BuildController) HandleBuild(build *buildapi.Build) {
 func (bc *BuildController) HandlePod(pod *kapi.Pod) {
 	// Find the build for this pod
 	var build *buildapi.Build
-	for _, obj := range bc.BuildStore.List() {
-		b := obj.(*buildapi.Build)
-		if b.PodID == pod.ID {
-			build = b
-			break
-		}
+	if builds := bc.BuildStore.Index(BuildPodIndex, pod.ID); len(builds) > 0 {
+		build = builds[0].(*buildapi.Build)
 	}
 
 	if build == nil {
@@ -115,6 +130,16 @@ func (bc *BuildController) HandlePod(pod *kapi.Pod) {
 		build.Status = nextStatus
 		if _, err := bc.BuildUpdater.UpdateBuild(kapi.WithNamespace(kapi.NewContext(), build.Namespace), build); err != nil {
 			glog.V(2).Infof("Failed to update build %s: %#v", build.ID, err)
+			return
+		}
+		if nextStatus == buildapi.BuildStatusComplete && bc.ProvenanceRecorder != nil {
+			// the push of the image may be reported after the build completes, so don't hold up
+			// other pods while the recorder waits for it
+			go func() {
+				if err := bc.ProvenanceRecorder.RecordProvenance(build); err != nil {
+					glog.V(2).Infof("Failed to record the provenance of the image of build %s: %v", build.ID, err)
+				}
+			}()
 		}
 	}
 }
@@ -3,6 +3,7 @@ package controller
 import (
 	"errors"
 	"testing"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -255,3 +256,33 @@ func TestHandlePod(t *testing.T) {
 		}
 	}
 }
+
+type fakeProvenanceRecorder struct {
+	builds chan string
+}
+
+func (r *fakeProvenanceRecorder) RecordProvenance(build *buildapi.Build) error {
+	r.builds <- build.ID
+	return nil
+}
+
+func TestHandlePodRecordsProvenanceOfCompletedBuilds(t *testing.T) {
+	for _, exitCode := range []int{0, -1} {
+		build, ctrl := mockBuildAndController(buildapi.BuildStatusRunning)
+		recorder := &fakeProvenanceRecorder{builds: make(chan string, 1)}
+		ctrl.ProvenanceRecorder = recorder
+		pod := mockPod(kapi.PodTerminated, exitCode)
+		build.PodID = pod.ID
+
+		ctrl.HandlePod(pod)
+
+		recorded := ""
+		select {
+		case recorded = <-recorder.builds:
+		case <-time.After(50 * time.Millisecond):
+		}
+		if e, a := exitCode == 0, recorded == build.ID; e != a {
+			t.Errorf("exit code %d: expected provenance recorded %t, got %q", exitCode, e, recorded)
+		}
+	}
+}
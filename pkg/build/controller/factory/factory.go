@@ -17,23 +17,34 @@ import (
 	controller "github.com/openshift/origin/pkg/build/controller"
 	strategy "github.com/openshift/origin/pkg/build/controller/strategy"
 	osclient "github.com/openshift/origin/pkg/client"
+	oscache "github.com/openshift/origin/pkg/client/cache"
 )
 
+// NewBuildCache creates a cache of all Builds indexed by controller.BuildPodIndex, which may be
+// shared by any controller interested in Builds.
+func NewBuildCache(client osclient.Interface) *oscache.Informer {
+	return oscache.NewInformer(&buildLW{client: client}, &buildapi.Build{}, oscache.Indexers{
+		controller.BuildPodIndex: controller.IndexBuildByPod,
+	})
+}
+
 type BuildControllerFactory struct {
 	Client              *osclient.Client
 	KubeClient          *kclient.Client
+	Builds              *oscache.Informer
 	DockerBuildStrategy *strategy.DockerBuildStrategy
 	STIBuildStrategy    *strategy.STIBuildStrategy
+	ImageSignatures     controller.ImageSignatureCreator
 
 	buildStore cache.Store
 }
 
 func (factory *BuildControllerFactory) Create() *controller.BuildController {
-	factory.buildStore = cache.NewStore()
-	cache.NewReflector(&buildLW{client: factory.Client}, &buildapi.Build{}, factory.buildStore).Run()
+	factory.buildStore = factory.Builds.Store()
 
 	buildQueue := cache.NewFIFO()
-	cache.NewReflector(&buildLW{client: factory.Client}, &buildapi.Build{}, buildQueue).Run()
+	factory.Builds.AddQueue(buildQueue, nil)
+	factory.Builds.Run()
 
 	// Kubernetes does not currently synchronize Pod status in storage with a Pod's container
 	// states. Because of this, we can't receive events related to container (and thus Pod)
@@ -47,7 +58,7 @@ func (factory *BuildControllerFactory) Create() *controller.BuildController {
 	cache.NewPoller(factory.pollPods, 10*time.Second, podQueue).Run()
 
 	return &controller.BuildController{
-		BuildStore:   factory.buildStore,
+		BuildStore:   factory.Builds.Store(),
 		BuildUpdater: factory.Client,
 		PodCreator:   factory.KubeClient,
 		NextBuild: func() *buildapi.Build {
@@ -60,6 +71,12 @@ func (factory *BuildControllerFactory) Create() *controller.BuildController {
 			DockerBuildStrategy: factory.DockerBuildStrategy,
 			STIBuildStrategy:    factory.STIBuildStrategy,
 		},
+		ProvenanceRecorder: &controller.ImageProvenanceRecorder{
+			ImageRepositories: factory.Client,
+			ImageSignatures:   factory.ImageSignatures,
+			Timeout:           2 * time.Minute,
+			PollInterval:      5 * time.Second,
+		},
 	}
 }
 
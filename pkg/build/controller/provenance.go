@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// ImageProvenanceRecorder records which Build produced the image a completed Build pushed, by
+// attaching an ImageSignature with the provenance of the Build to the Image. The image is the one
+// whose push to the tag of the build output was reported to the ImageRepository of the output while
+// the build ran. The recorder waits for the push to be reported, and records nothing if several
+// images were pushed to the tag in the meantime, as it can't tell which the build produced.
+type ImageProvenanceRecorder struct {
+	ImageRepositories imageRepositoryLister
+	// ImageSignatures attaches the provenance. Clients of the API can't record provenance, so it
+	// must be served by the master directly.
+	ImageSignatures ImageSignatureCreator
+	// Timeout is how long to wait for the push of the image of a build to be reported.
+	Timeout time.Duration
+	// PollInterval is how often to look for the pushed image while waiting.
+	PollInterval time.Duration
+}
+
+type imageRepositoryLister interface {
+	ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (*imageapi.ImageRepositoryList, error)
+}
+
+// ImageSignatureCreator attaches ImageSignatures to the Images they name.
+type ImageSignatureCreator interface {
+	CreateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (*imageapi.Image, error)
+}
+
+// errImageNotPushed means the push of the image of a build hasn't been reported yet.
+var errImageNotPushed = errors.New("the push of the image hasn't been reported yet")
+
+// RecordProvenance attaches the provenance of build to the image it pushed.
+func (r *ImageProvenanceRecorder) RecordProvenance(build *buildapi.Build) error {
+	dockerRepo, tag, err := buildOutputRepository(build)
+	if err != nil {
+		return err
+	}
+	ctx := kapi.WithNamespace(kapi.NewContext(), build.Namespace)
+
+	deadline := time.Now().Add(r.Timeout)
+	repo, imageID, err := r.pushedImage(ctx, build, dockerRepo, tag)
+	for err == errImageNotPushed && time.Now().Before(deadline) {
+		time.Sleep(r.PollInterval)
+		repo, imageID, err = r.pushedImage(ctx, build, dockerRepo, tag)
+	}
+	if err != nil {
+		return err
+	}
+
+	provenance := &imageapi.ImageProvenance{
+		Build:       build.ID,
+		BuildConfig: build.Labels[buildapi.BuildConfigLabel],
+	}
+	if source := build.Parameters.Source.Git; source != nil {
+		provenance.SourceURI = source.URI
+	}
+	if revision := build.Parameters.Revision; revision != nil && revision.Git != nil {
+		provenance.Commit = revision.Git.Commit
+	}
+
+	_, err = r.ImageSignatures.CreateImageSignature(kapi.WithNamespace(ctx, repo.Namespace), &imageapi.ImageSignature{
+		TypeMeta:   kapi.TypeMeta{ID: "build-" + build.ID, Namespace: repo.Namespace},
+		Image:      imageID,
+		Signer:     imageapi.ImageProvenanceSigner,
+		Provenance: provenance,
+	})
+	return err
+}
+
+// pushedImage returns the ImageRepository of dockerRepo and the ID of the only image pushed to its
+// tag since build was created.
+func (r *ImageProvenanceRecorder) pushedImage(ctx kapi.Context, build *buildapi.Build, dockerRepo, tag string) (*imageapi.ImageRepository, string, error) {
+	field := labels.SelectorFromSet(labels.Set{"dockerImageRepository": dockerRepo})
+	repos, err := r.ImageRepositories.ListImageRepositories(ctx, field, labels.Everything())
+	if err != nil {
+		return nil, "", err
+	}
+	if len(repos.Items) == 0 {
+		return nil, "", fmt.Errorf("no image repository of the build output %s exists", dockerRepo)
+	}
+	repo := &repos.Items[0]
+
+	pushed := []string{}
+	for _, event := range repo.TagHistory[tag] {
+		if event.SetBy == imageapi.TagEventSourceMapping && !event.Created.Before(build.CreationTimestamp.Time) {
+			pushed = append(pushed, event.ImageID)
+		}
+	}
+	switch len(pushed) {
+	case 0:
+		return nil, "", errImageNotPushed
+	case 1:
+		return repo, pushed[0], nil
+	default:
+		return nil, "", fmt.Errorf("%d images were pushed to tag %s of image repository %s while build %s ran", len(pushed), tag, repo.ID, build.ID)
+	}
+}
+
+// buildOutputRepository returns the Docker image repository and tag the build pushes its image to,
+// the way the builders name the image.
+func buildOutputRepository(build *buildapi.Build) (string, string, error) {
+	spec := build.Parameters.Output.ImageTag
+	if len(build.Parameters.Output.Registry) > 0 {
+		spec = build.Parameters.Output.Registry + "/" + spec
+	}
+	ref, err := imageapi.ParseDockerImageReference(spec)
+	if err != nil {
+		return "", "", err
+	}
+	tag := ref.Tag
+	if len(tag) == 0 {
+		tag = "latest"
+	}
+	return ref.RepositoryName(), tag, nil
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+type fakeImageClient struct {
+	repos      []imageapi.ImageRepository
+	signatures []*imageapi.ImageSignature
+	namespaces []string
+}
+
+func (c *fakeImageClient) ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (*imageapi.ImageRepositoryList, error) {
+	repos := &imageapi.ImageRepositoryList{}
+	for _, repo := range c.repos {
+		if field.Matches(labels.Set{"dockerImageRepository": repo.DockerImageRepository}) {
+			repos.Items = append(repos.Items, repo)
+		}
+	}
+	return repos, nil
+}
+
+func (c *fakeImageClient) CreateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (*imageapi.Image, error) {
+	c.signatures = append(c.signatures, signature)
+	c.namespaces = append(c.namespaces, kapi.Namespace(ctx))
+	return &imageapi.Image{}, nil
+}
+
+// buildCreated is when completedBuild was created.
+var buildCreated = time.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func completedBuild() *buildapi.Build {
+	return &buildapi.Build{
+		TypeMeta: kapi.TypeMeta{ID: "build1", Namespace: "app", CreationTimestamp: util.Time{Time: buildCreated}},
+		Labels:   map[string]string{buildapi.BuildConfigLabel: "frontend"},
+		Parameters: buildapi.BuildParameters{
+			Source: buildapi.BuildSource{
+				Type: buildapi.BuildSourceGit,
+				Git:  &buildapi.GitBuildSource{URI: "git://github.com/openshift/ruby-hello-world.git"},
+			},
+			Revision: &buildapi.SourceRevision{
+				Type: buildapi.BuildSourceGit,
+				Git:  &buildapi.GitSourceRevision{Commit: "a1b2c3"},
+			},
+			Output: buildapi.BuildOutput{
+				Registry: "registry:5000",
+				ImageTag: "app/frontend:v1",
+			},
+		},
+		Status: buildapi.BuildStatusComplete,
+	}
+}
+
+// pushEvent returns the tag event of a push of the image with the given ID the given time after
+// completedBuild was created.
+func pushEvent(imageID string, after time.Duration) imageapi.TagEvent {
+	return imageapi.TagEvent{
+		Created: util.Time{Time: buildCreated.Add(after)},
+		ImageID: imageID,
+		SetBy:   imageapi.TagEventSourceMapping,
+	}
+}
+
+func TestRecordProvenance(t *testing.T) {
+	client := &fakeImageClient{
+		repos: []imageapi.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "other", Namespace: "app"},
+				DockerImageRepository: "registry:5000/app/other",
+				Tags:                  map[string]string{"v1": "image0"},
+				TagHistory:            map[string][]imageapi.TagEvent{"v1": {pushEvent("image0", time.Minute)}},
+			},
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "frontend", Namespace: "app"},
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags:                  map[string]string{"latest": "image1", "v1": "image3"},
+				TagHistory: map[string][]imageapi.TagEvent{
+					"latest": {pushEvent("image1", time.Minute)},
+					// the tag was set to image3 by another means after the build pushed image2
+					"v1": {
+						{Created: util.Time{Time: buildCreated.Add(2 * time.Minute)}, ImageID: "image3", SetBy: imageapi.TagEventSourceUpdate},
+						pushEvent("image2", time.Minute),
+						pushEvent("image0", -time.Hour),
+					},
+				},
+			},
+		},
+	}
+	recorder := &ImageProvenanceRecorder{ImageRepositories: client, ImageSignatures: client}
+
+	if err := recorder.RecordProvenance(completedBuild()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(client.signatures) != 1 {
+		t.Fatalf("Expected one signature, got %#v", client.signatures)
+	}
+	signature := client.signatures[0]
+	if e, a := "image2", signature.Image; e != a {
+		t.Errorf("Expected the signature of image %s, got %s", e, a)
+	}
+	if e, a := imageapi.ImageProvenanceSigner, signature.Signer; e != a {
+		t.Errorf("Expected signer %s, got %s", e, a)
+	}
+	expected := &imageapi.ImageProvenance{
+		Build:       "build1",
+		BuildConfig: "frontend",
+		SourceURI:   "git://github.com/openshift/ruby-hello-world.git",
+		Commit:      "a1b2c3",
+	}
+	if !reflect.DeepEqual(expected, signature.Provenance) {
+		t.Errorf("Expected provenance %#v, got %#v", expected, signature.Provenance)
+	}
+	if e, a := "app", client.namespaces[0]; e != a {
+		t.Errorf("Expected the signature in namespace %s, got %s", e, a)
+	}
+}
+
+func TestRecordProvenanceAmbiguousPush(t *testing.T) {
+	client := &fakeImageClient{
+		repos: []imageapi.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "frontend", Namespace: "app"},
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags:                  map[string]string{"v1": "image2"},
+				TagHistory: map[string][]imageapi.TagEvent{
+					"v1": {pushEvent("image2", 2*time.Minute), pushEvent("image1", time.Minute)},
+				},
+			},
+		},
+	}
+	recorder := &ImageProvenanceRecorder{ImageRepositories: client, ImageSignatures: client}
+
+	if err := recorder.RecordProvenance(completedBuild()); err == nil {
+		t.Errorf("Expected an error for several pushes while the build ran")
+	}
+	if len(client.signatures) != 0 {
+		t.Errorf("Unexpected signatures %#v", client.signatures)
+	}
+}
+
+func TestRecordProvenanceWaitsForPush(t *testing.T) {
+	client := &fakeImageClient{
+		repos: []imageapi.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "frontend", Namespace: "app"},
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags:                  map[string]string{"v1": "image1"},
+				TagHistory:            map[string][]imageapi.TagEvent{"v1": {pushEvent("image1", -time.Hour)}},
+			},
+		},
+	}
+	recorder := &ImageProvenanceRecorder{
+		ImageRepositories: client,
+		ImageSignatures:   client,
+		Timeout:           20 * time.Millisecond,
+		PollInterval:      time.Millisecond,
+	}
+
+	if err := recorder.RecordProvenance(completedBuild()); err != errImageNotPushed {
+		t.Errorf("Expected the push not to be reported, got %v", err)
+	}
+	if len(client.signatures) != 0 {
+		t.Errorf("Unexpected signatures %#v", client.signatures)
+	}
+}
+
+func TestRecordProvenanceUnknownOutput(t *testing.T) {
+	client := &fakeImageClient{
+		repos: []imageapi.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "frontend", Namespace: "app"},
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags:                  map[string]string{"latest": "image1"},
+			},
+		},
+	}
+	recorder := &ImageProvenanceRecorder{ImageRepositories: client, ImageSignatures: client}
+
+	build := completedBuild()
+	if err := recorder.RecordProvenance(build); err == nil {
+		t.Errorf("Expected an error for a missing tag")
+	}
+	build.Parameters.Output.Registry = "other:5000"
+	if err := recorder.RecordProvenance(build); err == nil {
+		t.Errorf("Expected an error for an unknown repository")
+	}
+	if len(client.signatures) != 0 {
+		t.Errorf("Unexpected signatures %#v", client.signatures)
+	}
+}
+
+func TestBuildOutputRepository(t *testing.T) {
+	testCases := []struct {
+		registry, imageTag string
+		repo, tag          string
+	}{
+		{"", "app/frontend", "app/frontend", "latest"},
+		{"", "app/frontend:v1", "app/frontend", "v1"},
+		{"registry:5000", "app/frontend", "registry:5000/app/frontend", "latest"},
+		{"registry:5000", "app/frontend:v1", "registry:5000/app/frontend", "v1"},
+	}
+	for _, testCase := range testCases {
+		build := &buildapi.Build{Parameters: buildapi.BuildParameters{Output: buildapi.BuildOutput{Registry: testCase.registry, ImageTag: testCase.imageTag}}}
+		repo, tag, err := buildOutputRepository(build)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %v", testCase.registry, testCase.imageTag, err)
+			continue
+		}
+		if repo != testCase.repo || tag != testCase.tag {
+			t.Errorf("%s %s: expected %s %s, got %s %s", testCase.registry, testCase.imageTag, testCase.repo, testCase.tag, repo, tag)
+		}
+	}
+}
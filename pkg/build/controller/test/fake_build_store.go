@@ -39,3 +39,11 @@ func (s FakeBuildStore) Get(id string) (item interface{}, exists bool) {
 }
 
 func (s FakeBuildStore) Replace(idToObj map[string]interface{}) {}
+
+func (s FakeBuildStore) Index(name, key string) []interface{} {
+	if s.Build == nil || s.Build.PodID != key {
+		return []interface{}{}
+	}
+
+	return []interface{}{s.Build}
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// IndexFunc returns the keys under which an object is indexed. An object may be indexed under any
+// number of keys, including none.
+type IndexFunc func(obj interface{}) []string
+
+// Indexers maps the name of an index to the function which computes its keys.
+type Indexers map[string]IndexFunc
+
+// Indexer is a Store which also maintains indices over the objects it holds, so that callers can
+// find the objects matching a key without listing the whole store.
+type Indexer interface {
+	cache.Store
+	// Index returns the objects indexed under key by the index with the given name.
+	Index(name, key string) []interface{}
+}
+
+type indexer struct {
+	lock     sync.RWMutex
+	items    map[string]interface{}
+	indexers Indexers
+	// indices maps an index name to its keys, and each key to the IDs of the objects indexed under it.
+	indices map[string]map[string]util.StringSet
+}
+
+// NewIndexer returns an Indexer which maintains the given indices.
+func NewIndexer(indexers Indexers) Indexer {
+	i := &indexer{
+		items:    map[string]interface{}{},
+		indexers: indexers,
+	}
+	i.resetIndices()
+	return i
+}
+
+// Add inserts an item into the store and indexes it.
+func (i *indexer) Add(id string, obj interface{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.set(id, obj)
+}
+
+// Update sets an item in the store to its updated state and reindexes it.
+func (i *indexer) Update(id string, obj interface{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.set(id, obj)
+}
+
+// Delete removes an item from the store and its indices.
+func (i *indexer) Delete(id string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if old, exists := i.items[id]; exists {
+		i.unindex(id, old)
+		delete(i.items, id)
+	}
+}
+
+// List returns a list of all the items.
+func (i *indexer) List() []interface{} {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	list := make([]interface{}, 0, len(i.items))
+	for _, item := range i.items {
+		list = append(list, item)
+	}
+	return list
+}
+
+// Contains returns a util.StringSet containing the IDs of all stored items.
+func (i *indexer) Contains() util.StringSet {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	set := util.StringSet{}
+	for id := range i.items {
+		set.Insert(id)
+	}
+	return set
+}
+
+// Get returns the requested item, or sets exists=false.
+func (i *indexer) Get(id string) (item interface{}, exists bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	item, exists = i.items[id]
+	return item, exists
+}
+
+// Replace deletes the contents of the store and rebuilds its indices from the given map. The store
+// takes ownership of the map.
+func (i *indexer) Replace(idToObj map[string]interface{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.items = idToObj
+	i.resetIndices()
+	for id, obj := range i.items {
+		i.index(id, obj)
+	}
+}
+
+// Index returns the items indexed under key by the named index. It returns nothing for an unknown
+// index.
+func (i *indexer) Index(name, key string) []interface{} {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	ids := i.indices[name][key]
+	list := make([]interface{}, 0, len(ids))
+	for _, id := range ids.List() {
+		list = append(list, i.items[id])
+	}
+	return list
+}
+
+func (i *indexer) set(id string, obj interface{}) {
+	if old, exists := i.items[id]; exists {
+		i.unindex(id, old)
+	}
+	i.items[id] = obj
+	i.index(id, obj)
+}
+
+func (i *indexer) index(id string, obj interface{}) {
+	for name, indexFunc := range i.indexers {
+		index := i.indices[name]
+		for _, key := range indexFunc(obj) {
+			if _, exists := index[key]; !exists {
+				index[key] = util.StringSet{}
+			}
+			index[key].Insert(id)
+		}
+	}
+}
+
+func (i *indexer) unindex(id string, obj interface{}) {
+	for name, indexFunc := range i.indexers {
+		index := i.indices[name]
+		for _, key := range indexFunc(obj) {
+			index[key].Delete(id)
+			if len(index[key]) == 0 {
+				delete(index, key)
+			}
+		}
+	}
+}
+
+func (i *indexer) resetIndices() {
+	i.indices = map[string]map[string]util.StringSet{}
+	for name := range i.indexers {
+		i.indices[name] = map[string]util.StringSet{}
+	}
+}
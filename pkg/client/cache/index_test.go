@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testObject struct {
+	id   string
+	tags []string
+}
+
+func indexByTag(obj interface{}) []string {
+	return obj.(*testObject).tags
+}
+
+func indexedIDs(objs []interface{}) []string {
+	ids := []string{}
+	for _, obj := range objs {
+		ids = append(ids, obj.(*testObject).id)
+	}
+	return ids
+}
+
+func TestIndexerAddUpdateDelete(t *testing.T) {
+	store := NewIndexer(Indexers{"tag": indexByTag})
+
+	store.Add("a", &testObject{"a", []string{"x", "y"}})
+	store.Add("b", &testObject{"b", []string{"y"}})
+
+	if e, a := []string{"a", "b"}, indexedIDs(store.Index("tag", "y")); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+
+	store.Update("a", &testObject{"a", []string{"z"}})
+	if e, a := []string{"b"}, indexedIDs(store.Index("tag", "y")); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+	if e, a := []string{"a"}, indexedIDs(store.Index("tag", "z")); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+	if objs := store.Index("tag", "x"); len(objs) != 0 {
+		t.Errorf("Expected the stale key to be unindexed, got %v", indexedIDs(objs))
+	}
+
+	store.Delete("b")
+	if objs := store.Index("tag", "y"); len(objs) != 0 {
+		t.Errorf("Expected the deleted item to be unindexed, got %v", indexedIDs(objs))
+	}
+	if _, exists := store.Get("b"); exists {
+		t.Errorf("Expected the deleted item to be removed")
+	}
+}
+
+func TestIndexerReplace(t *testing.T) {
+	store := NewIndexer(Indexers{"tag": indexByTag})
+	store.Add("a", &testObject{"a", []string{"x"}})
+
+	store.Replace(map[string]interface{}{
+		"b": &testObject{"b", []string{"x"}},
+		"c": &testObject{"c", []string{"y"}},
+	})
+
+	if e, a := []string{"b"}, indexedIDs(store.Index("tag", "x")); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+	if e, a := 2, len(store.List()); e != a {
+		t.Errorf("Expected %d items, got %d", e, a)
+	}
+	if objs := store.Index("unknown", "x"); len(objs) != 0 {
+		t.Errorf("Expected nothing from an unknown index, got %v", indexedIDs(objs))
+	}
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// FilterFunc returns true if an object should be delivered to a queue.
+type FilterFunc func(obj interface{}) bool
+
+// Informer keeps an indexed store of a resource up to date from a single list and watch, and passes
+// every change it observes on to any number of queues. Controllers interested in the same resource
+// share an Informer rather than each running a reflector of their own.
+type Informer struct {
+	lw           cache.ListerWatcher
+	expectedType interface{}
+	store        Indexer
+	once         sync.Once
+
+	// lock serializes the delivery of changes with the addition of queues.
+	lock   sync.Mutex
+	queues []filteredQueue
+}
+
+type filteredQueue struct {
+	queue  cache.Store
+	filter FilterFunc
+}
+
+// NewInformer creates an Informer for objects of expectedType listed and watched with lw, whose
+// store maintains the given indices.
+func NewInformer(lw cache.ListerWatcher, expectedType interface{}, indexers Indexers) *Informer {
+	return &Informer{
+		lw:           lw,
+		expectedType: expectedType,
+		store:        NewIndexer(indexers),
+	}
+}
+
+// Store returns the indexed store of all objects observed by the Informer.
+func (i *Informer) Store() Indexer {
+	return i.store
+}
+
+// AddQueue delivers changes to objects accepted by filter to queue. A nil filter accepts every object.
+// The queue is seeded with the accepted objects already in the store, so queues may be added after
+// the Informer is running.
+func (i *Informer) AddQueue(queue cache.Store, filter FilterFunc) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	q := filteredQueue{queue, filter}
+	items := map[string]interface{}{}
+	for _, id := range i.store.Contains().List() {
+		if obj, exists := i.store.Get(id); exists && q.accepts(obj) {
+			items[id] = obj
+		}
+	}
+	if len(items) > 0 {
+		queue.Replace(items)
+	}
+	i.queues = append(i.queues, q)
+}
+
+// Run starts the list and watch of the Informer. Only the first call has any effect, so every
+// controller sharing the Informer may call it.
+func (i *Informer) Run() {
+	i.once.Do(func() {
+		cache.NewReflector(i.lw, i.expectedType, &distributor{i}).Run()
+	})
+}
+
+func (q filteredQueue) accepts(obj interface{}) bool {
+	return q.filter == nil || q.filter(obj)
+}
+
+// distributor is the Store the Informer's reflector writes to. It applies each change to the
+// Informer's store before its queues, so that consumers of a queue always find the object they
+// dequeue in the store.
+type distributor struct {
+	informer *Informer
+}
+
+func (d *distributor) Add(id string, obj interface{}) {
+	d.informer.lock.Lock()
+	defer d.informer.lock.Unlock()
+	d.informer.store.Add(id, obj)
+	for _, q := range d.informer.queues {
+		if q.accepts(obj) {
+			q.queue.Add(id, obj)
+		}
+	}
+}
+
+func (d *distributor) Update(id string, obj interface{}) {
+	d.informer.lock.Lock()
+	defer d.informer.lock.Unlock()
+	d.informer.store.Update(id, obj)
+	for _, q := range d.informer.queues {
+		if q.accepts(obj) {
+			q.queue.Update(id, obj)
+		}
+	}
+}
+
+func (d *distributor) Delete(id string) {
+	d.informer.lock.Lock()
+	defer d.informer.lock.Unlock()
+	d.informer.store.Delete(id)
+	for _, q := range d.informer.queues {
+		q.queue.Delete(id)
+	}
+}
+
+func (d *distributor) Replace(idToObj map[string]interface{}) {
+	d.informer.lock.Lock()
+	defer d.informer.lock.Unlock()
+	queued := make([]map[string]interface{}, len(d.informer.queues))
+	for i, q := range d.informer.queues {
+		queued[i] = map[string]interface{}{}
+		for id, obj := range idToObj {
+			if q.accepts(obj) {
+				queued[i][id] = obj
+			}
+		}
+	}
+	d.informer.store.Replace(idToObj)
+	for i, q := range d.informer.queues {
+		q.queue.Replace(queued[i])
+	}
+}
+
+func (d *distributor) List() []interface{} {
+	return d.informer.store.List()
+}
+
+func (d *distributor) Contains() util.StringSet {
+	return d.informer.store.Contains()
+}
+
+func (d *distributor) Get(id string) (interface{}, bool) {
+	return d.informer.store.Get(id)
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+)
+
+func queuedIDs(queue cache.Store) []string {
+	ids := queue.Contains().List()
+	sort.Strings(ids)
+	return ids
+}
+
+func TestInformerDistributesToQueues(t *testing.T) {
+	informer := NewInformer(nil, &testObject{}, Indexers{"tag": indexByTag})
+	all := cache.NewFIFO()
+	tagged := cache.NewFIFO()
+	informer.AddQueue(all, nil)
+	informer.AddQueue(tagged, func(obj interface{}) bool {
+		return len(obj.(*testObject).tags) > 0
+	})
+
+	d := &distributor{informer}
+	d.Replace(map[string]interface{}{
+		"a": &testObject{"a", []string{"x"}},
+		"b": &testObject{"b", nil},
+	})
+	d.Add("c", &testObject{"c", []string{"x"}})
+
+	if e, a := []string{"a", "b", "c"}, queuedIDs(all); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+	if e, a := []string{"a", "c"}, queuedIDs(tagged); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+	if e, a := []string{"a", "c"}, indexedIDs(informer.Store().Index("tag", "x")); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+
+	d.Delete("a")
+	if e, a := []string{"c"}, queuedIDs(tagged); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+}
+
+func TestInformerSeedsLateQueues(t *testing.T) {
+	informer := NewInformer(nil, &testObject{}, Indexers{})
+	d := &distributor{informer}
+	d.Add("a", &testObject{"a", nil})
+
+	queue := cache.NewFIFO()
+	informer.AddQueue(queue, nil)
+
+	if e, a := []string{"a"}, queuedIDs(queue); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// WorkQueue is a queue of the IDs of objects awaiting processing. An ID is queued at most once however
+// often its object changes, and an ID whose object changes while it is being processed is queued
+// again once processing is done. IDs which fail to be processed can be retried after a delay which
+// doubles with every consecutive failure.
+//
+// WorkQueue implements cache.Store so that it can be fed by an Informer or a Reflector, but it keeps
+// only the IDs of the objects it receives; processors look up the current state of an object by ID.
+type WorkQueue struct {
+	lock sync.Mutex
+	cond sync.Cond
+
+	queue      []string
+	queued     util.StringSet
+	processing util.StringSet
+	// dirty holds the IDs which were added while they were being processed.
+	dirty    util.StringSet
+	failures map[string]int
+
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// NewWorkQueue creates a WorkQueue which retries an ID after baseDelay on its first failure, doubling
+// the delay on each further failure up to maxDelay.
+func NewWorkQueue(baseDelay, maxDelay time.Duration) *WorkQueue {
+	q := &WorkQueue{
+		queued:     util.StringSet{},
+		processing: util.StringSet{},
+		dirty:      util.StringSet{},
+		failures:   map[string]int{},
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+	}
+	q.cond.L = &q.lock
+	return q
+}
+
+// Add queues the ID.
+func (q *WorkQueue) Add(id string, obj interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.enqueue(id)
+}
+
+// Update queues the ID.
+func (q *WorkQueue) Update(id string, obj interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.enqueue(id)
+}
+
+// Delete queues the ID, so that its processor observes the deletion.
+func (q *WorkQueue) Delete(id string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.enqueue(id)
+}
+
+// Replace queues every ID in the map.
+func (q *WorkQueue) Replace(idToObj map[string]interface{}) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for id := range idToObj {
+		q.enqueue(id)
+	}
+}
+
+// List returns the queued IDs.
+func (q *WorkQueue) List() []interface{} {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	list := make([]interface{}, 0, len(q.queue))
+	for _, id := range q.queue {
+		list = append(list, id)
+	}
+	return list
+}
+
+// Contains returns the set of queued IDs.
+func (q *WorkQueue) Contains() util.StringSet {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return util.NewStringSet(q.queue...)
+}
+
+// Get returns the ID and whether it is queued.
+func (q *WorkQueue) Get(id string) (interface{}, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return id, q.queued.Has(id)
+}
+
+// Pop waits until an ID is queued and returns it. The ID is considered to be processing until Done is
+// called with it.
+func (q *WorkQueue) Pop() string {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for len(q.queue) == 0 {
+		q.cond.Wait()
+	}
+	id := q.queue[0]
+	q.queue = q.queue[1:]
+	q.queued.Delete(id)
+	q.processing.Insert(id)
+	return id
+}
+
+// Done marks the processing of the ID as finished, queueing it again if it was added meanwhile.
+func (q *WorkQueue) Done(id string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.processing.Delete(id)
+	if q.dirty.Has(id) {
+		q.dirty.Delete(id)
+		q.enqueue(id)
+	}
+}
+
+// Retry records a failure to process the ID and queues it again once the backoff delay for its
+// consecutive failures has passed.
+func (q *WorkQueue) Retry(id string) {
+	q.lock.Lock()
+	q.failures[id]++
+	delay := q.baseDelay
+	for i := 1; i < q.failures[id] && delay < q.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > q.maxDelay {
+		delay = q.maxDelay
+	}
+	q.lock.Unlock()
+
+	time.AfterFunc(delay, func() { q.Add(id, nil) })
+}
+
+// Forget clears the failures recorded for the ID, typically once it has been processed successfully.
+func (q *WorkQueue) Forget(id string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	delete(q.failures, id)
+}
+
+// Failures returns the number of consecutive failures recorded for the ID.
+func (q *WorkQueue) Failures(id string) int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.failures[id]
+}
+
+func (q *WorkQueue) enqueue(id string) {
+	if q.processing.Has(id) {
+		q.dirty.Insert(id)
+		return
+	}
+	if q.queued.Has(id) {
+		return
+	}
+	q.queued.Insert(id)
+	q.queue = append(q.queue, id)
+	q.cond.Signal()
+}
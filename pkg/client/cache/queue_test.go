@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkQueueDeduplicates(t *testing.T) {
+	queue := NewWorkQueue(time.Millisecond, time.Millisecond)
+	queue.Add("a", nil)
+	queue.Update("a", nil)
+	queue.Add("b", nil)
+
+	if e, a := "a", queue.Pop(); e != a {
+		t.Fatalf("Expected %s, got %s", e, a)
+	}
+	if e, a := "b", queue.Pop(); e != a {
+		t.Fatalf("Expected %s, got %s", e, a)
+	}
+	if ids := queue.Contains(); len(ids) != 0 {
+		t.Fatalf("Expected an empty queue, got %v", ids.List())
+	}
+}
+
+func TestWorkQueueRequeuesAfterProcessing(t *testing.T) {
+	queue := NewWorkQueue(time.Millisecond, time.Millisecond)
+	queue.Add("a", nil)
+
+	id := queue.Pop()
+	queue.Add("a", nil)
+	if ids := queue.Contains(); len(ids) != 0 {
+		t.Fatalf("Expected the ID not to be queued while processing, got %v", ids.List())
+	}
+
+	queue.Done(id)
+	if !queue.Contains().Has("a") {
+		t.Fatalf("Expected the ID to be queued again once processed")
+	}
+}
+
+func TestWorkQueueRetry(t *testing.T) {
+	queue := NewWorkQueue(time.Millisecond, 2*time.Millisecond)
+	queue.Add("a", nil)
+
+	for i := 1; i <= 3; i++ {
+		id := queue.Pop()
+		queue.Retry(id)
+		queue.Done(id)
+		if e, a := i, queue.Failures(id); e != a {
+			t.Fatalf("Expected %d failures, got %d", e, a)
+		}
+	}
+
+	select {
+	case id := <-popAsync(queue):
+		if id != "a" {
+			t.Fatalf("Expected a, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the retry")
+	}
+
+	queue.Forget("a")
+	if failures := queue.Failures("a"); failures != 0 {
+		t.Fatalf("Expected no failures, got %d", failures)
+	}
+}
+
+func popAsync(queue *WorkQueue) <-chan string {
+	ch := make(chan string, 1)
+	go func() { ch <- queue.Pop() }()
+	return ch
+}
@@ -23,6 +23,7 @@ type Interface interface {
 	ImageRepositoryMappingInterface
 	DeploymentInterface
 	DeploymentConfigInterface
+	DeploymentConfigAutoscalerInterface
 	RouteInterface
 	UserInterface
 	UserIdentityMappingInterface
@@ -52,15 +53,19 @@ type ImageInterface interface {
 	ListImages(ctx kapi.Context, labels labels.Selector) (*imageapi.ImageList, error)
 	GetImage(ctx kapi.Context, id string) (*imageapi.Image, error)
 	CreateImage(ctx kapi.Context, image *imageapi.Image) (*imageapi.Image, error)
+	PruneImages(ctx kapi.Context, prune *imageapi.ImagePrune) (*imageapi.ImagePrune, error)
+	CreateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (*imageapi.Image, error)
+	UpdateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (*imageapi.Image, error)
 }
 
 // ImageRepositoryInterface exposes methods on ImageRepository resources.
 type ImageRepositoryInterface interface {
-	ListImageRepositories(ctx kapi.Context, labels labels.Selector) (*imageapi.ImageRepositoryList, error)
+	ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (*imageapi.ImageRepositoryList, error)
 	GetImageRepository(ctx kapi.Context, id string) (*imageapi.ImageRepository, error)
 	WatchImageRepositories(ctx kapi.Context, field, label labels.Selector, resourceVersion string) (watch.Interface, error)
 	CreateImageRepository(ctx kapi.Context, repo *imageapi.ImageRepository) (*imageapi.ImageRepository, error)
 	UpdateImageRepository(ctx kapi.Context, repo *imageapi.ImageRepository) (*imageapi.ImageRepository, error)
+	TagImageRepository(ctx kapi.Context, tag *imageapi.ImageRepositoryTag) (*imageapi.ImageRepository, error)
 }
 
 // ImageRepositoryMappingInterface exposes methods on ImageRepositoryMapping resources.
@@ -77,6 +82,20 @@ type DeploymentConfigInterface interface {
 	UpdateDeploymentConfig(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error)
 	DeleteDeploymentConfig(ctx kapi.Context, id string) error
 	GenerateDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error)
+	DiffDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfigDiff, error)
+	ApproveDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error)
+	GetDeploymentConfigHistory(ctx kapi.Context, id string) (*deployapi.DeploymentConfigHistory, error)
+	ScaleDeploymentConfig(ctx kapi.Context, id string, replicas int) (*deployapi.DeploymentConfig, error)
+}
+
+// DeploymentConfigAutoscalerInterface contains methods for working with DeploymentConfigAutoscalers
+type DeploymentConfigAutoscalerInterface interface {
+	ListDeploymentConfigAutoscalers(ctx kapi.Context, selector labels.Selector) (*deployapi.DeploymentConfigAutoscalerList, error)
+	WatchDeploymentConfigAutoscalers(ctx kapi.Context, field, label labels.Selector, resourceVersion string) (watch.Interface, error)
+	GetDeploymentConfigAutoscaler(ctx kapi.Context, id string) (*deployapi.DeploymentConfigAutoscaler, error)
+	CreateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error)
+	UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error)
+	DeleteDeploymentConfigAutoscaler(ctx kapi.Context, id string) error
 }
 
 // DeploymentInterface contains methods for working with Deployments
@@ -87,6 +106,8 @@ type DeploymentInterface interface {
 	UpdateDeployment(ctx kapi.Context, deployment *deployapi.Deployment) (*deployapi.Deployment, error)
 	DeleteDeployment(ctx kapi.Context, id string) error
 	WatchDeployments(ctx kapi.Context, field, label labels.Selector, resourceVersion string) (watch.Interface, error)
+	PromoteDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error)
+	AbortDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error)
 }
 
 // RouteInterface exposes methods on Route resources
@@ -232,10 +253,37 @@ func (c *Client) CreateImage(ctx kapi.Context, image *imageapi.Image) (result *i
 	return
 }
 
-// ListImageRepositories returns a list of imagerepositories that match the selector.
-func (c *Client) ListImageRepositories(ctx kapi.Context, selector labels.Selector) (result *imageapi.ImageRepositoryList, err error) {
+// PruneImages finds the images of a namespace which are no longer referenced, and deletes them if the prune is confirmed. Returns the server's representation of the prune and error if one occurs.
+func (c *Client) PruneImages(ctx kapi.Context, prune *imageapi.ImagePrune) (result *imageapi.ImagePrune, err error) {
+	result = &imageapi.ImagePrune{}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("imagePrunes").Body(prune).Do().Into(result)
+	return
+}
+
+// CreateImageSignature attaches a signature to the image it names. Returns the server's representation of the signed image and error if one occurs.
+func (c *Client) CreateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (result *imageapi.Image, err error) {
+	result = &imageapi.Image{}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("imageSignatures").Body(signature).Do().Into(result)
+	return
+}
+
+// UpdateImageSignature replaces an existing signature of the image it names. Returns the server's representation of the signed image and error if one occurs.
+func (c *Client) UpdateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (result *imageapi.Image, err error) {
+	result = &imageapi.Image{}
+	err = c.Put().Namespace(kapi.Namespace(ctx)).Path("imageSignatures").Path(signature.ID).Body(signature).Do().Into(result)
+	return
+}
+
+// ListImageRepositories returns a list of imagerepositories that match the label and field selectors.
+func (c *Client) ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (result *imageapi.ImageRepositoryList, err error) {
 	result = &imageapi.ImageRepositoryList{}
-	err = c.Get().Namespace(kapi.Namespace(ctx)).Path("imageRepositories").SelectorParam("labels", selector).Do().Into(result)
+	err = c.Get().
+		Namespace(kapi.Namespace(ctx)).
+		Path("imageRepositories").
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Do().
+		Into(result)
 	return
 }
 
@@ -272,6 +320,13 @@ func (c *Client) UpdateImageRepository(ctx kapi.Context, repo *imageapi.ImageRep
 	return
 }
 
+// TagImageRepository copies a tag of one imagerepository to another. Returns the server's representation of the updated imagerepository and error if one occurs.
+func (c *Client) TagImageRepository(ctx kapi.Context, tag *imageapi.ImageRepositoryTag) (result *imageapi.ImageRepository, err error) {
+	result = &imageapi.ImageRepository{}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("imageRepositoryTags").Body(tag).Do().Into(result)
+	return
+}
+
 // CreateImageRepositoryMapping create a new imagerepository mapping on the server. Returns error if one occurs.
 func (c *Client) CreateImageRepositoryMapping(ctx kapi.Context, mapping *imageapi.ImageRepositoryMapping) error {
 	return c.Post().Namespace(kapi.Namespace(ctx)).Path("imageRepositoryMappings").Body(mapping).Do().Error()
@@ -328,6 +383,81 @@ func (c *Client) GenerateDeploymentConfig(ctx kapi.Context, id string) (result *
 	return
 }
 
+// DiffDeploymentConfig returns the changes the next deployment of the deploymentConfig with the given ID would make.
+func (c *Client) DiffDeploymentConfig(ctx kapi.Context, id string) (result *deployapi.DeploymentConfigDiff, err error) {
+	result = &deployapi.DeploymentConfigDiff{}
+	err = c.Get().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigDiffs").Path(id).Do().Into(result)
+	return
+}
+
+// ApproveDeploymentConfig approves the pending image updates of the deploymentConfig with the given ID.
+func (c *Client) ApproveDeploymentConfig(ctx kapi.Context, id string) (result *deployapi.DeploymentConfig, err error) {
+	result = &deployapi.DeploymentConfig{}
+	approval := &deployapi.DeploymentConfigApproval{TypeMeta: kapi.TypeMeta{ID: id}}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigApprovals").Body(approval).Do().Into(result)
+	return
+}
+
+// ScaleDeploymentConfig sets the number of replicas of the deploymentConfig with the given ID and of its active replication controller.
+func (c *Client) ScaleDeploymentConfig(ctx kapi.Context, id string, replicas int) (result *deployapi.DeploymentConfig, err error) {
+	result = &deployapi.DeploymentConfig{}
+	scale := &deployapi.DeploymentConfigScale{TypeMeta: kapi.TypeMeta{ID: id}, Replicas: replicas}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigScales").Body(scale).Do().Into(result)
+	return
+}
+
+// GetDeploymentConfigHistory returns the deployment history of the deploymentConfig with the given ID.
+func (c *Client) GetDeploymentConfigHistory(ctx kapi.Context, id string) (result *deployapi.DeploymentConfigHistory, err error) {
+	result = &deployapi.DeploymentConfigHistory{}
+	err = c.Get().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigHistories").Path(id).Do().Into(result)
+	return
+}
+
+// ListDeploymentConfigAutoscalers takes a selector, and returns the list of deploymentConfigAutoscalers that match that selector
+func (c *Client) ListDeploymentConfigAutoscalers(ctx kapi.Context, selector labels.Selector) (result *deployapi.DeploymentConfigAutoscalerList, err error) {
+	result = &deployapi.DeploymentConfigAutoscalerList{}
+	err = c.Get().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigAutoscalers").SelectorParam("labels", selector).Do().Into(result)
+	return
+}
+
+// WatchDeploymentConfigAutoscalers returns a watch.Interface that watches the requested deploymentConfigAutoscalers.
+func (c *Client) WatchDeploymentConfigAutoscalers(ctx kapi.Context, field, label labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.Get().
+		Namespace(kapi.Namespace(ctx)).
+		Path("watch").
+		Path("deploymentConfigAutoscalers").
+		Param("resourceVersion", resourceVersion).
+		SelectorParam("labels", label).
+		SelectorParam("fields", field).
+		Watch()
+}
+
+// GetDeploymentConfigAutoscaler returns information about a particular deploymentConfigAutoscaler
+func (c *Client) GetDeploymentConfigAutoscaler(ctx kapi.Context, id string) (result *deployapi.DeploymentConfigAutoscaler, err error) {
+	result = &deployapi.DeploymentConfigAutoscaler{}
+	err = c.Get().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigAutoscalers").Path(id).Do().Into(result)
+	return
+}
+
+// CreateDeploymentConfigAutoscaler creates a new deploymentConfigAutoscaler
+func (c *Client) CreateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (result *deployapi.DeploymentConfigAutoscaler, err error) {
+	result = &deployapi.DeploymentConfigAutoscaler{}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigAutoscalers").Body(autoscaler).Do().Into(result)
+	return
+}
+
+// UpdateDeploymentConfigAutoscaler updates an existing deploymentConfigAutoscaler
+func (c *Client) UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (result *deployapi.DeploymentConfigAutoscaler, err error) {
+	result = &deployapi.DeploymentConfigAutoscaler{}
+	err = c.Put().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigAutoscalers").Path(autoscaler.ID).Body(autoscaler).Do().Into(result)
+	return
+}
+
+// DeleteDeploymentConfigAutoscaler deletes an existing deploymentConfigAutoscaler.
+func (c *Client) DeleteDeploymentConfigAutoscaler(ctx kapi.Context, id string) error {
+	return c.Delete().Namespace(kapi.Namespace(ctx)).Path("deploymentConfigAutoscalers").Path(id).Do().Error()
+}
+
 // ListDeployments takes a selector, and returns the list of deployments that match that selector
 func (c *Client) ListDeployments(ctx kapi.Context, selector labels.Selector) (result *deployapi.DeploymentList, err error) {
 	result = &deployapi.DeploymentList{}
@@ -373,6 +503,23 @@ func (c *Client) WatchDeployments(ctx kapi.Context, field, label labels.Selector
 		Watch()
 }
 
+// PromoteDeployment scales the canary deployment with the given ID to all of its replicas and removes previous deployments.
+func (c *Client) PromoteDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error) {
+	return c.decideCanary(ctx, id, deployapi.CanaryActionPromote)
+}
+
+// AbortDeployment scales the canary deployment with the given ID to zero and restores the previous deployment.
+func (c *Client) AbortDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error) {
+	return c.decideCanary(ctx, id, deployapi.CanaryActionAbort)
+}
+
+func (c *Client) decideCanary(ctx kapi.Context, id string, action deployapi.CanaryAction) (result *deployapi.Deployment, err error) {
+	result = &deployapi.Deployment{}
+	decision := &deployapi.DeploymentCanaryDecision{TypeMeta: kapi.TypeMeta{ID: id}, Action: action}
+	err = c.Post().Namespace(kapi.Namespace(ctx)).Path("deploymentCanaryDecisions").Body(decision).Do().Into(result)
+	return
+}
+
 // ListRoutes takes a selector, and returns the list of routes that match that selector
 func (c *Client) ListRoutes(ctx kapi.Context, selector labels.Selector) (result *routeapi.RouteList, err error) {
 	result = &routeapi.RouteList{}
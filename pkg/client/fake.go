@@ -100,7 +100,22 @@ func (c *Fake) CreateImage(ctx kapi.Context, image *imageapi.Image) (*imageapi.I
 	return &imageapi.Image{}, nil
 }
 
-func (c *Fake) ListImageRepositories(ctx kapi.Context, selector labels.Selector) (*imageapi.ImageRepositoryList, error) {
+func (c *Fake) PruneImages(ctx kapi.Context, prune *imageapi.ImagePrune) (*imageapi.ImagePrune, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "prune-images", Ctx: ctx, Value: prune})
+	return &imageapi.ImagePrune{}, nil
+}
+
+func (c *Fake) CreateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (*imageapi.Image, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "create-imagesignature", Ctx: ctx, Value: signature})
+	return &imageapi.Image{}, nil
+}
+
+func (c *Fake) UpdateImageSignature(ctx kapi.Context, signature *imageapi.ImageSignature) (*imageapi.Image, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "update-imagesignature", Ctx: ctx, Value: signature})
+	return &imageapi.Image{}, nil
+}
+
+func (c *Fake) ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (*imageapi.ImageRepositoryList, error) {
 	c.Actions = append(c.Actions, FakeAction{Action: "list-imagerepositries", Ctx: ctx})
 	return &imageapi.ImageRepositoryList{}, nil
 }
@@ -125,6 +140,11 @@ func (c *Fake) UpdateImageRepository(ctx kapi.Context, repo *imageapi.ImageRepos
 	return &imageapi.ImageRepository{}, nil
 }
 
+func (c *Fake) TagImageRepository(ctx kapi.Context, tag *imageapi.ImageRepositoryTag) (*imageapi.ImageRepository, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "tag-imagerepository", Ctx: ctx, Value: tag})
+	return &imageapi.ImageRepository{}, nil
+}
+
 func (c *Fake) CreateImageRepositoryMapping(ctx kapi.Context, mapping *imageapi.ImageRepositoryMapping) error {
 	c.Actions = append(c.Actions, FakeAction{Action: "create-imagerepository-mapping", Ctx: ctx})
 	return nil
@@ -160,6 +180,56 @@ func (c *Fake) GenerateDeploymentConfig(ctx kapi.Context, id string) (*deployapi
 	return nil, nil
 }
 
+func (c *Fake) DiffDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfigDiff, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "diff-deploymentconfig"})
+	return &deployapi.DeploymentConfigDiff{}, nil
+}
+
+func (c *Fake) ApproveDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "approve-deploymentconfig", Value: id})
+	return &deployapi.DeploymentConfig{}, nil
+}
+
+func (c *Fake) GetDeploymentConfigHistory(ctx kapi.Context, id string) (*deployapi.DeploymentConfigHistory, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "get-deploymentconfighistory", Value: id})
+	return &deployapi.DeploymentConfigHistory{}, nil
+}
+
+func (c *Fake) ScaleDeploymentConfig(ctx kapi.Context, id string, replicas int) (*deployapi.DeploymentConfig, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "scale-deploymentconfig", Value: &deployapi.DeploymentConfigScale{TypeMeta: kapi.TypeMeta{ID: id}, Replicas: replicas}})
+	return &deployapi.DeploymentConfig{}, nil
+}
+
+func (c *Fake) ListDeploymentConfigAutoscalers(ctx kapi.Context, selector labels.Selector) (*deployapi.DeploymentConfigAutoscalerList, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "list-deploymentconfigautoscaler", Ctx: ctx})
+	return &deployapi.DeploymentConfigAutoscalerList{}, nil
+}
+
+func (c *Fake) WatchDeploymentConfigAutoscalers(ctx kapi.Context, field, label labels.Selector, resourceVersion string) (watch.Interface, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "watch-deploymentconfigautoscaler"})
+	return nil, nil
+}
+
+func (c *Fake) GetDeploymentConfigAutoscaler(ctx kapi.Context, id string) (*deployapi.DeploymentConfigAutoscaler, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "get-deploymentconfigautoscaler", Ctx: ctx, Value: id})
+	return &deployapi.DeploymentConfigAutoscaler{}, nil
+}
+
+func (c *Fake) CreateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "create-deploymentconfigautoscaler", Ctx: ctx})
+	return &deployapi.DeploymentConfigAutoscaler{}, nil
+}
+
+func (c *Fake) UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "update-deploymentconfigautoscaler", Ctx: ctx})
+	return &deployapi.DeploymentConfigAutoscaler{}, nil
+}
+
+func (c *Fake) DeleteDeploymentConfigAutoscaler(ctx kapi.Context, id string) error {
+	c.Actions = append(c.Actions, FakeAction{Action: "delete-deploymentconfigautoscaler", Ctx: ctx})
+	return nil
+}
+
 func (c *Fake) ListDeployments(ctx kapi.Context, selector labels.Selector) (*deployapi.DeploymentList, error) {
 	c.Actions = append(c.Actions, FakeAction{Action: "list-deployment"})
 	return &deployapi.DeploymentList{}, nil
@@ -190,6 +260,16 @@ func (c *Fake) WatchDeployments(ctx kapi.Context, field, label labels.Selector,
 	return nil, nil
 }
 
+func (c *Fake) PromoteDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "promote-deployment", Value: id})
+	return &deployapi.Deployment{}, nil
+}
+
+func (c *Fake) AbortDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "abort-deployment", Value: id})
+	return &deployapi.Deployment{}, nil
+}
+
 func (c *Fake) ListRoutes(ctx kapi.Context, selector labels.Selector) (*routeapi.RouteList, error) {
 	c.Actions = append(c.Actions, FakeAction{Action: "list-routes"})
 	return &routeapi.RouteList{}, nil
@@ -50,6 +50,8 @@ func NewCommandKubecfg(name string) *cobra.Command {
 	flag.StringVar(&cfg.ClientConfig.KeyFile, "client_key", "", "Path to a client key file for TLS.")
 	flag.BoolVar(&cfg.ClientConfig.Insecure, "insecure_skip_tls_verify", false, "If true, the server's certificate will not be checked for validity. This will make your HTTPS connections insecure.")
 	flag.StringVar(&cfg.ImageName, "image", "", "Image used when updating a replicationController.  Will apply to the first container in the pod template.")
+	flag.BoolVar(&cfg.Confirm, "confirm", false, "If true, pruneImages deletes the images it lists.")
+	flag.DurationVar(&cfg.TagHistoryRetention, "tag_history_retention", 0, "How long pruneImages keeps images a tag pointed to in the past. Defaults to a week on the server.")
 	flag.StringVar(&cfg.ID, "id", "", "Specifies ID of requested resource.")
 	flag.StringVar(&cfg.ns, "ns", "", "If present, the namespace scope for this request.")
 	flag.StringVar(&cfg.nsFile, "ns_file", os.Getenv("HOME")+"/.kubernetes_ns", "Path to the namespace file")
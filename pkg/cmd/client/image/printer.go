@@ -11,6 +11,7 @@ import (
 
 var imageColumns = []string{"ID", "Docker Ref"}
 var imageRepositoryColumns = []string{"ID", "Docker Repo", "Tags"}
+var imagePruneColumns = []string{"Unreferenced Image"}
 
 // RegisterPrintHandlers registers HumanReadablePrinter handlers for image and image repository resources.
 func RegisterPrintHandlers(printer *kubecfg.HumanReadablePrinter) {
@@ -18,6 +19,7 @@ func RegisterPrintHandlers(printer *kubecfg.HumanReadablePrinter) {
 	printer.Handler(imageColumns, printImageList)
 	printer.Handler(imageRepositoryColumns, printImageRepository)
 	printer.Handler(imageRepositoryColumns, printImageRepositoryList)
+	printer.Handler(imagePruneColumns, printImagePrune)
 }
 
 func printImage(image *api.Image, w io.Writer) error {
@@ -55,3 +57,12 @@ func printImageRepositoryList(repos *api.ImageRepositoryList, w io.Writer) error
 	}
 	return nil
 }
+
+func printImagePrune(prune *api.ImagePrune, w io.Writer) error {
+	for _, id := range prune.Images {
+		if _, err := fmt.Fprintf(w, "%s\n", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -35,6 +35,7 @@ import (
 	configapi "github.com/openshift/origin/pkg/config/api"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployclient "github.com/openshift/origin/pkg/deploy/client"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 	projectapi "github.com/openshift/origin/pkg/project/api"
 	routeapi "github.com/openshift/origin/pkg/route/api"
@@ -63,6 +64,9 @@ type KubeConfig struct {
 
 	ImageName string
 
+	Confirm             bool
+	TagHistoryRetention time.Duration
+
 	APIVersion   string
 	OSAPIVersion string
 
@@ -105,24 +109,41 @@ func usage(name string) string {
 
   Retrieve build logs:
   %[1]s [OPTIONS] buildLogs --id="buildID"
+
+  Preview the changes the next deployment of a deployment config would make:
+  %[1]s [OPTIONS] diffDeploymentConfig --id="deploymentConfigID"
+
+  Show the deployment history of a deployment config, or the changes between two of its versions:
+  %[1]s [OPTIONS] deploymentConfigHistory --id="deploymentConfigID" [<from version> <to version>]
+
+  Scale a deployment config and its running replication controller without redeploying it:
+  %[1]s [OPTIONS] scaleDeploymentConfig --id="deploymentConfigID" <replicas>
+
+  Copy a tag of an image repository, optionally in another namespace, to a tag of another:
+  %[1]s [OPTIONS] tagImageRepository --id="imageRepositoryID" <tag> [<namespace>/]<imageRepositoryID>:<tag>
+
+  List the images of a namespace which are no longer referenced, and delete them with --confirm:
+  %[1]s [OPTIONS] pruneImages [--confirm] [--tag_history_retention=<duration>]
 `, name, prettyWireStorage())
 }
 
 var parser = kubecfg.NewParser(map[string]runtime.Object{
-	"pods":                    &api.Pod{},
-	"services":                &api.Service{},
-	"replicationControllers":  &api.ReplicationController{},
-	"minions":                 &api.Minion{},
-	"builds":                  &buildapi.Build{},
-	"buildConfigs":            &buildapi.BuildConfig{},
-	"images":                  &imageapi.Image{},
-	"imageRepositories":       &imageapi.ImageRepository{},
-	"imageRepositoryMappings": &imageapi.ImageRepositoryMapping{},
-	"config":                  &configapi.Config{},
-	"deployments":             &deployapi.Deployment{},
-	"deploymentConfigs":       &deployapi.DeploymentConfig{},
-	"routes":                  &routeapi.Route{},
-	"projects":                &projectapi.Project{},
+	"pods":                        &api.Pod{},
+	"services":                    &api.Service{},
+	"replicationControllers":      &api.ReplicationController{},
+	"minions":                     &api.Minion{},
+	"builds":                      &buildapi.Build{},
+	"buildConfigs":                &buildapi.BuildConfig{},
+	"images":                      &imageapi.Image{},
+	"imageRepositories":           &imageapi.ImageRepository{},
+	"imageRepositoryMappings":     &imageapi.ImageRepositoryMapping{},
+	"imageSignatures":             &imageapi.ImageSignature{},
+	"config":                      &configapi.Config{},
+	"deployments":                 &deployapi.Deployment{},
+	"deploymentConfigs":           &deployapi.DeploymentConfig{},
+	"deploymentConfigAutoscalers": &deployapi.DeploymentConfigAutoscaler{},
+	"routes":                      &routeapi.Route{},
+	"projects":                    &projectapi.Project{},
 })
 
 func prettyWireStorage() string {
@@ -203,6 +224,37 @@ func (c *KubeConfig) getNamespace() string {
 	return ret
 }
 
+// getPrinter returns the printer selected by the --json, --yaml and template flags, printing human
+// readable output by default.
+func (c *KubeConfig) getPrinter() kubecfg.ResourcePrinter {
+	switch {
+	case c.JSON:
+		return &kubecfg.IdentityPrinter{}
+	case c.YAML:
+		return &kubecfg.YAMLPrinter{}
+	case len(c.TemplateFile) > 0 || len(c.TemplateStr) > 0:
+		var data []byte
+		if len(c.TemplateFile) > 0 {
+			var err error
+			data, err = ioutil.ReadFile(c.TemplateFile)
+			if err != nil {
+				glog.Fatalf("Error reading template %s, %v", c.TemplateFile, err)
+			}
+		} else {
+			data = []byte(c.TemplateStr)
+		}
+		tmpl, err := template.New("output").Parse(string(data))
+		if err != nil {
+			glog.Fatalf("Error parsing template %s, %v", string(data), err)
+		}
+		return &kubecfg.TemplatePrinter{
+			Template: tmpl,
+		}
+	default:
+		return humanReadablePrinter()
+	}
+}
+
 func (c *KubeConfig) Run() {
 	util.InitLogs()
 	defer util.FlushLogs()
@@ -288,22 +340,24 @@ func (c *KubeConfig) Run() {
 
 	method := c.Arg(0)
 	clients := ClientMappings{
-		"minions":                 {"Minion", kubeClient.RESTClient, klatest.Codec},
-		"pods":                    {"Pod", kubeClient.RESTClient, klatest.Codec},
-		"services":                {"Service", kubeClient.RESTClient, klatest.Codec},
-		"replicationControllers":  {"ReplicationController", kubeClient.RESTClient, klatest.Codec},
-		"builds":                  {"Build", client.RESTClient, latest.Codec},
-		"buildConfigs":            {"BuildConfig", client.RESTClient, latest.Codec},
-		"images":                  {"Image", client.RESTClient, latest.Codec},
-		"imageRepositories":       {"ImageRepository", client.RESTClient, latest.Codec},
-		"imageRepositoryMappings": {"ImageRepositoryMapping", client.RESTClient, latest.Codec},
-		"deployments":             {"Deployment", client.RESTClient, latest.Codec},
-		"deploymentConfigs":       {"DeploymentConfig", client.RESTClient, latest.Codec},
-		"routes":                  {"Route", client.RESTClient, latest.Codec},
-		"projects":                {"Project", client.RESTClient, latest.Codec},
-	}
-
-	matchFound := c.executeConfigRequest(method, clients) || c.executeTemplateRequest(method, client) || c.executeBuildLogRequest(method, client) || c.executeControllerRequest(method, kubeClient) || c.executeNamespaceRequest(method) || c.executeAPIRequest(method, clients)
+		"minions":                     {"Minion", kubeClient.RESTClient, klatest.Codec},
+		"pods":                        {"Pod", kubeClient.RESTClient, klatest.Codec},
+		"services":                    {"Service", kubeClient.RESTClient, klatest.Codec},
+		"replicationControllers":      {"ReplicationController", kubeClient.RESTClient, klatest.Codec},
+		"builds":                      {"Build", client.RESTClient, latest.Codec},
+		"buildConfigs":                {"BuildConfig", client.RESTClient, latest.Codec},
+		"images":                      {"Image", client.RESTClient, latest.Codec},
+		"imageRepositories":           {"ImageRepository", client.RESTClient, latest.Codec},
+		"imageRepositoryMappings":     {"ImageRepositoryMapping", client.RESTClient, latest.Codec},
+		"imageSignatures":             {"ImageSignature", client.RESTClient, latest.Codec},
+		"deployments":                 {"Deployment", client.RESTClient, latest.Codec},
+		"deploymentConfigs":           {"DeploymentConfig", client.RESTClient, latest.Codec},
+		"deploymentConfigAutoscalers": {"DeploymentConfigAutoscaler", client.RESTClient, latest.Codec},
+		"routes":                      {"Route", client.RESTClient, latest.Codec},
+		"projects":                    {"Project", client.RESTClient, latest.Codec},
+	}
+
+	matchFound := c.executeConfigRequest(method, clients) || c.executeTemplateRequest(method, client) || c.executeBuildLogRequest(method, client) || c.executeDeploymentConfigDiffRequest(method, client) || c.executeDeploymentConfigHistoryRequest(method, client) || c.executeDeploymentConfigScaleRequest(method, client) || c.executeImageRepositoryTagRequest(method, client) || c.executeImagePruneRequest(method, client) || c.executeControllerRequest(method, kubeClient) || c.executeNamespaceRequest(method) || c.executeAPIRequest(method, clients)
 	if matchFound == false {
 		glog.Fatalf("Unknown command %s", method)
 	}
@@ -414,36 +468,7 @@ func (c *KubeConfig) executeAPIRequest(method string, clients ClientMappings) bo
 		return false
 	}
 
-	var printer kubecfg.ResourcePrinter
-	switch {
-	case c.JSON:
-		printer = &kubecfg.IdentityPrinter{}
-	case c.YAML:
-		printer = &kubecfg.YAMLPrinter{}
-	case len(c.TemplateFile) > 0 || len(c.TemplateStr) > 0:
-		var data []byte
-		if len(c.TemplateFile) > 0 {
-			var err error
-			data, err = ioutil.ReadFile(c.TemplateFile)
-			if err != nil {
-				glog.Fatalf("Error reading template %s, %v", c.TemplateFile, err)
-				return false
-			}
-		} else {
-			data = []byte(c.TemplateStr)
-		}
-		tmpl, err := template.New("output").Parse(string(data))
-		if err != nil {
-			glog.Fatalf("Error parsing template %s, %v", string(data), err)
-			return false
-		}
-		printer = &kubecfg.TemplatePrinter{
-			Template: tmpl,
-		}
-	default:
-		printer = humanReadablePrinter()
-	}
-
+	printer := c.getPrinter()
 	if err = printer.PrintObj(obj, os.Stdout); err != nil {
 		body, _ := result.Raw()
 		glog.Fatalf("Failed to print: %v\nRaw received object:\n%#v\n\nBody received: %v", err, obj, string(body))
@@ -521,6 +546,182 @@ func (c *KubeConfig) executeBuildLogRequest(method string, client *osclient.Clie
 	return true
 }
 
+// executeDeploymentConfigDiffRequest prints the changes the next deployment of a deployment config
+// would make to its current deployment
+func (c *KubeConfig) executeDeploymentConfigDiffRequest(method string, client *osclient.Client) bool {
+	if method != "diffDeploymentConfig" {
+		return false
+	}
+	if len(c.ID) == 0 {
+		glog.Fatal("DeploymentConfig ID required")
+	}
+	ctx := api.WithNamespace(api.NewContext(), c.getNamespace())
+	diff, err := client.DiffDeploymentConfig(ctx, c.ID)
+	if err != nil {
+		glog.Fatalf("Error: %v", err)
+	}
+
+	if err := c.getPrinter().PrintObj(diff, os.Stdout); err != nil {
+		glog.Fatalf("Failed to print: %v", err)
+	}
+	return true
+}
+
+// executeDeploymentConfigHistoryRequest prints the deployment history of a deployment config. If two
+// versions are given, the changes between the pod templates of their deployments are printed instead.
+func (c *KubeConfig) executeDeploymentConfigHistoryRequest(method string, client *osclient.Client) bool {
+	if method != "deploymentConfigHistory" {
+		return false
+	}
+	if len(c.ID) == 0 {
+		glog.Fatal("DeploymentConfig ID required")
+	}
+	ctx := api.WithNamespace(api.NewContext(), c.getNamespace())
+	history, err := client.GetDeploymentConfigHistory(ctx, c.ID)
+	if err != nil {
+		glog.Fatalf("Error: %v", err)
+	}
+
+	var obj runtime.Object = history
+	if len(c.Args) > 1 {
+		if len(c.Args) != 3 {
+			glog.Fatal("Both a from and a to version are required")
+		}
+		from, to := historyRevision(history, c.Arg(1)), historyRevision(history, c.Arg(2))
+		obj = &deployapi.DeploymentConfigDiff{
+			TypeMeta:      history.TypeMeta,
+			DeploymentID:  from.Deployment.ID,
+			LatestVersion: to.Version,
+			Changes:       deployutil.DiffPodTemplates(from.Deployment.ControllerTemplate.PodTemplate, to.Deployment.ControllerTemplate.PodTemplate),
+		}
+	}
+
+	if err := c.getPrinter().PrintObj(obj, os.Stdout); err != nil {
+		glog.Fatalf("Failed to print: %v", err)
+	}
+	return true
+}
+
+// executeDeploymentConfigScaleRequest sets the number of replicas of a deployment config and of its
+// running replication controller, and prints the updated deployment config.
+func (c *KubeConfig) executeDeploymentConfigScaleRequest(method string, client *osclient.Client) bool {
+	if method != "scaleDeploymentConfig" {
+		return false
+	}
+	if len(c.ID) == 0 {
+		glog.Fatal("DeploymentConfig ID required")
+	}
+	if len(c.Args) != 2 {
+		glog.Fatal("usage: kubecfg scaleDeploymentConfig --id=<deploymentConfigID> <replicas>")
+	}
+	replicas, err := strconv.Atoi(c.Arg(1))
+	if err != nil {
+		glog.Fatalf("Error parsing replicas: %v", err)
+	}
+	ctx := api.WithNamespace(api.NewContext(), c.getNamespace())
+	config, err := client.ScaleDeploymentConfig(ctx, c.ID, replicas)
+	if err != nil {
+		glog.Fatalf("Error: %v", err)
+	}
+
+	if err := c.getPrinter().PrintObj(config, os.Stdout); err != nil {
+		glog.Fatalf("Failed to print: %v", err)
+	}
+	return true
+}
+
+// executeImageRepositoryTagRequest sets a tag of an image repository to the image a tag of another
+// image repository points to, and prints the updated image repository.
+func (c *KubeConfig) executeImageRepositoryTagRequest(method string, client *osclient.Client) bool {
+	if method != "tagImageRepository" {
+		return false
+	}
+	if len(c.ID) == 0 {
+		glog.Fatal("ImageRepository ID required")
+	}
+	if len(c.Args) != 3 {
+		glog.Fatal("usage: kubecfg tagImageRepository --id=<imageRepositoryID> <tag> [<namespace>/]<imageRepositoryID>:<tag>")
+	}
+	from, err := parseTagReference(c.Arg(2))
+	if err != nil {
+		glog.Fatalf("Error: %v", err)
+	}
+	ctx := api.WithNamespace(api.NewContext(), c.getNamespace())
+	repo, err := client.TagImageRepository(ctx, &imageapi.ImageRepositoryTag{
+		TypeMeta: api.TypeMeta{ID: c.ID},
+		Tag:      c.Arg(1),
+		From:     from,
+	})
+	if err != nil {
+		glog.Fatalf("Error: %v", err)
+	}
+
+	if err := c.getPrinter().PrintObj(repo, os.Stdout); err != nil {
+		glog.Fatalf("Failed to print: %v", err)
+	}
+	return true
+}
+
+// executeImagePruneRequest prints the images of the namespace which are no longer referenced, deleting
+// them if --confirm is set.
+func (c *KubeConfig) executeImagePruneRequest(method string, client *osclient.Client) bool {
+	if method != "pruneImages" {
+		return false
+	}
+	if len(c.Args) != 1 {
+		glog.Fatal("usage: kubecfg pruneImages [--confirm] [--tag_history_retention=<duration>]")
+	}
+	ctx := api.WithNamespace(api.NewContext(), c.getNamespace())
+	prune, err := client.PruneImages(ctx, &imageapi.ImagePrune{
+		Confirm:                    c.Confirm,
+		TagHistoryRetentionSeconds: int64(c.TagHistoryRetention / time.Second),
+	})
+	if err != nil {
+		glog.Fatalf("Error: %v", err)
+	}
+
+	printer := c.getPrinter()
+	if err := printer.PrintObj(prune, os.Stdout); err != nil {
+		glog.Fatalf("Failed to print: %v", err)
+	}
+	if _, ok := printer.(*kubecfg.HumanReadablePrinter); !c.Confirm && ok {
+		fmt.Printf("\n%d image(s) can be pruned, rerun with --confirm to delete them\n", len(prune.Images))
+	}
+	return true
+}
+
+// parseTagReference parses a reference of the form [<namespace>/]<imageRepositoryID>:<tag>.
+func parseTagReference(s string) (imageapi.TagReference, error) {
+	ref := imageapi.TagReference{}
+	i := strings.LastIndex(s, ":")
+	if i == -1 {
+		return ref, fmt.Errorf("invalid tag reference %q, expected [<namespace>/]<imageRepositoryID>:<tag>", s)
+	}
+	ref.ImageRepository, ref.Tag = s[:i], s[i+1:]
+	if j := strings.Index(ref.ImageRepository, "/"); j != -1 {
+		ref.Namespace, ref.ImageRepository = ref.ImageRepository[:j], ref.ImageRepository[j+1:]
+	}
+	if len(ref.ImageRepository) == 0 || len(ref.Tag) == 0 || strings.Contains(ref.ImageRepository, "/") {
+		return ref, fmt.Errorf("invalid tag reference %q, expected [<namespace>/]<imageRepositoryID>:<tag>", s)
+	}
+	return ref, nil
+}
+
+// historyRevision returns the revision of the history with the given version or exits.
+func historyRevision(history *deployapi.DeploymentConfigHistory, version string) *deployapi.DeploymentRevision {
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		glog.Fatalf("Invalid version %q: %v", version, err)
+	}
+	for i := range history.Revisions {
+		if history.Revisions[i].Version == v {
+			return &history.Revisions[i]
+		}
+	}
+	glog.Fatalf("No deployment of version %d exists for deploymentConfig %s", v, history.ID)
+	return nil
+}
+
 // executeTemplateRequest transform the JSON file with Config template into a
 // valid Config JSON.
 //
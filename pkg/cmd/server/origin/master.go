@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,13 +37,21 @@ import (
 	cmdutil "github.com/openshift/origin/pkg/cmd/util"
 	deploycontrollerfactory "github.com/openshift/origin/pkg/deploy/controller/factory"
 	deployconfiggenerator "github.com/openshift/origin/pkg/deploy/generator"
+	deploymetrics "github.com/openshift/origin/pkg/deploy/metrics"
+	autoscalerregistry "github.com/openshift/origin/pkg/deploy/registry/autoscaler"
 	deployregistry "github.com/openshift/origin/pkg/deploy/registry/deploy"
 	deployconfigregistry "github.com/openshift/origin/pkg/deploy/registry/deployconfig"
 	deployetcd "github.com/openshift/origin/pkg/deploy/registry/etcd"
+	"github.com/openshift/origin/pkg/dockerregistry"
+	imagecontrollerfactory "github.com/openshift/origin/pkg/image/controller/factory"
 	imageetcd "github.com/openshift/origin/pkg/image/registry/etcd"
 	"github.com/openshift/origin/pkg/image/registry/image"
+	"github.com/openshift/origin/pkg/image/registry/imageprune"
 	"github.com/openshift/origin/pkg/image/registry/imagerepository"
 	"github.com/openshift/origin/pkg/image/registry/imagerepositorymapping"
+	"github.com/openshift/origin/pkg/image/registry/imagerepositorytag"
+	"github.com/openshift/origin/pkg/image/registry/imagesignature"
+	imagewebhook "github.com/openshift/origin/pkg/image/webhook"
 	accesstokenregistry "github.com/openshift/origin/pkg/oauth/registry/accesstoken"
 	authorizetokenregistry "github.com/openshift/origin/pkg/oauth/registry/authorizetoken"
 	clientregistry "github.com/openshift/origin/pkg/oauth/registry/client"
@@ -77,6 +86,9 @@ type MasterConfig struct {
 
 	KubeClient *kclient.Client
 	OSClient   *osclient.Client
+
+	// deployCaches are shared by the deployment controllers.
+	deployCaches *deploycontrollerfactory.SharedCaches
 }
 
 // APIInstaller installs additional API components into this server
@@ -130,6 +142,33 @@ func (c *MasterConfig) RunAPI(installers ...APIInstaller) {
 		DeploymentInterface:       deployEtcd,
 		DeploymentConfigInterface: deployEtcd,
 		ImageRepositoryInterface:  imageEtcd,
+		ImageInterface:            imageEtcd,
+	}
+
+	deployCanceller := &deployregistry.DeploymentCanceller{
+		Registry:                    deployEtcd,
+		PodClient:                   c.KubeClient,
+		ReplicationControllerClient: c.KubeClient,
+	}
+
+	// refuse to deploy images without a verified signature if required
+	var deployImagePolicy deployregistry.ImagePolicy
+	requireSignedImages, err := strconv.ParseBool(env("OPENSHIFT_REQUIRE_SIGNED_IMAGES", "false"))
+	if err != nil {
+		glog.Fatalf("Invalid OPENSHIFT_REQUIRE_SIGNED_IMAGES: %v", err)
+	}
+	if requireSignedImages {
+		deployImagePolicy = &deployregistry.SignedImagePolicy{Images: imageEtcd, Repositories: imageEtcd}
+	}
+
+	// verify image signatures with the keys of trusted signers, if any
+	var signatureVerifier imagesignature.Verifier
+	if dir := env("OPENSHIFT_IMAGE_SIGNER_KEYS_DIR", ""); len(dir) > 0 {
+		keyring, err := imagesignature.LoadKeyring(dir)
+		if err != nil {
+			glog.Fatalf("Unable to load the keys of image signers from %s: %v", dir, err)
+		}
+		signatureVerifier = keyring
 	}
 
 	// initialize OpenShift API
@@ -141,10 +180,19 @@ func (c *MasterConfig) RunAPI(installers ...APIInstaller) {
 		"images":                  image.NewREST(imageEtcd),
 		"imageRepositories":       imagerepository.NewREST(imageEtcd),
 		"imageRepositoryMappings": imagerepositorymapping.NewREST(imageEtcd, imageEtcd),
-
-		"deployments":               deployregistry.NewREST(deployEtcd),
-		"deploymentConfigs":         deployconfigregistry.NewREST(deployEtcd),
-		"generateDeploymentConfigs": deployconfiggenerator.NewREST(deployConfigGenerator, v1beta1.Codec),
+		"imageRepositoryTags":     imagerepositorytag.NewREST(imageEtcd),
+		"imagePrunes":             imageprune.NewREST(imageEtcd, imageEtcd, c.KubeClient),
+		"imageSignatures":         imagesignature.NewREST(imageEtcd, signatureVerifier),
+
+		"deployments":                 deployregistry.NewREST(deployEtcd, deployCanceller, deployImagePolicy),
+		"deploymentCanaryDecisions":   deployregistry.NewCanaryREST(deployEtcd, c.KubeClient),
+		"deploymentConfigs":           deployconfigregistry.NewREST(deployEtcd),
+		"generateDeploymentConfigs":   deployconfiggenerator.NewREST(deployConfigGenerator, v1beta1.Codec),
+		"deploymentConfigDiffs":       deployconfiggenerator.NewDiffREST(deployConfigGenerator),
+		"deploymentConfigApprovals":   deployconfigregistry.NewApproveREST(deployEtcd, deployConfigGenerator),
+		"deploymentConfigHistories":   deployconfigregistry.NewHistoryREST(deployEtcd, deployEtcd),
+		"deploymentConfigScales":      deployconfigregistry.NewScaleREST(deployEtcd, deployEtcd, c.KubeClient),
+		"deploymentConfigAutoscalers": autoscalerregistry.NewREST(deployEtcd),
 
 		"templateConfigs": templateregistry.NewREST(),
 
@@ -169,6 +217,13 @@ func (c *MasterConfig) RunAPI(installers ...APIInstaller) {
 			"github": github.New(),
 		})))
 
+	// registries POST push notifications to <pushPrefix><OPENSHIFT_PUSH_NOTIFICATION_SECRET>, and the
+	// metadata of pushed images is only read from the registries in OPENSHIFT_PUSH_NOTIFICATION_REGISTRIES
+	pushPrefix := OpenShiftAPIPrefixV1Beta1 + "/registryNotifications/"
+	osMux.Handle(pushPrefix, http.StripPrefix(pushPrefix,
+		imagewebhook.NewController(c.OSClient, dockerregistry.NewClient(insecureRegistries()...),
+			env("OPENSHIFT_PUSH_NOTIFICATION_SECRET", ""), registryList("OPENSHIFT_PUSH_NOTIFICATION_REGISTRIES")...)))
+
 	var extra []string
 	for _, i := range installers {
 		extra = append(extra, i.InstallAPI(osMux)...)
@@ -268,6 +323,7 @@ func (c *MasterConfig) RunBuildController() {
 	factory := buildcontrollerfactory.BuildControllerFactory{
 		Client:     c.OSClient,
 		KubeClient: c.KubeClient,
+		Builds:     buildcontrollerfactory.NewBuildCache(c.OSClient),
 		DockerBuildStrategy: &buildstrategy.DockerBuildStrategy{
 			BuilderImage:   dockerBuilderImage,
 			UseLocalImages: useLocalImages,
@@ -277,6 +333,7 @@ func (c *MasterConfig) RunBuildController() {
 			TempDirectoryCreator: buildstrategy.STITempDirectoryCreator,
 			UseLocalImages:       useLocalImages,
 		},
+		ImageSignatures: imagesignature.NewProvenanceRecorder(imageetcd.New(c.EtcdHelper)),
 	}
 
 	controller := factory.Create()
@@ -288,11 +345,17 @@ func (c *MasterConfig) RunCustomPodDeploymentController() {
 	factory := deploycontrollerfactory.CustomPodDeploymentControllerFactory{
 		Client:     c.OSClient,
 		KubeClient: c.KubeClient,
+		Caches:     c.ensureDeployCaches(),
 		Environment: []api.EnvVar{
 			{Name: "KUBERNETES_MASTER", Value: c.MasterAddr},
 		},
 		DefaultImage:   env("OPENSHIFT_DEPLOY_CUSTOMPOD_DEFAULT_IMAGE", "openshift/origin-deployer"),
 		UseLocalImages: env("USE_LOCAL_IMAGES", "true") == "true",
+		TokenIssuer: &accesstokenregistry.NamespaceTokenIssuer{
+			Registry:  oauthetcd.New(c.EtcdHelper),
+			UserName:  "system:openshift-deployer",
+			ExpiresIn: int64(time.Hour / time.Second),
+		},
 	}
 
 	controller := factory.Create()
@@ -303,6 +366,20 @@ func (c *MasterConfig) RunBasicDeploymentController() {
 	factory := deploycontrollerfactory.BasicDeploymentControllerFactory{
 		Client:     c.OSClient,
 		KubeClient: c.KubeClient,
+		Caches:     c.ensureDeployCaches(),
+	}
+
+	controller := factory.Create()
+	controller.Run()
+}
+
+func (c *MasterConfig) RunRecreateDeploymentController() {
+	factory := deploycontrollerfactory.RecreateDeploymentControllerFactory{
+		Client:       c.OSClient,
+		KubeClient:   c.KubeClient,
+		Caches:       c.ensureDeployCaches(),
+		PollInterval: 1 * time.Second,
+		Timeout:      2 * time.Minute,
 	}
 
 	controller := factory.Create()
@@ -310,23 +387,71 @@ func (c *MasterConfig) RunBasicDeploymentController() {
 }
 
 func (c *MasterConfig) RunDeploymentConfigController() {
-	factory := deploycontrollerfactory.DeploymentConfigControllerFactory{c.OSClient}
+	factory := deploycontrollerfactory.DeploymentConfigControllerFactory{Client: c.OSClient, Caches: c.ensureDeployCaches()}
 	controller := factory.Create()
 	controller.Run()
 }
 
 func (c *MasterConfig) RunDeploymentConfigChangeController() {
-	factory := deploycontrollerfactory.DeploymentConfigChangeControllerFactory{c.OSClient}
+	factory := deploycontrollerfactory.DeploymentConfigChangeControllerFactory{Client: c.OSClient, Caches: c.ensureDeployCaches()}
 	controller := factory.Create()
 	controller.Run()
 }
 
 func (c *MasterConfig) RunDeploymentImageChangeTriggerController() {
-	factory := deploycontrollerfactory.ImageChangeControllerFactory{c.OSClient}
+	factory := deploycontrollerfactory.ImageChangeControllerFactory{Client: c.OSClient, Caches: c.ensureDeployCaches()}
+	controller := factory.Create()
+	controller.Run()
+}
+
+// RunDeploymentConfigAutoscalerController starts the autoscaler controller if a metrics source is
+// configured with OPENSHIFT_AUTOSCALER_METRICS_URL.
+func (c *MasterConfig) RunDeploymentConfigAutoscalerController() {
+	metricsURL := env("OPENSHIFT_AUTOSCALER_METRICS_URL", "")
+	if len(metricsURL) == 0 {
+		glog.Infof("No metrics source configured, not starting the deployment config autoscaler controller")
+		return
+	}
+	factory := deploycontrollerfactory.DeploymentConfigAutoscalerControllerFactory{
+		Client:   c.OSClient,
+		Metrics:  deploymetrics.NewHTTPSource(metricsURL),
+		Interval: 30 * time.Second,
+	}
+	controller := factory.Create()
+	controller.Run()
+}
+
+// RunImageImportController starts the image import controller, which imports the tags of the image
+// repositories marked for import every OPENSHIFT_IMAGE_IMPORT_INTERVAL (5m by default).
+func (c *MasterConfig) RunImageImportController() {
+	interval, err := time.ParseDuration(env("OPENSHIFT_IMAGE_IMPORT_INTERVAL", "5m"))
+	if err != nil {
+		glog.Fatalf("Invalid OPENSHIFT_IMAGE_IMPORT_INTERVAL: %v", err)
+	}
+	factory := imagecontrollerfactory.ImportControllerFactory{
+		Client:             c.OSClient,
+		Interval:           interval,
+		InsecureRegistries: insecureRegistries(),
+	}
 	controller := factory.Create()
 	controller.Run()
 }
 
+// RunTagAliasController starts the image tag alias controller.
+func (c *MasterConfig) RunTagAliasController() {
+	factory := imagecontrollerfactory.TagAliasControllerFactory{Client: c.OSClient}
+	controller := factory.Create()
+	controller.Run()
+}
+
+// ensureDeployCaches returns the caches shared by the deployment controllers, creating them on first use.
+func (c *MasterConfig) ensureDeployCaches() *deploycontrollerfactory.SharedCaches {
+	if c.deployCaches == nil {
+		c.deployCaches = deploycontrollerfactory.NewSharedCaches(c.OSClient)
+	}
+	return c.deployCaches
+}
+
 // NewEtcdHelper returns an EtcdHelper for the provided arguments or an error if the version
 // is incorrect.
 func NewEtcdHelper(version string, client *etcdclient.Client) (helper tools.EtcdHelper, err error) {
@@ -340,6 +465,23 @@ func NewEtcdHelper(version string, client *etcdclient.Client) (helper tools.Etcd
 	return tools.EtcdHelper{client, interfaces.Codec, tools.RuntimeVersionAdapter{interfaces.ResourceVersioner}}, nil
 }
 
+// insecureRegistries returns the Docker registries listed in OPENSHIFT_INSECURE_REGISTRIES, which are
+// contacted over plain HTTP rather than HTTPS.
+func insecureRegistries() []string {
+	return registryList("OPENSHIFT_INSECURE_REGISTRIES")
+}
+
+// registryList returns the Docker registries listed in the environment variable key, separated by commas.
+func registryList(key string) []string {
+	registries := []string{}
+	for _, registry := range strings.Split(env(key, ""), ",") {
+		if registry = strings.TrimSpace(registry); len(registry) > 0 {
+			registries = append(registries, registry)
+		}
+	}
+	return registries
+}
+
 // env returns an environment variable, or the defaultValue if it is not set.
 func env(key string, defaultValue string) string {
 	val := os.Getenv(key)
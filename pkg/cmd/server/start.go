@@ -219,9 +219,13 @@ func NewCommandStartServer(name string) *cobra.Command {
 				osmaster.RunBuildController()
 				osmaster.RunDeploymentConfigController()
 				osmaster.RunBasicDeploymentController()
+				osmaster.RunRecreateDeploymentController()
 				osmaster.RunCustomPodDeploymentController()
 				osmaster.RunDeploymentConfigChangeController()
 				osmaster.RunDeploymentImageChangeTriggerController()
+				osmaster.RunDeploymentConfigAutoscalerController()
+				osmaster.RunImageImportController()
+				osmaster.RunTagAliasController()
 			}
 
 			if startNode {
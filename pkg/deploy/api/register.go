@@ -10,10 +10,24 @@ func init() {
 		&DeploymentList{},
 		&DeploymentConfig{},
 		&DeploymentConfigList{},
+		&DeploymentConfigDiff{},
+		&DeploymentConfigHistory{},
+		&DeploymentConfigApproval{},
+		&DeploymentConfigScale{},
+		&DeploymentConfigAutoscaler{},
+		&DeploymentConfigAutoscalerList{},
+		&DeploymentCanaryDecision{},
 	)
 }
 
-func (*Deployment) IsAnAPIObject()           {}
-func (*DeploymentList) IsAnAPIObject()       {}
-func (*DeploymentConfig) IsAnAPIObject()     {}
-func (*DeploymentConfigList) IsAnAPIObject() {}
+func (*Deployment) IsAnAPIObject()                     {}
+func (*DeploymentList) IsAnAPIObject()                 {}
+func (*DeploymentConfig) IsAnAPIObject()               {}
+func (*DeploymentConfigList) IsAnAPIObject()           {}
+func (*DeploymentConfigDiff) IsAnAPIObject()           {}
+func (*DeploymentConfigHistory) IsAnAPIObject()        {}
+func (*DeploymentConfigApproval) IsAnAPIObject()       {}
+func (*DeploymentConfigScale) IsAnAPIObject()          {}
+func (*DeploymentConfigAutoscaler) IsAnAPIObject()     {}
+func (*DeploymentConfigAutoscalerList) IsAnAPIObject() {}
+func (*DeploymentCanaryDecision) IsAnAPIObject()       {}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // A deployment represents a single configuration of a pod deployed into the cluster, and may
@@ -19,8 +20,64 @@ type Deployment struct {
 	// If no trigger is specified here, then the deployment was likely created as a result of an
 	// explicit client request to create a new deployment resource.
 	Details *DeploymentDetails `json:"details,omitempty" yaml:"details,omitempty"`
+	// StatusMessage is a human readable explanation of the current Status. It is populated by the
+	// deployment strategy, typically with the reason for a failure.
+	StatusMessage string `json:"statusMessage,omitempty" yaml:"statusMessage,omitempty"`
+	// StartTimestamp is the time at which a deployment strategy began acting on the deployment.
+	StartTimestamp util.Time `json:"startTimestamp,omitempty" yaml:"startTimestamp,omitempty"`
+	// CompletionTimestamp is the time at which the deployment reached a terminal status.
+	CompletionTimestamp util.Time `json:"completionTimestamp,omitempty" yaml:"completionTimestamp,omitempty"`
+	// Events is the ordered list of steps performed by the deployment strategy.
+	Events []DeploymentEvent `json:"events,omitempty" yaml:"events,omitempty"`
+	// Images records the exact image deployed to each container updated by an image change trigger.
+	Images []DeploymentImage `json:"images,omitempty" yaml:"images,omitempty"`
 }
 
+// DeploymentImage identifies the image deployed to a container.
+type DeploymentImage struct {
+	// ContainerName is the name of the container in the pod template.
+	ContainerName string `json:"containerName,omitempty" yaml:"containerName,omitempty"`
+	// ImageID is the ID of the Image the trigger's tag referred to when the deployment was created.
+	ImageID string `json:"imageID,omitempty" yaml:"imageID,omitempty"`
+	// DockerImageReference is the exact pull spec of the image given to the container.
+	DockerImageReference string `json:"dockerImageReference,omitempty" yaml:"dockerImageReference,omitempty"`
+}
+
+// DeploymentEvent records a single step performed by a deployment strategy.
+type DeploymentEvent struct {
+	// Type identifies the kind of step which was performed.
+	Type DeploymentEventType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Message is a human readable description of the step.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Timestamp is the time at which the step was performed.
+	Timestamp util.Time `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+}
+
+// DeploymentEventType describes a kind of step performed by a deployment strategy.
+type DeploymentEventType string
+
+const (
+	// DeploymentEventReplicationControllerCreated means a replication controller was created for the deployment.
+	DeploymentEventReplicationControllerCreated DeploymentEventType = "ReplicationControllerCreated"
+	// DeploymentEventReplicationControllerScaled means the replica count of a replication controller was changed.
+	DeploymentEventReplicationControllerScaled DeploymentEventType = "ReplicationControllerScaled"
+	// DeploymentEventReplicationControllerDeleted means a replication controller from a previous deployment
+	// was removed.
+	DeploymentEventReplicationControllerDeleted DeploymentEventType = "ReplicationControllerDeleted"
+	// DeploymentEventPodCreated means a pod responsible for carrying out the deployment was created.
+	DeploymentEventPodCreated DeploymentEventType = "PodCreated"
+	// DeploymentEventHookExecuted means a hook defined by the deployment strategy was run.
+	DeploymentEventHookExecuted DeploymentEventType = "HookExecuted"
+	// DeploymentEventFailed means a step of the deployment failed.
+	DeploymentEventFailed DeploymentEventType = "Failed"
+	// DeploymentEventCancelled means the deployment was cancelled.
+	DeploymentEventCancelled DeploymentEventType = "Cancelled"
+	// DeploymentEventCanaryPromoted means the canary of the deployment was promoted.
+	DeploymentEventCanaryPromoted DeploymentEventType = "CanaryPromoted"
+	// DeploymentEventCanaryAborted means the canary of the deployment was aborted.
+	DeploymentEventCanaryAborted DeploymentEventType = "CanaryAborted"
+)
+
 // A DeploymentList is a collection of deployments.
 type DeploymentList struct {
 	api.TypeMeta `json:",inline" yaml:",inline"`
@@ -43,19 +100,55 @@ const (
 	DeploymentStatusComplete DeploymentStatus = "Complete"
 	// DeploymentStatusFailed means the deployment finished with an error.
 	DeploymentStatusFailed DeploymentStatus = "Failed"
+	// DeploymentStatusCancelled means the deployment was cancelled by a client before it finished.
+	DeploymentStatusCancelled DeploymentStatus = "Cancelled"
+	// DeploymentStatusCanary means the deployment runs alongside the previous deployment at the
+	// ratio given by its canary parameters, awaiting promotion or abort.
+	DeploymentStatusCanary DeploymentStatus = "Canary"
 )
 
 // DeploymentConfigLabel is the key of a Deployment label whose value is the ID of a DeploymentConfig
 // on which the Deployment is based.
 const DeploymentConfigLabel = "deploymentConfig"
 
+// DeploymentLabel is the key of a ReplicationController or Pod label whose value is the ID of the
+// Deployment which created it.
+const DeploymentLabel = "deployment"
+
 // DeploymentStrategy describes how to perform a deployment.
 type DeploymentStrategy struct {
 	Type DeploymentStrategyType `json:"type,omitempty" yaml:"type,omitempty"`
 	// CustomPod represents the parameters for the CustomPod strategy.
 	CustomPod *CustomPodDeploymentStrategy `json:"customPod,omitempty" yaml:"customPod,omitempty"`
+	// Recreate represents the parameters for the Recreate strategy.
+	Recreate *RecreateDeploymentStrategy `json:"recreate,omitempty" yaml:"recreate,omitempty"`
+	// CancelPolicy determines what happens to replication controllers when a deployment is cancelled.
+	// If unspecified, DeploymentCancelPolicyLeave is assumed.
+	CancelPolicy DeploymentCancelPolicy `json:"cancelPolicy,omitempty" yaml:"cancelPolicy,omitempty"`
+	// Canary, if specified, pauses a Basic deployment while only part of its replicas run alongside
+	// the previous deployment, until the deployment is promoted or aborted.
+	Canary *CanaryDeploymentParams `json:"canary,omitempty" yaml:"canary,omitempty"`
+}
+
+// CanaryDeploymentParams represents parameters for canary deployments.
+type CanaryDeploymentParams struct {
+	// Percentage is the share of the deployment's replicas which run the new deployment while
+	// it awaits promotion. The previous deployment runs the remaining replicas.
+	Percentage int `json:"percentage,omitempty" yaml:"percentage,omitempty"`
 }
 
+// DeploymentCancelPolicy describes how replication controllers are handled when a deployment is cancelled.
+type DeploymentCancelPolicy string
+
+const (
+	// DeploymentCancelPolicyLeave leaves all replication controllers as they were at the time the
+	// deployment was cancelled.
+	DeploymentCancelPolicyLeave DeploymentCancelPolicy = "Leave"
+	// DeploymentCancelPolicyRestore removes the replication controller created for the cancelled
+	// deployment and restores the replication controller of the previous completed deployment.
+	DeploymentCancelPolicyRestore DeploymentCancelPolicy = "Restore"
+)
+
 // DeploymentStrategyType refers to a specific DeploymentStrategy implementation.
 type DeploymentStrategyType string
 
@@ -64,8 +157,18 @@ const (
 	DeploymentStrategyTypeBasic DeploymentStrategyType = "Basic"
 	// DeploymentStrategyTypeCustomPod is a custom deployment strategy carried out by a pod.
 	DeploymentStrategyTypeCustomPod DeploymentStrategyType = "CustomPod"
+	// DeploymentStrategyTypeRecreate is a deployment strategy which scales down and waits for the pods
+	// of previous deployments to terminate before creating and scaling up the new deployment.
+	DeploymentStrategyTypeRecreate DeploymentStrategyType = "Recreate"
 )
 
+// RecreateDeploymentStrategy represents parameters for the Recreate strategy.
+type RecreateDeploymentStrategy struct {
+	// TimeoutSeconds is how long to wait for the pods of previous deployments to terminate before
+	// failing the deployment. If unspecified, a server default is used.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+}
+
 // CustomPodDeploymentStrategy represents parameters for the CustomPod strategy.
 type CustomPodDeploymentStrategy struct {
 	// Image specifies a Docker image which can carry out a deployment.
@@ -89,17 +192,188 @@ type DeploymentConfig struct {
 	// LatestVersion is used to determine whether the current deployment associated with a DeploymentConfig
 	// is out of sync.
 	LatestVersion int `json:"latestVersion,omitempty" yaml:"latestVersion,omitempty"`
+	// Paused prevents new deployments from being created for the DeploymentConfig. Triggers continue
+	// to update the config while it is paused, so any changes are rolled out as a single deployment
+	// once the config is resumed.
+	Paused bool `json:"paused,omitempty" yaml:"paused,omitempty"`
+	// PendingImageUpdates records new images detected by image change triggers which are not
+	// automatic. They are rolled out once approved.
+	PendingImageUpdates []PendingImageUpdate `json:"pendingImageUpdates,omitempty" yaml:"pendingImageUpdates,omitempty"`
+	// Conditions report problems the server has encountered acting on the config, such as repeated
+	// failures to create its latest deployment. They are maintained by the server.
+	Conditions []DeploymentConfigCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
 	// The reasons for the update to this deployment config.
 	// This could be based on a change made by the user or caused by an automatic trigger
 	Details *DeploymentDetails `json:"details,omitempty" yaml:"details,omitempty"`
 }
 
+// DeploymentConfigConditionType is the kind of problem described by a DeploymentConfigCondition.
+type DeploymentConfigConditionType string
+
+const (
+	// DeploymentConfigConditionDeployFailed means the latest deployment of the config could not be
+	// created despite repeated attempts.
+	DeploymentConfigConditionDeployFailed DeploymentConfigConditionType = "DeployFailed"
+)
+
+// DeploymentConfigCondition describes a problem with a DeploymentConfig.
+type DeploymentConfigCondition struct {
+	// Type identifies the kind of problem.
+	Type DeploymentConfigConditionType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Message is a human readable description of the problem, typically the last error encountered.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Timestamp is the time at which the condition was recorded.
+	Timestamp util.Time `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+}
+
 // A DeploymentConfigList is a collection of deployment configs.
 type DeploymentConfigList struct {
 	api.TypeMeta `json:",inline" yaml:",inline"`
 	Items        []DeploymentConfig `json:"items,omitempty" yaml:"items,omitempty"`
 }
 
+// DeploymentConfigApproval approves the pending image updates of the DeploymentConfig with the same
+// ID, causing a new deployment of the DeploymentConfig.
+type DeploymentConfigApproval struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	// Updates are the pending image updates being approved. The approval is rejected unless each is
+	// still pending with the same image. If empty, all pending image updates are approved.
+	Updates []PendingImageUpdate `json:"updates,omitempty" yaml:"updates,omitempty"`
+}
+
+// DeploymentConfigScale sets the number of replicas of the DeploymentConfig with the same ID and of
+// the replication controller of its active deployment, without causing a new deployment.
+type DeploymentConfigScale struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	Replicas     int `json:"replicas" yaml:"replicas"`
+}
+
+// DeploymentConfigAutoscaler adjusts the replicas of a DeploymentConfig so that the value of a metric
+// per replica stays near a target, within a range of replicas.
+type DeploymentConfigAutoscaler struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// DeploymentConfigID is the ID of the DeploymentConfig to scale, in the namespace of the autoscaler.
+	DeploymentConfigID string `json:"deploymentConfigID" yaml:"deploymentConfigID"`
+	// MinReplicas is the lowest number of replicas the autoscaler scales to.
+	MinReplicas int `json:"minReplicas" yaml:"minReplicas"`
+	// MaxReplicas is the highest number of replicas the autoscaler scales to.
+	MaxReplicas int `json:"maxReplicas" yaml:"maxReplicas"`
+	// Metric is the name of the metric, as known to the metrics source, whose value per replica is
+	// kept near TargetValue.
+	Metric string `json:"metric" yaml:"metric"`
+	// TargetValue is the value of the metric per replica the autoscaler aims for.
+	TargetValue int `json:"targetValue" yaml:"targetValue"`
+	// ScaleUpCooldownSeconds is the minimum time between a scaling of the DeploymentConfig and a
+	// following scale up.
+	ScaleUpCooldownSeconds int `json:"scaleUpCooldownSeconds,omitempty" yaml:"scaleUpCooldownSeconds,omitempty"`
+	// ScaleDownCooldownSeconds is the minimum time between a scaling of the DeploymentConfig and a
+	// following scale down.
+	ScaleDownCooldownSeconds int `json:"scaleDownCooldownSeconds,omitempty" yaml:"scaleDownCooldownSeconds,omitempty"`
+	// Status is the most recently observed state of the autoscaler. It is maintained by the server.
+	Status DeploymentConfigAutoscalerStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// DeploymentConfigAutoscalerStatus describes the last observation of a DeploymentConfigAutoscaler.
+type DeploymentConfigAutoscalerStatus struct {
+	// CurrentReplicas is the number of replicas of the DeploymentConfig when last observed.
+	CurrentReplicas int `json:"currentReplicas,omitempty" yaml:"currentReplicas,omitempty"`
+	// DesiredReplicas is the number of replicas the last observed metric value calls for.
+	DesiredReplicas int `json:"desiredReplicas,omitempty" yaml:"desiredReplicas,omitempty"`
+	// MetricValue is the last observed value of the metric per replica.
+	MetricValue int `json:"metricValue,omitempty" yaml:"metricValue,omitempty"`
+	// LastScaleTimestamp is the time the autoscaler last scaled the DeploymentConfig.
+	LastScaleTimestamp util.Time `json:"lastScaleTimestamp,omitempty" yaml:"lastScaleTimestamp,omitempty"`
+}
+
+// A DeploymentConfigAutoscalerList is a collection of deployment config autoscalers.
+type DeploymentConfigAutoscalerList struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	Items        []DeploymentConfigAutoscaler `json:"items,omitempty" yaml:"items,omitempty"`
+}
+
+// DeploymentCanaryDecision promotes or aborts the Deployment with the same ID while it is in the
+// DeploymentStatusCanary status.
+type DeploymentCanaryDecision struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	Action       CanaryAction `json:"action,omitempty" yaml:"action,omitempty"`
+}
+
+// CanaryAction is the decision taken for a canary deployment.
+type CanaryAction string
+
+const (
+	// CanaryActionPromote scales the new deployment to all of its replicas and removes previous
+	// deployments, completing the deployment.
+	CanaryActionPromote CanaryAction = "Promote"
+	// CanaryActionAbort scales the new deployment to zero and returns its replicas to the previous
+	// deployment, cancelling the deployment.
+	CanaryActionAbort CanaryAction = "Abort"
+)
+
+// DeploymentConfigDiff describes the changes the next deployment of a DeploymentConfig would make to
+// the pod template of its most recent deployment.
+type DeploymentConfigDiff struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	// DeploymentID is the ID of the deployment the changes are relative to. It is empty if the
+	// DeploymentConfig has never been deployed.
+	DeploymentID string `json:"deploymentID,omitempty" yaml:"deploymentID,omitempty"`
+	// LatestVersion is the version the next deployment of the DeploymentConfig would have.
+	LatestVersion int `json:"latestVersion,omitempty" yaml:"latestVersion,omitempty"`
+	// Changes lists the differences between the deployed and the next pod template.
+	Changes []DeploymentChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// DeploymentConfigHistory lists the deployments of the DeploymentConfig with the same ID, newest first.
+type DeploymentConfigHistory struct {
+	api.TypeMeta `json:",inline" yaml:",inline"`
+	// Revisions holds a revision for every deployment of the DeploymentConfig which still exists.
+	Revisions []DeploymentRevision `json:"revisions,omitempty" yaml:"revisions,omitempty"`
+}
+
+// DeploymentRevision describes the deployment of one version of a DeploymentConfig.
+type DeploymentRevision struct {
+	// Version is the LatestVersion of the DeploymentConfig the deployment was created from.
+	Version int `json:"version,omitempty" yaml:"version,omitempty"`
+	// Deployment is the deployment, including its causes, the images it ran, its status and its
+	// timestamps.
+	Deployment Deployment `json:"deployment,omitempty" yaml:"deployment,omitempty"`
+	// Changes lists the differences between the pod template of the previous revision and that of
+	// this one. The oldest revision lists every container as added.
+	Changes []DeploymentChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// DeploymentChangeType describes the kind of a DeploymentChange.
+type DeploymentChangeType string
+
+const (
+	// DeploymentChangeContainerAdded means a container is added to the pod template.
+	DeploymentChangeContainerAdded DeploymentChangeType = "ContainerAdded"
+	// DeploymentChangeContainerRemoved means a container is removed from the pod template.
+	DeploymentChangeContainerRemoved DeploymentChangeType = "ContainerRemoved"
+	// DeploymentChangeImage means the image of a container changes.
+	DeploymentChangeImage DeploymentChangeType = "Image"
+	// DeploymentChangeEnv means an environment variable of a container is added, removed or changed.
+	DeploymentChangeEnv DeploymentChangeType = "Env"
+	// DeploymentChangePort means a port of a container is added, removed or changed.
+	DeploymentChangePort DeploymentChangeType = "Port"
+)
+
+// DeploymentChange is a single difference between two pod templates.
+type DeploymentChange struct {
+	// Type is the kind of change.
+	Type DeploymentChangeType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Container is the name of the container which changes.
+	Container string `json:"container,omitempty" yaml:"container,omitempty"`
+	// Name identifies the environment variable or port which changes. It is empty for container
+	// and image changes.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// From is the deployed value, empty if the value is added.
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	// To is the value the next deployment would have, empty if the value is removed.
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
 // DeploymentTemplate contains all the necessary information to create a Deployment from a
 // DeploymentStrategy.
 type DeploymentTemplate struct {
@@ -124,6 +398,20 @@ type DeploymentTriggerImageChangeParams struct {
 	RepositoryName string `json:"repositoryName,omitempty" yaml:"repositoryName,omitempty"`
 	// Tag is the name of an image repository tag to watch for changes.
 	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	// LastTriggeredImageID is the ID of the Image the tag referred to when the trigger last updated
+	// the pod template. It is maintained by the server and is empty until the trigger is resolved.
+	LastTriggeredImageID string `json:"lastTriggeredImageID,omitempty" yaml:"lastTriggeredImageID,omitempty"`
+}
+
+// PendingImageUpdate is a new image for a tag referenced by an image change trigger which awaits
+// approval before it is deployed.
+type PendingImageUpdate struct {
+	// RepositoryName is the Docker image repository of the trigger.
+	RepositoryName string `json:"repositoryName,omitempty" yaml:"repositoryName,omitempty"`
+	// Tag is the image repository tag of the trigger.
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	// ImageID is the image the tag now refers to.
+	ImageID string `json:"imageID,omitempty" yaml:"imageID,omitempty"`
 }
 
 // DeploymentTriggerType refers to a specific DeploymentTriggerPolicy implementation.
@@ -33,6 +33,27 @@ func validateDeploymentStrategy(strategy *deployapi.DeploymentStrategy) errors.E
 		}
 	}
 
+	if strategy.Type == deployapi.DeploymentStrategyTypeRecreate && strategy.Recreate != nil {
+		if strategy.Recreate.TimeoutSeconds < 0 {
+			result = append(result, errors.NewFieldInvalid("recreate.timeoutSeconds", strategy.Recreate.TimeoutSeconds))
+		}
+	}
+
+	if strategy.Canary != nil {
+		if strategy.Type != deployapi.DeploymentStrategyTypeBasic {
+			result = append(result, errors.NewFieldInvalid("canary", strategy.Canary))
+		}
+		if strategy.Canary.Percentage <= 0 || strategy.Canary.Percentage >= 100 {
+			result = append(result, errors.NewFieldInvalid("canary.percentage", strategy.Canary.Percentage))
+		}
+	}
+
+	switch strategy.CancelPolicy {
+	case "", deployapi.DeploymentCancelPolicyLeave, deployapi.DeploymentCancelPolicyRestore:
+	default:
+		result = append(result, errors.NewFieldNotSupported("cancelPolicy", strategy.CancelPolicy))
+	}
+
 	return result
 }
 
@@ -91,3 +112,31 @@ func ValidateDeploymentConfig(config *deployapi.DeploymentConfig) errors.ErrorLi
 
 	return result
 }
+
+func ValidateDeploymentConfigAutoscaler(autoscaler *deployapi.DeploymentConfigAutoscaler) errors.ErrorList {
+	result := errors.ErrorList{}
+
+	if len(autoscaler.DeploymentConfigID) == 0 {
+		result = append(result, errors.NewFieldRequired("deploymentConfigID", ""))
+	}
+	if len(autoscaler.Metric) == 0 {
+		result = append(result, errors.NewFieldRequired("metric", ""))
+	}
+	if autoscaler.TargetValue <= 0 {
+		result = append(result, errors.NewFieldInvalid("targetValue", autoscaler.TargetValue))
+	}
+	if autoscaler.MinReplicas < 1 {
+		result = append(result, errors.NewFieldInvalid("minReplicas", autoscaler.MinReplicas))
+	}
+	if autoscaler.MaxReplicas < autoscaler.MinReplicas {
+		result = append(result, errors.NewFieldInvalid("maxReplicas", autoscaler.MaxReplicas))
+	}
+	if autoscaler.ScaleUpCooldownSeconds < 0 {
+		result = append(result, errors.NewFieldInvalid("scaleUpCooldownSeconds", autoscaler.ScaleUpCooldownSeconds))
+	}
+	if autoscaler.ScaleDownCooldownSeconds < 0 {
+		result = append(result, errors.NewFieldInvalid("scaleDownCooldownSeconds", autoscaler.ScaleDownCooldownSeconds))
+	}
+
+	return result
+}
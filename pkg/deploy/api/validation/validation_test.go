@@ -65,6 +65,28 @@ func TestValidateDeploymentMissingFields(t *testing.T) {
 			errors.ValidationErrorTypeRequired,
 			"strategy.customPod.image",
 		},
+		"invalid strategy.canary.percentage": {
+			api.Deployment{
+				Strategy: api.DeploymentStrategy{
+					Type:   api.DeploymentStrategyTypeBasic,
+					Canary: &api.CanaryDeploymentParams{Percentage: 100},
+				},
+				ControllerTemplate: test.OkControllerTemplate(),
+			},
+			errors.ValidationErrorTypeInvalid,
+			"strategy.canary.percentage",
+		},
+		"strategy.canary with unsupported strategy type": {
+			api.Deployment{
+				Strategy: api.DeploymentStrategy{
+					Type:   api.DeploymentStrategyTypeRecreate,
+					Canary: &api.CanaryDeploymentParams{Percentage: 10},
+				},
+				ControllerTemplate: test.OkControllerTemplate(),
+			},
+			errors.ValidationErrorTypeInvalid,
+			"strategy.canary",
+		},
 	}
 
 	for k, v := range errorCases {
@@ -201,3 +223,75 @@ func TestValidateDeploymentConfigMissingFields(t *testing.T) {
 		}
 	}
 }
+
+func okAutoscaler() api.DeploymentConfigAutoscaler {
+	return api.DeploymentConfigAutoscaler{
+		DeploymentConfigID: "config",
+		MinReplicas:        1,
+		MaxReplicas:        5,
+		Metric:             "requestsPerSecond",
+		TargetValue:        100,
+	}
+}
+
+func TestValidateDeploymentConfigAutoscalerOK(t *testing.T) {
+	autoscaler := okAutoscaler()
+	if errs := ValidateDeploymentConfigAutoscaler(&autoscaler); len(errs) > 0 {
+		t.Errorf("Unxpected non-empty error list: %#v", errs)
+	}
+}
+
+func TestValidateDeploymentConfigAutoscalerInvalidFields(t *testing.T) {
+	errorCases := map[string]struct {
+		Mutate func(*api.DeploymentConfigAutoscaler)
+		T      errors.ValidationErrorType
+		F      string
+	}{
+		"missing deploymentConfigID": {
+			func(a *api.DeploymentConfigAutoscaler) { a.DeploymentConfigID = "" },
+			errors.ValidationErrorTypeRequired,
+			"deploymentConfigID",
+		},
+		"missing metric": {
+			func(a *api.DeploymentConfigAutoscaler) { a.Metric = "" },
+			errors.ValidationErrorTypeRequired,
+			"metric",
+		},
+		"zero targetValue": {
+			func(a *api.DeploymentConfigAutoscaler) { a.TargetValue = 0 },
+			errors.ValidationErrorTypeInvalid,
+			"targetValue",
+		},
+		"zero minReplicas": {
+			func(a *api.DeploymentConfigAutoscaler) { a.MinReplicas = 0 },
+			errors.ValidationErrorTypeInvalid,
+			"minReplicas",
+		},
+		"maxReplicas below minReplicas": {
+			func(a *api.DeploymentConfigAutoscaler) { a.MaxReplicas = 0 },
+			errors.ValidationErrorTypeInvalid,
+			"maxReplicas",
+		},
+		"negative scaleDownCooldownSeconds": {
+			func(a *api.DeploymentConfigAutoscaler) { a.ScaleDownCooldownSeconds = -1 },
+			errors.ValidationErrorTypeInvalid,
+			"scaleDownCooldownSeconds",
+		},
+	}
+
+	for k, v := range errorCases {
+		autoscaler := okAutoscaler()
+		v.Mutate(&autoscaler)
+		errs := ValidateDeploymentConfigAutoscaler(&autoscaler)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected one error, got %v", k, errs)
+			continue
+		}
+		if errs[0].(errors.ValidationError).Type != v.T {
+			t.Errorf("%s: expected error to have type %q: %v", k, v.T, errs[0])
+		}
+		if errs[0].(errors.ValidationError).Field != v.F {
+			t.Errorf("%s: expected error to have field %s: %v", k, v.F, errs[0])
+		}
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubecfg"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/openshift/origin/pkg/deploy/api"
 )
 
-var deploymentColumns = []string{"ID", "Status", "Cause"}
-var deploymentConfigColumns = []string{"ID", "Triggers", "LatestVersion"}
+var deploymentColumns = []string{"ID", "Status", "Cause", "Started", "Completed", "Message"}
+var deploymentConfigColumns = []string{"ID", "Triggers", "LatestVersion", "Paused"}
+var deploymentConfigDiffColumns = []string{"Container", "Change", "Name", "From", "To"}
+var deploymentConfigHistoryColumns = []string{"Version", "ID", "Status", "Cause", "Images", "Changes", "Started", "Completed"}
+var deploymentConfigAutoscalerColumns = []string{"ID", "DeploymentConfig", "Replicas", "Desired", "Range", "Metric", "Value", "Target", "Last Scaled"}
 
 // RegisterPrintHandlers registers human-readable printers for deploy types.
 func RegisterPrintHandlers(printer *kubecfg.HumanReadablePrinter) {
@@ -19,23 +23,36 @@ func RegisterPrintHandlers(printer *kubecfg.HumanReadablePrinter) {
 	printer.Handler(deploymentColumns, printDeploymentList)
 	printer.Handler(deploymentConfigColumns, printDeploymentConfig)
 	printer.Handler(deploymentConfigColumns, printDeploymentConfigList)
+	printer.Handler(deploymentConfigDiffColumns, printDeploymentConfigDiff)
+	printer.Handler(deploymentConfigHistoryColumns, printDeploymentConfigHistory)
+	printer.Handler(deploymentConfigAutoscalerColumns, printDeploymentConfigAutoscaler)
+	printer.Handler(deploymentConfigAutoscalerColumns, printDeploymentConfigAutoscalerList)
 }
 
+// printDeployment prints a summary of the deployment followed by the steps recorded by its
+// deployment strategy.
 func printDeployment(d *api.Deployment, w io.Writer) error {
-	causes := util.StringSet{}
-	if d.Details != nil {
-		for _, cause := range d.Details.Causes {
-			causes.Insert(string(cause.Type))
+	if err := printDeploymentSummary(d, w); err != nil {
+		return err
+	}
+	for _, event := range d.Events {
+		if _, err := fmt.Fprintf(w, "\t%s\t%s\t%s\n", event.Type, formatTimestamp(event.Timestamp), event.Message); err != nil {
+			return err
 		}
 	}
-	cStr := strings.Join(causes.List(), ", ")
-	_, err := fmt.Fprintf(w, "%s\t%s\t%s\n", d.ID, d.Status, cStr)
+	return nil
+}
+
+func printDeploymentSummary(d *api.Deployment, w io.Writer) error {
+	cStr := formatCauses(d)
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", d.ID, d.Status, cStr,
+		formatTimestamp(d.StartTimestamp), formatTimestamp(d.CompletionTimestamp), d.StatusMessage)
 	return err
 }
 
 func printDeploymentList(list *api.DeploymentList, w io.Writer) error {
 	for _, d := range list.Items {
-		if err := printDeployment(&d, w); err != nil {
+		if err := printDeploymentSummary(&d, w); err != nil {
 			return err
 		}
 	}
@@ -43,6 +60,23 @@ func printDeploymentList(list *api.DeploymentList, w io.Writer) error {
 	return nil
 }
 
+func formatCauses(d *api.Deployment) string {
+	causes := util.StringSet{}
+	if d.Details != nil {
+		for _, cause := range d.Details.Causes {
+			causes.Insert(string(cause.Type))
+		}
+	}
+	return strings.Join(causes.List(), ", ")
+}
+
+func formatTimestamp(t util.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func printDeploymentConfig(dc *api.DeploymentConfig, w io.Writer) error {
 	triggers := util.StringSet{}
 	for _, trigger := range dc.Triggers {
@@ -50,7 +84,7 @@ func printDeploymentConfig(dc *api.DeploymentConfig, w io.Writer) error {
 	}
 	tStr := strings.Join(triggers.List(), ", ")
 
-	_, err := fmt.Fprintf(w, "%s\t%s\t%v\n", dc.ID, tStr, dc.LatestVersion)
+	_, err := fmt.Fprintf(w, "%s\t%s\t%v\t%t\n", dc.ID, tStr, dc.LatestVersion, dc.Paused)
 	return err
 }
 
@@ -63,3 +97,44 @@ func printDeploymentConfigList(list *api.DeploymentConfigList, w io.Writer) erro
 
 	return nil
 }
+
+func printDeploymentConfigDiff(diff *api.DeploymentConfigDiff, w io.Writer) error {
+	for _, change := range diff.Changes {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", change.Container, change.Type, change.Name, change.From, change.To); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDeploymentConfigHistory(history *api.DeploymentConfigHistory, w io.Writer) error {
+	for _, revision := range history.Revisions {
+		d := revision.Deployment
+		images := []string{}
+		for _, container := range d.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers {
+			images = append(images, container.Image)
+		}
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n", revision.Version, d.ID, d.Status, formatCauses(&d),
+			strings.Join(images, ", "), len(revision.Changes), formatTimestamp(d.StartTimestamp), formatTimestamp(d.CompletionTimestamp)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDeploymentConfigAutoscaler(a *api.DeploymentConfigAutoscaler, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d-%d\t%s\t%d\t%d\t%s\n", a.ID, a.DeploymentConfigID,
+		a.Status.CurrentReplicas, a.Status.DesiredReplicas, a.MinReplicas, a.MaxReplicas,
+		a.Metric, a.Status.MetricValue, a.TargetValue, formatTimestamp(a.Status.LastScaleTimestamp))
+	return err
+}
+
+func printDeploymentConfigAutoscalerList(list *api.DeploymentConfigAutoscalerList, w io.Writer) error {
+	for _, a := range list.Items {
+		if err := printDeploymentConfigAutoscaler(&a, w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// DeploymentConfigAutoscalerController periodically reads the metric of every
+// DeploymentConfigAutoscaler and scales the DeploymentConfig of the autoscaler to the number of
+// replicas which brings the metric per replica back to its target, within the replica range of the
+// autoscaler. DeploymentConfigs are scaled with their scale operation, which resizes the replication
+// controller of the active deployment without causing a new deployment.
+//
+// A DeploymentConfig is not scaled up again before the scale up cooldown of the autoscaler has passed
+// since it was last scaled, nor down before the scale down cooldown has passed, so that a noisy metric
+// doesn't make the replica count flap.
+type DeploymentConfigAutoscalerController struct {
+	AutoscalerInterface       autoscalerInterface
+	DeploymentConfigInterface asDeploymentConfigInterface
+	// AutoscalerStore holds the DeploymentConfigAutoscalers to evaluate.
+	AutoscalerStore cache.Store
+	// Metrics provides the current value of the metrics of the autoscalers.
+	Metrics metricsSource
+	// Interval is the time between evaluations of the autoscalers.
+	Interval time.Duration
+	// Now returns the current time. It defaults to util.Now.
+	Now func() util.Time
+}
+
+type autoscalerInterface interface {
+	UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error)
+}
+
+type asDeploymentConfigInterface interface {
+	GetDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error)
+	ScaleDeploymentConfig(ctx kapi.Context, id string, replicas int) (*deployapi.DeploymentConfig, error)
+}
+
+type metricsSource interface {
+	GetMetric(namespace, deploymentConfigID, metric string) (int, error)
+}
+
+// Run evaluates the autoscalers every Interval.
+func (c *DeploymentConfigAutoscalerController) Run() {
+	go util.Forever(c.HandleAutoscalers, c.Interval)
+}
+
+// HandleAutoscalers evaluates every autoscaler in the store once.
+func (c *DeploymentConfigAutoscalerController) HandleAutoscalers() {
+	for _, obj := range c.AutoscalerStore.List() {
+		c.autoscale(obj.(*deployapi.DeploymentConfigAutoscaler))
+	}
+}
+
+// autoscale scales the DeploymentConfig of the autoscaler if its metric calls for a different number
+// of replicas and the applicable cooldown has passed, and records what it observed in the status of
+// the autoscaler.
+func (c *DeploymentConfigAutoscalerController) autoscale(autoscaler *deployapi.DeploymentConfigAutoscaler) {
+	ctx := kapi.WithNamespace(kapi.NewContext(), autoscaler.Namespace)
+
+	config, err := c.DeploymentConfigInterface.GetDeploymentConfig(ctx, autoscaler.DeploymentConfigID)
+	if err != nil {
+		glog.V(2).Infof("Error getting deploymentConfig %s of autoscaler %s: %v", autoscaler.DeploymentConfigID, autoscaler.ID, err)
+		return
+	}
+	value, err := c.Metrics.GetMetric(autoscaler.Namespace, config.ID, autoscaler.Metric)
+	if err != nil {
+		glog.V(2).Infof("Error getting metric for autoscaler %s: %v", autoscaler.ID, err)
+		return
+	}
+
+	current := config.Template.ControllerTemplate.Replicas
+	desired := desiredReplicas(autoscaler, current, value)
+	now := c.now()
+
+	status := deployapi.DeploymentConfigAutoscalerStatus{
+		CurrentReplicas:    current,
+		DesiredReplicas:    desired,
+		MetricValue:        value,
+		LastScaleTimestamp: autoscaler.Status.LastScaleTimestamp,
+	}
+
+	switch {
+	case desired == current:
+		glog.V(4).Infof("Autoscaler %s keeps deploymentConfig %s at %d replicas", autoscaler.ID, config.ID, current)
+	case coolingDown(autoscaler, desired > current, now):
+		glog.V(4).Infof("Autoscaler %s won't scale deploymentConfig %s from %d to %d replicas during its cooldown", autoscaler.ID, config.ID, current, desired)
+	default:
+		glog.V(2).Infof("Autoscaler %s is scaling deploymentConfig %s from %d to %d replicas (%s=%d, target %d)", autoscaler.ID, config.ID, current, desired, autoscaler.Metric, value, autoscaler.TargetValue)
+		if _, err := c.DeploymentConfigInterface.ScaleDeploymentConfig(ctx, config.ID, desired); err != nil {
+			glog.V(2).Infof("Error scaling deploymentConfig %s: %v", config.ID, err)
+			break
+		}
+		status.CurrentReplicas = desired
+		status.LastScaleTimestamp = now
+	}
+
+	if status == autoscaler.Status {
+		return
+	}
+	newAutoscaler := *autoscaler
+	newAutoscaler.Status = status
+	if _, err := c.AutoscalerInterface.UpdateDeploymentConfigAutoscaler(ctx, &newAutoscaler); err != nil {
+		glog.V(2).Infof("Error updating the status of autoscaler %s: %v", autoscaler.ID, err)
+	}
+}
+
+func (c *DeploymentConfigAutoscalerController) now() util.Time {
+	if c.Now == nil {
+		return util.Now()
+	}
+	return c.Now()
+}
+
+// desiredReplicas returns the number of replicas which would bring a metric whose value per replica
+// is value with current replicas to the target of the autoscaler, assuming the load is spread evenly,
+// limited to the replica range of the autoscaler.
+func desiredReplicas(autoscaler *deployapi.DeploymentConfigAutoscaler, current, value int) int {
+	desired := (current*value + autoscaler.TargetValue - 1) / autoscaler.TargetValue
+	if desired < autoscaler.MinReplicas {
+		return autoscaler.MinReplicas
+	}
+	if desired > autoscaler.MaxReplicas {
+		return autoscaler.MaxReplicas
+	}
+	return desired
+}
+
+// coolingDown returns true if the autoscaler last scaled its DeploymentConfig more recently than the
+// cooldown for scaling up, or down, allows.
+func coolingDown(autoscaler *deployapi.DeploymentConfigAutoscaler, up bool, now util.Time) bool {
+	last := autoscaler.Status.LastScaleTimestamp
+	if last.IsZero() {
+		return false
+	}
+	cooldown := autoscaler.ScaleDownCooldownSeconds
+	if up {
+		cooldown = autoscaler.ScaleUpCooldownSeconds
+	}
+	return now.Sub(last.Time) < time.Duration(cooldown)*time.Second
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+type testAutoscalerInterface struct {
+	UpdateDeploymentConfigAutoscalerFunc func(autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error)
+}
+
+func (i *testAutoscalerInterface) UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error) {
+	return i.UpdateDeploymentConfigAutoscalerFunc(autoscaler)
+}
+
+type testAsDeploymentConfigInterface struct {
+	GetDeploymentConfigFunc   func(id string) (*deployapi.DeploymentConfig, error)
+	ScaleDeploymentConfigFunc func(id string, replicas int) (*deployapi.DeploymentConfig, error)
+}
+
+func (i *testAsDeploymentConfigInterface) GetDeploymentConfig(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+	return i.GetDeploymentConfigFunc(id)
+}
+
+func (i *testAsDeploymentConfigInterface) ScaleDeploymentConfig(ctx kapi.Context, id string, replicas int) (*deployapi.DeploymentConfig, error) {
+	return i.ScaleDeploymentConfigFunc(id, replicas)
+}
+
+type testMetricsSource struct {
+	GetMetricFunc func(namespace, deploymentConfigID, metric string) (int, error)
+}
+
+func (s *testMetricsSource) GetMetric(namespace, deploymentConfigID, metric string) (int, error) {
+	return s.GetMetricFunc(namespace, deploymentConfigID, metric)
+}
+
+func testAutoscaler() *deployapi.DeploymentConfigAutoscaler {
+	return &deployapi.DeploymentConfigAutoscaler{
+		TypeMeta:                 kapi.TypeMeta{ID: "autoscaler"},
+		DeploymentConfigID:       "config",
+		MinReplicas:              1,
+		MaxReplicas:              10,
+		Metric:                   "requestsPerSecond",
+		TargetValue:              100,
+		ScaleUpCooldownSeconds:   60,
+		ScaleDownCooldownSeconds: 300,
+	}
+}
+
+// autoscalerResult records the scaling and the status update made by a controller under test.
+type autoscalerResult struct {
+	scaledTo int
+	updated  *deployapi.DeploymentConfigAutoscaler
+}
+
+func autoscalerController(autoscaler *deployapi.DeploymentConfigAutoscaler, replicas, value int, now util.Time) (*DeploymentConfigAutoscalerController, *autoscalerResult) {
+	result := &autoscalerResult{scaledTo: -1}
+	store := cache.NewStore()
+	store.Add(autoscaler.ID, autoscaler)
+
+	controller := &DeploymentConfigAutoscalerController{
+		AutoscalerInterface: &testAutoscalerInterface{
+			UpdateDeploymentConfigAutoscalerFunc: func(autoscaler *deployapi.DeploymentConfigAutoscaler) (*deployapi.DeploymentConfigAutoscaler, error) {
+				result.updated = autoscaler
+				return autoscaler, nil
+			},
+		},
+		DeploymentConfigInterface: &testAsDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				config := manualDeploymentConfig()
+				config.ID = id
+				config.Template.ControllerTemplate.Replicas = replicas
+				return config, nil
+			},
+			ScaleDeploymentConfigFunc: func(id string, replicas int) (*deployapi.DeploymentConfig, error) {
+				result.scaledTo = replicas
+				return nil, nil
+			},
+		},
+		AutoscalerStore: store,
+		Metrics: &testMetricsSource{
+			GetMetricFunc: func(namespace, deploymentConfigID, metric string) (int, error) {
+				return value, nil
+			},
+		},
+		Now: func() util.Time { return now },
+	}
+	return controller, result
+}
+
+func TestDesiredReplicas(t *testing.T) {
+	testCases := []struct {
+		current, value, expected int
+	}{
+		{current: 2, value: 100, expected: 2},
+		{current: 2, value: 150, expected: 3},
+		{current: 4, value: 40, expected: 2},
+		{current: 4, value: 0, expected: 1},
+		{current: 0, value: 0, expected: 1},
+		{current: 8, value: 500, expected: 10},
+	}
+
+	for _, test := range testCases {
+		if e, a := test.expected, desiredReplicas(testAutoscaler(), test.current, test.value); e != a {
+			t.Errorf("Expected %d replicas for %d replicas at %d, got %d", e, test.current, test.value, a)
+		}
+	}
+}
+
+func TestAutoscalerScalesUp(t *testing.T) {
+	now := util.Now()
+	controller, result := autoscalerController(testAutoscaler(), 2, 250, now)
+
+	controller.HandleAutoscalers()
+
+	if e, a := 5, result.scaledTo; e != a {
+		t.Fatalf("Expected the config to be scaled to %d, got %d", e, a)
+	}
+	if result.updated == nil {
+		t.Fatalf("Expected the autoscaler status to be updated")
+	}
+	status := result.updated.Status
+	if status.CurrentReplicas != 5 || status.DesiredReplicas != 5 || status.MetricValue != 250 || status.LastScaleTimestamp != now {
+		t.Errorf("Unexpected status: %#v", status)
+	}
+}
+
+func TestAutoscalerScaleUpCooldown(t *testing.T) {
+	now := util.Now()
+	autoscaler := testAutoscaler()
+	autoscaler.Status.LastScaleTimestamp = util.Time{Time: now.Add(-30 * time.Second)}
+	controller, result := autoscalerController(autoscaler, 2, 250, now)
+
+	controller.HandleAutoscalers()
+
+	if result.scaledTo != -1 {
+		t.Fatalf("Unexpected scaling to %d during the cooldown", result.scaledTo)
+	}
+	if result.updated == nil || result.updated.Status.DesiredReplicas != 5 || result.updated.Status.CurrentReplicas != 2 {
+		t.Errorf("Expected the status to record the desired replicas, got %#v", result.updated)
+	}
+}
+
+func TestAutoscalerScaleDownCooldown(t *testing.T) {
+	now := util.Now()
+	autoscaler := testAutoscaler()
+	// the scale up cooldown has passed, but scaling down waits longer
+	autoscaler.Status.LastScaleTimestamp = util.Time{Time: now.Add(-2 * time.Minute)}
+	controller, result := autoscalerController(autoscaler, 4, 40, now)
+
+	controller.HandleAutoscalers()
+
+	if result.scaledTo != -1 {
+		t.Fatalf("Unexpected scaling to %d during the cooldown", result.scaledTo)
+	}
+
+	autoscaler.Status.LastScaleTimestamp = util.Time{Time: now.Add(-10 * time.Minute)}
+	controller, result = autoscalerController(autoscaler, 4, 40, now)
+
+	controller.HandleAutoscalers()
+
+	if e, a := 2, result.scaledTo; e != a {
+		t.Errorf("Expected the config to be scaled to %d after the cooldown, got %d", e, a)
+	}
+}
+
+func TestAutoscalerUnchangedStatus(t *testing.T) {
+	autoscaler := testAutoscaler()
+	autoscaler.Status = deployapi.DeploymentConfigAutoscalerStatus{CurrentReplicas: 2, DesiredReplicas: 2, MetricValue: 100}
+	controller, result := autoscalerController(autoscaler, 2, 100, util.Now())
+
+	controller.HandleAutoscalers()
+
+	if result.scaledTo != -1 {
+		t.Errorf("Unexpected scaling to %d", result.scaledTo)
+	}
+	if result.updated != nil {
+		t.Errorf("Unexpected status update: %#v", result.updated.Status)
+	}
+}
+
+func TestAutoscalerMetricError(t *testing.T) {
+	controller, result := autoscalerController(testAutoscaler(), 2, 250, util.Now())
+	controller.Metrics = &testMetricsSource{
+		GetMetricFunc: func(namespace, deploymentConfigID, metric string) (int, error) {
+			return 0, fmt.Errorf("metrics unavailable")
+		},
+	}
+
+	controller.HandleAutoscalers()
+
+	if result.scaledTo != -1 || result.updated != nil {
+		t.Errorf("Expected no action without a metric, got %#v", result)
+	}
+}
@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/golang/glog"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -8,11 +11,13 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 // BasicDeploymentController implements the DeploymentStrategyTypeBasic deployment strategy. Its behavior
 // is to create new replication controllers as defined on a Deployment, and delete any previously existing
-// replication controllers for the same DeploymentConfig associated with the deployment.
+// replication controllers for the same DeploymentConfig associated with the deployment. Canary deployments
+// instead run part of their replicas alongside the previous deployment until promoted or aborted.
 type BasicDeploymentController struct {
 	DeploymentUpdater           bdcDeploymentUpdater
 	ReplicationControllerClient bdcReplicationControllerClient
@@ -48,14 +53,15 @@ func (dc *BasicDeploymentController) HandleDeployment() error {
 	ctx := kapi.WithNamespace(kapi.NewContext(), deployment.Namespace)
 
 	nextStatus := deployment.Status
+	message := ""
 	switch deployment.Status {
 	case deployapi.DeploymentStatusNew:
-		nextStatus = dc.handleNew(ctx, deployment)
+		nextStatus, message = dc.handleNew(ctx, deployment)
 	}
 
 	// persist any status change
 	if deployment.Status != nextStatus {
-		deployment.Status = nextStatus
+		deployutil.SetDeploymentStatus(deployment, nextStatus, message)
 		glog.V(4).Infof("Saving deployment %v status: %v", deployment.ID, deployment.Status)
 		if _, err := dc.DeploymentUpdater.UpdateDeployment(ctx, deployment); err != nil {
 			glog.V(2).Infof("Received error while saving deployment %v: %v", deployment.ID, err)
@@ -66,74 +72,136 @@ func (dc *BasicDeploymentController) HandleDeployment() error {
 	return nil
 }
 
-func (dc *BasicDeploymentController) handleNew(ctx kapi.Context, deployment *deployapi.Deployment) deployapi.DeploymentStatus {
-	controllers := &kapi.ReplicationControllerList{}
-	var err error
+// handleNew creates the replication controller for the deployment and removes those of any previous
+// deployments. It returns the next status of the deployment along with a message explaining any
+// failure. Each step performed is recorded as an event on the deployment.
+func (dc *BasicDeploymentController) handleNew(ctx kapi.Context, deployment *deployapi.Deployment) (deployapi.DeploymentStatus, string) {
+	controllers, err := listPreviousControllers(ctx, dc.ReplicationControllerClient, deployment)
+	if err != nil {
+		return failDeployment(deployment, "Unable to get list of replication controllers for previous deployments: %v", err)
+	}
 
-	configID, hasConfigID := deployment.Labels[deployapi.DeploymentConfigLabel]
-	if hasConfigID {
-		selector, _ := labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + configID)
-		controllers, err = dc.ReplicationControllerClient.ListReplicationControllers(ctx, selector)
-		if err != nil {
-			glog.V(2).Infof("Unable to get list of replication controllers for previous deploymentConfig %s: %v\n", configID, err)
-			return deployapi.DeploymentStatusFailed
+	if deployment.Strategy.Canary != nil {
+		if baseline := deployutil.CanaryBaselineController(deployment, controllers.Items); baseline != nil {
+			return dc.handleCanary(ctx, deployment, baseline)
+		}
+		glog.V(2).Infof("Deploying canary deployment %s fully because there is no previous deployment", deployment.ID)
+	}
+
+	if _, err := createController(ctx, dc.ReplicationControllerClient, deployment, deployment.ControllerTemplate.Replicas); err != nil {
+		return failDeployment(deployment, "Unable to create replication controller: %v", err)
+	}
+
+	failures := []string{}
+	// For this simple deploy, remove previous replication controllers
+	for _, rc := range controllers.Items {
+		if err := scaleController(ctx, dc.ReplicationControllerClient, deployment, rc.ID, 0); err != nil {
+			failures = append(failures, err.Error())
 		}
 	}
 
-	controller := &kapi.ReplicationController{
-		DesiredState: deployment.ControllerTemplate,
-		Labels:       map[string]string{deployapi.DeploymentConfigLabel: configID, "deployment": deployment.ID},
+	for _, rc := range controllers.Items {
+		if err := deleteController(ctx, dc.ReplicationControllerClient, deployment, rc.ID); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) == 0 {
+		return deployapi.DeploymentStatusComplete, ""
+	}
+
+	return failDeployment(deployment, "Unable to remove previous replication controllers: %s", strings.Join(failures, "; "))
+}
+
+// handleCanary creates the replication controller for the deployment with the canary share of its replicas
+// and scales down the baseline replication controller to run the remaining replicas. The deployment then
+// awaits promotion or abort.
+func (dc *BasicDeploymentController) handleCanary(ctx kapi.Context, deployment *deployapi.Deployment, baseline *kapi.ReplicationController) (deployapi.DeploymentStatus, string) {
+	canary := deployutil.CanaryReplicas(deployment)
+	if _, err := createController(ctx, dc.ReplicationControllerClient, deployment, canary); err != nil {
+		return failDeployment(deployment, "Unable to create replication controller: %v", err)
+	}
+
+	if err := scaleController(ctx, dc.ReplicationControllerClient, deployment, baseline.ID, deployment.ControllerTemplate.Replicas-canary); err != nil {
+		return failDeployment(deployment, "Unable to scale down previous deployment: %v", err)
 	}
 
-	if controller.DesiredState.PodTemplate.Labels == nil {
-		controller.DesiredState.PodTemplate.Labels = make(map[string]string)
+	return deployapi.DeploymentStatusCanary, ""
+}
+
+// listPreviousControllers returns the replication controllers belonging to the DeploymentConfig of
+// the deployment. A deployment not associated with a config has no previous controllers.
+func listPreviousControllers(ctx kapi.Context, client bdcReplicationControllerClient, deployment *deployapi.Deployment) (*kapi.ReplicationControllerList, error) {
+	configID, hasConfigID := deployment.Labels[deployapi.DeploymentConfigLabel]
+	if !hasConfigID {
+		return &kapi.ReplicationControllerList{}, nil
+	}
+
+	selector, _ := labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + configID)
+	controllers, err := client.ListReplicationControllers(ctx, selector)
+	if err != nil {
+		glog.V(2).Infof("Unable to get list of replication controllers for previous deploymentConfig %s: %v\n", configID, err)
+		return nil, err
 	}
 
-	controller.DesiredState.PodTemplate.Labels[deployapi.DeploymentConfigLabel] = configID
-	controller.DesiredState.PodTemplate.Labels["deployment"] = deployment.ID
+	return controllers, nil
+}
+
+// createController creates the replication controller for the deployment with the given number
+// of replicas.
+func createController(ctx kapi.Context, client bdcReplicationControllerClient, deployment *deployapi.Deployment, replicas int) (*kapi.ReplicationController, error) {
+	controller := deployutil.ReplicationControllerForDeployment(deployment)
+	controller.DesiredState.Replicas = replicas
 
 	glog.V(2).Infof("Creating replicationController for deployment %s", deployment.ID)
-	if _, err := dc.ReplicationControllerClient.CreateReplicationController(ctx, controller); err != nil {
+	created, err := client.CreateReplicationController(ctx, controller)
+	if err != nil {
 		glog.V(2).Infof("An error occurred creating the replication controller for deployment %s: %v", deployment.ID, err)
-		return deployapi.DeploymentStatusFailed
+		return nil, err
 	}
 
-	allProcessed := true
-	// For this simple deploy, remove previous replication controllers
-	for _, rc := range controllers.Items {
-		configID, _ := deployment.Labels[deployapi.DeploymentConfigLabel]
-		glog.V(2).Infof("Stopping replication controller for previous deploymentConfig %s: %v", configID, rc.ID)
-
-		controller, err := dc.ReplicationControllerClient.GetReplicationController(ctx, rc.ID)
-		if err != nil {
-			glog.V(2).Infof("Unable to get replication controller %s for previous deploymentConfig %s: %#v\n", rc.ID, configID, err)
-			allProcessed = false
-			continue
-		}
+	deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerCreated,
+		"Created replication controller %s with %d replicas", created.ID, replicas)
+	return created, nil
+}
 
-		controller.DesiredState.Replicas = 0
-		glog.V(2).Infof("Settings Replicas=0 for replicationController %s for previous deploymentConfig %s", rc.ID, configID)
-		if _, err := dc.ReplicationControllerClient.UpdateReplicationController(ctx, controller); err != nil {
-			glog.V(2).Infof("Unable to stop replication controller %s for previous deploymentConfig %s: %#v\n", rc.ID, configID, err)
-			allProcessed = false
-			continue
-		}
+// scaleController sets the replica count of the replication controller with the given id.
+func scaleController(ctx kapi.Context, client bdcReplicationControllerClient, deployment *deployapi.Deployment, id string, replicas int) error {
+	controller, err := client.GetReplicationController(ctx, id)
+	if err != nil {
+		glog.V(2).Infof("Unable to get replication controller %s for deployment %s: %#v\n", id, deployment.ID, err)
+		return fmt.Errorf("unable to get replication controller %s: %v", id, err)
 	}
 
-	for _, rc := range controllers.Items {
-		configID, _ := deployment.Labels[deployapi.DeploymentConfigLabel]
-		glog.V(2).Infof("Deleting replication controller %s for previous deploymentConfig %s", rc.ID, configID)
-		err := dc.ReplicationControllerClient.DeleteReplicationController(ctx, rc.ID)
-		if err != nil {
-			glog.V(2).Infof("Unable to remove replication controller %s for previous deploymentConfig %s:%#v\n", rc.ID, configID, err)
-			allProcessed = false
-			continue
-		}
+	controller.DesiredState.Replicas = replicas
+	glog.V(2).Infof("Setting Replicas=%d for replicationController %s for deployment %s", replicas, id, deployment.ID)
+	if _, err := client.UpdateReplicationController(ctx, controller); err != nil {
+		glog.V(2).Infof("Unable to scale replication controller %s for deployment %s: %#v\n", id, deployment.ID, err)
+		return fmt.Errorf("unable to scale replication controller %s: %v", id, err)
 	}
 
-	if allProcessed {
-		return deployapi.DeploymentStatusComplete
+	deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerScaled,
+		"Scaled replication controller %s to %d replicas", id, replicas)
+	return nil
+}
+
+// deleteController removes the replication controller with the given id.
+func deleteController(ctx kapi.Context, client bdcReplicationControllerClient, deployment *deployapi.Deployment, id string) error {
+	glog.V(2).Infof("Deleting replication controller %s for deployment %s", id, deployment.ID)
+	if err := client.DeleteReplicationController(ctx, id); err != nil {
+		glog.V(2).Infof("Unable to remove replication controller %s for deployment %s: %#v\n", id, deployment.ID, err)
+		return fmt.Errorf("unable to remove replication controller %s: %v", id, err)
 	}
 
-	return deployapi.DeploymentStatusFailed
+	deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerDeleted,
+		"Deleted replication controller %s", id)
+	return nil
+}
+
+// failDeployment records a failure event on the deployment and returns the failed status along
+// with the failure message.
+func failDeployment(deployment *deployapi.Deployment, format string, args ...interface{}) (deployapi.DeploymentStatus, string) {
+	message := fmt.Sprintf(format, args...)
+	deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventFailed, "%s", message)
+	return deployapi.DeploymentStatusFailed, message
 }
@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+type testBdcReplicationControllerClient struct {
+	ListReplicationControllersFunc  func(selector labels.Selector) (*kapi.ReplicationControllerList, error)
+	GetReplicationControllerFunc    func(id string) (*kapi.ReplicationController, error)
+	CreateReplicationControllerFunc func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error)
+	UpdateReplicationControllerFunc func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error)
+	DeleteReplicationControllerFunc func(id string) error
+}
+
+func (c *testBdcReplicationControllerClient) ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+	return c.ListReplicationControllersFunc(selector)
+}
+
+func (c *testBdcReplicationControllerClient) GetReplicationController(ctx kapi.Context, id string) (*kapi.ReplicationController, error) {
+	return c.GetReplicationControllerFunc(id)
+}
+
+func (c *testBdcReplicationControllerClient) CreateReplicationController(ctx kapi.Context, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	return c.CreateReplicationControllerFunc(ctrl)
+}
+
+func (c *testBdcReplicationControllerClient) UpdateReplicationController(ctx kapi.Context, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	return c.UpdateReplicationControllerFunc(ctrl)
+}
+
+func (c *testBdcReplicationControllerClient) DeleteReplicationController(ctx kapi.Context, id string) error {
+	return c.DeleteReplicationControllerFunc(id)
+}
+
+func previousControllerClient() *testBdcReplicationControllerClient {
+	return &testBdcReplicationControllerClient{
+		ListReplicationControllersFunc: func(selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+			return &kapi.ReplicationControllerList{
+				Items: []kapi.ReplicationController{
+					{TypeMeta: kapi.TypeMeta{ID: "config-1"}},
+				},
+			}, nil
+		},
+		GetReplicationControllerFunc: func(id string) (*kapi.ReplicationController, error) {
+			return &kapi.ReplicationController{TypeMeta: kapi.TypeMeta{ID: id}}, nil
+		},
+		CreateReplicationControllerFunc: func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			return ctrl, nil
+		},
+		UpdateReplicationControllerFunc: func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			return ctrl, nil
+		},
+		DeleteReplicationControllerFunc: func(id string) error {
+			return nil
+		},
+	}
+}
+
+func configDeployment() *deployapi.Deployment {
+	d := basicDeployment()
+	d.Labels = map[string]string{deployapi.DeploymentConfigLabel: "config"}
+	return d
+}
+
+func TestBasicHandleNewRecordsEvents(t *testing.T) {
+	var updatedDeployment *deployapi.Deployment
+
+	controller := &BasicDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				updatedDeployment = deployment
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: previousControllerClient(),
+		NextDeployment:              configDeployment,
+	}
+
+	controller.HandleDeployment()
+
+	if updatedDeployment == nil {
+		t.Fatalf("expected an updated deployment")
+	}
+
+	if e, a := deployapi.DeploymentStatusComplete, updatedDeployment.Status; e != a {
+		t.Fatalf("expected updated deployment status %s, got %s", e, a)
+	}
+
+	if updatedDeployment.StartTimestamp.IsZero() || updatedDeployment.CompletionTimestamp.IsZero() {
+		t.Fatalf("expected start and completion timestamps to be set: %#v", updatedDeployment)
+	}
+
+	expectedEvents := []deployapi.DeploymentEventType{
+		deployapi.DeploymentEventReplicationControllerCreated,
+		deployapi.DeploymentEventReplicationControllerScaled,
+		deployapi.DeploymentEventReplicationControllerDeleted,
+	}
+	if e, a := len(expectedEvents), len(updatedDeployment.Events); e != a {
+		t.Fatalf("expected %d events, got %d: %#v", e, a, updatedDeployment.Events)
+	}
+	for i, e := range expectedEvents {
+		if a := updatedDeployment.Events[i].Type; e != a {
+			t.Errorf("expected event %d to be %s, got %s", i, e, a)
+		}
+	}
+}
+
+func TestBasicHandleNewCreateFailure(t *testing.T) {
+	var updatedDeployment *deployapi.Deployment
+
+	client := previousControllerClient()
+	client.CreateReplicationControllerFunc = func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+		return nil, fmt.Errorf("create failed")
+	}
+
+	controller := &BasicDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				updatedDeployment = deployment
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: client,
+		NextDeployment:              configDeployment,
+	}
+
+	controller.HandleDeployment()
+
+	if updatedDeployment == nil {
+		t.Fatalf("expected an updated deployment")
+	}
+
+	if e, a := deployapi.DeploymentStatusFailed, updatedDeployment.Status; e != a {
+		t.Fatalf("expected updated deployment status %s, got %s", e, a)
+	}
+
+	if len(updatedDeployment.StatusMessage) == 0 {
+		t.Fatalf("expected a status message explaining the failure")
+	}
+
+	if e, a := deployapi.DeploymentEventFailed, updatedDeployment.Events[len(updatedDeployment.Events)-1].Type; e != a {
+		t.Fatalf("expected last event type %s, got %s", e, a)
+	}
+}
+
+func TestBasicHandleNewCanary(t *testing.T) {
+	var (
+		updatedDeployment *deployapi.Deployment
+		created           *kapi.ReplicationController
+	)
+	scaled := map[string]int{}
+
+	client := previousControllerClient()
+	client.ListReplicationControllersFunc = func(selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+		return &kapi.ReplicationControllerList{
+			Items: []kapi.ReplicationController{
+				{TypeMeta: kapi.TypeMeta{ID: "config-1"}, DesiredState: kapi.ReplicationControllerState{Replicas: 10}},
+			},
+		}, nil
+	}
+	client.CreateReplicationControllerFunc = func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+		created = ctrl
+		return ctrl, nil
+	}
+	client.UpdateReplicationControllerFunc = func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+		scaled[ctrl.ID] = ctrl.DesiredState.Replicas
+		return ctrl, nil
+	}
+	client.DeleteReplicationControllerFunc = func(id string) error {
+		t.Fatalf("unexpected deletion of replication controller %s", id)
+		return nil
+	}
+
+	controller := &BasicDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				updatedDeployment = deployment
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: client,
+		NextDeployment: func() *deployapi.Deployment {
+			deployment := configDeployment()
+			deployment.Status = deployapi.DeploymentStatusNew
+			deployment.ControllerTemplate.Replicas = 10
+			deployment.Strategy.Canary = &deployapi.CanaryDeploymentParams{Percentage: 25}
+			return deployment
+		},
+	}
+
+	if err := controller.HandleDeployment(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if e, a := deployapi.DeploymentStatusCanary, updatedDeployment.Status; e != a {
+		t.Fatalf("expected status %s, got %s", e, a)
+	}
+	if created == nil || created.DesiredState.Replicas != 3 {
+		t.Fatalf("expected a replication controller with 3 replicas, got %#v", created)
+	}
+	if e, a := 7, scaled["config-1"]; e != a {
+		t.Fatalf("expected previous replication controller scaled to %d, got %d", e, a)
+	}
+}
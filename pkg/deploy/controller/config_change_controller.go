@@ -2,9 +2,9 @@ package controller
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	cache "github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	util "github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
+	oscache "github.com/openshift/origin/pkg/client/cache"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deployutil "github.com/openshift/origin/pkg/deploy/util"
 
@@ -17,7 +17,8 @@ import (
 type DeploymentConfigChangeController struct {
 	ChangeStrategy       changeStrategy
 	NextDeploymentConfig func() *deployapi.DeploymentConfig
-	DeploymentStore      cache.Store
+	// DeploymentStore is indexed by DeploymentConfigIndex.
+	DeploymentStore oscache.Indexer
 }
 
 type changeStrategy interface {
@@ -53,16 +54,12 @@ func (dc *DeploymentConfigChangeController) HandleDeploymentConfig() {
 		return
 	}
 
-	latestDeploymentId := deployutil.LatestDeploymentIDForConfig(config)
-	obj, exists := dc.DeploymentStore.Get(latestDeploymentId)
-
-	if !exists {
+	deployment := dc.latestDeployment(config)
+	if deployment == nil {
 		glog.V(4).Info("Ignoring config change due to lack of existing deployment")
 		return
 	}
 
-	deployment := obj.(*deployapi.Deployment)
-
 	if deployutil.PodTemplatesEqual(config.Template.ControllerTemplate.PodTemplate, deployment.ControllerTemplate.PodTemplate) {
 		glog.V(4).Infof("Ignoring updated config %s with LatestVersion=%d because it matches deployment %s", config.ID, config.LatestVersion, deployment.ID)
 		return
@@ -71,6 +68,18 @@ func (dc *DeploymentConfigChangeController) HandleDeploymentConfig() {
 	dc.generateDeployment(config, deployment)
 }
 
+// latestDeployment returns the deployment of the config's LatestVersion, or nil if it doesn't exist yet.
+func (dc *DeploymentConfigChangeController) latestDeployment(config *deployapi.DeploymentConfig) *deployapi.Deployment {
+	latestDeploymentID := deployutil.LatestDeploymentIDForConfig(config)
+	for _, obj := range dc.DeploymentStore.Index(DeploymentConfigIndex, config.ID) {
+		deployment := obj.(*deployapi.Deployment)
+		if deployment.ID == latestDeploymentID && deployment.Namespace == config.Namespace {
+			return deployment
+		}
+	}
+	return nil
+}
+
 func (dc *DeploymentConfigChangeController) generateDeployment(config *deployapi.DeploymentConfig, deployment *deployapi.Deployment) {
 	ctx := kapi.WithNamespace(kapi.NewContext(), config.Namespace)
 	newConfig, err := dc.ChangeStrategy.GenerateDeploymentConfig(ctx, config.ID)
@@ -154,6 +154,34 @@ func TestChangeWithoutTemplateDiff(t *testing.T) {
 	}
 }
 
+func TestChangeWithDeploymentInOtherNamespace(t *testing.T) {
+	generated := false
+
+	deployment := matchingInitialDeployment()
+	deployment.Namespace = "other"
+	controller := &DeploymentConfigChangeController{
+		ChangeStrategy: &testChangeStrategy{
+			GenerateDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				generated = true
+				return generatedExistingConfig(), nil
+			},
+			UpdateDeploymentConfigFunc: func(config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				return config, nil
+			},
+		},
+		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
+			return diffedConfig()
+		},
+		DeploymentStore: deploytest.NewFakeDeploymentStore(deployment),
+	}
+
+	controller.HandleDeploymentConfig()
+
+	if generated {
+		t.Error("Unexpected generation of deploymentConfig for a deployment in another namespace")
+	}
+}
+
 type testChangeStrategy struct {
 	GenerateDeploymentConfigFunc func(id string) (*deployapi.DeploymentConfig, error)
 	UpdateDeploymentConfigFunc   func(config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error)
@@ -296,7 +324,8 @@ func generatedConfig() *deployapi.DeploymentConfig {
 
 func matchingInitialDeployment() *deployapi.Deployment {
 	return &deployapi.Deployment{
-		TypeMeta: kapi.TypeMeta{ID: "test-deploy-config-1"},
+		TypeMeta: kapi.TypeMeta{ID: "test-deploy-config-2"},
+		Labels:   map[string]string{deployapi.DeploymentConfigLabel: "test-deploy-config"},
 		Status:   deployapi.DeploymentStatusNew,
 		ControllerTemplate: kapi.ReplicationControllerState{
 			Replicas: 1,
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -9,6 +11,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 // CustomPodDeploymentController implements the DeploymentStrategyTypeCustomPod deployment strategy.
@@ -23,6 +26,15 @@ type CustomPodDeploymentController struct {
 	DeploymentStore     cache.Store
 	DefaultImage        string
 	UseLocalImages      bool
+	// TokenIssuer, when set, issues the token deployer pods authenticate with, which is limited to
+	// the namespace of their deployment.
+	TokenIssuer TokenIssuer
+}
+
+// TokenIssuer issues tokens which authenticate deployer pods with the master.
+type TokenIssuer interface {
+	// IssueToken returns a token limited to namespace.
+	IssueToken(namespace string) (string, error)
 }
 
 type dcDeploymentInterface interface {
@@ -65,14 +77,25 @@ func (dc *CustomPodDeploymentController) HandleDeployment() error {
 		return nil
 	}
 
-	deploymentPod := dc.makeDeploymentPod(deployment)
-	glog.V(2).Infof("Attempting to create deployment pod: %+v", deploymentPod)
-	if pod, err := dc.PodInterface.CreatePod(kapi.NewContext(), deploymentPod); err != nil {
+	deploymentPod, err := dc.makeDeploymentPod(deployment)
+	if err != nil {
+		glog.V(2).Infof("Unable to make the deployment pod of deployment %s: %v", deployment.ID, err)
+		message := fmt.Sprintf("Unable to make deployment pod: %v", err)
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventFailed, "%s", message)
+		deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusFailed, message)
+		return dc.saveDeployment(ctx, deployment)
+	}
+
+	glog.V(2).Infof("Attempting to create deployment pod %s", deploymentPod.ID)
+	if pod, err := dc.PodInterface.CreatePod(ctx, deploymentPod); err != nil {
 		glog.V(2).Infof("Received error creating pod: %v", err)
-		deployment.Status = deployapi.DeploymentStatusFailed
+		message := fmt.Sprintf("Unable to create deployment pod %s: %v", deploymentPod.ID, err)
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventFailed, "%s", message)
+		deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusFailed, message)
 	} else {
-		glog.V(4).Infof("Successfully created pod %+v", pod)
-		deployment.Status = deployapi.DeploymentStatusPending
+		glog.V(4).Infof("Successfully created pod %s", pod.ID)
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventPodCreated, "Created deployment pod %s", deploymentPod.ID)
+		deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusPending, "")
 	}
 
 	return dc.saveDeployment(ctx, deployment)
@@ -84,58 +107,68 @@ func (dc *CustomPodDeploymentController) HandlePod() error {
 	glog.V(2).Infof("Synchronizing pod id: %v status: %v", pod.ID, pod.CurrentState.Status)
 
 	// assumption: filter prevents this label from not being present
-	id := pod.Labels["deployment"]
+	id := pod.Labels[deployapi.DeploymentLabel]
 	obj, exists := dc.DeploymentStore.Get(id)
 	if !exists {
 		return kerrors.NewNotFound("Deployment", id)
 	}
 	deployment := obj.(*deployapi.Deployment)
 
-	if deployment.Status == deployapi.DeploymentStatusComplete || deployment.Status == deployapi.DeploymentStatusFailed {
+	if deployutil.IsTerminatedDeployment(deployment) {
 		return nil
 	}
-	currentDeploymentStatus := deployment.Status
+	nextStatus := deployment.Status
+	message := ""
 
 	switch pod.CurrentState.Status {
 	case kapi.PodRunning:
-		deployment.Status = deployapi.DeploymentStatusRunning
+		nextStatus = deployapi.DeploymentStatusRunning
 	case kapi.PodTerminated:
-		deployment.Status = dc.inspectTerminatedDeploymentPod(deployment, pod)
+		nextStatus, message = dc.inspectTerminatedDeploymentPod(deployment, pod)
 	}
 
-	if currentDeploymentStatus != deployment.Status {
+	if deployment.Status != nextStatus {
+		deployutil.SetDeploymentStatus(deployment, nextStatus, message)
 		return dc.saveDeployment(ctx, deployment)
 	}
 
 	return nil
 }
 
-func deploymentPodID(deployment *deployapi.Deployment) string {
-	return "deploy-" + deployment.ID
-}
-
-func (dc *CustomPodDeploymentController) inspectTerminatedDeploymentPod(deployment *deployapi.Deployment, pod *kapi.Pod) deployapi.DeploymentStatus {
+// inspectTerminatedDeploymentPod returns the next status of the deployment based on the exit codes of
+// the deployment pod containers, along with a message explaining any failure.
+func (dc *CustomPodDeploymentController) inspectTerminatedDeploymentPod(deployment *deployapi.Deployment, pod *kapi.Pod) (deployapi.DeploymentStatus, string) {
 	nextStatus := deployment.Status
 	if pod.CurrentState.Status != kapi.PodTerminated {
 		glog.V(2).Infof("The deployment has not yet finished. Pod status is %s. Continuing", pod.CurrentState.Status)
-		return nextStatus
+		return nextStatus, ""
 	}
 
 	nextStatus = deployapi.DeploymentStatusComplete
-	for _, info := range pod.CurrentState.Info {
+	message := ""
+	for name, info := range pod.CurrentState.Info {
 		if info.State.Termination != nil && info.State.Termination.ExitCode != 0 {
 			nextStatus = deployapi.DeploymentStatusFailed
+			// the deployer may already have recorded the reason for the failure
+			if len(deployment.StatusMessage) > 0 {
+				continue
+			}
+			message = fmt.Sprintf("Deployment pod container %s exited with code %d", name, info.State.Termination.ExitCode)
+			if len(info.State.Termination.Reason) > 0 {
+				message += ": " + info.State.Termination.Reason
+			}
+			deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventFailed, "%s", message)
 		}
 	}
 
 	if nextStatus == deployapi.DeploymentStatusComplete {
-		podID := deploymentPodID(deployment)
+		podID := deployutil.DeployerPodIDForDeployment(deployment)
 		glog.V(2).Infof("Removing deployment pod for ID %v", podID)
-		dc.PodInterface.DeletePod(kapi.NewContext(), podID)
+		dc.PodInterface.DeletePod(kapi.WithNamespace(kapi.NewContext(), deployment.Namespace), podID)
 	}
 
-	glog.V(4).Infof("The deployment pod has finished. Setting deployment state to %s", deployment.Status)
-	return nextStatus
+	glog.V(4).Infof("The deployment pod has finished. Setting deployment state to %s", nextStatus)
+	return nextStatus, message
 }
 
 func (dc *CustomPodDeploymentController) saveDeployment(ctx kapi.Context, deployment *deployapi.Deployment) error {
@@ -147,11 +180,24 @@ func (dc *CustomPodDeploymentController) saveDeployment(ctx kapi.Context, deploy
 	return err
 }
 
-func (dc *CustomPodDeploymentController) makeDeploymentPod(deployment *deployapi.Deployment) *kapi.Pod {
-	podID := deploymentPodID(deployment)
+// makeDeploymentPod returns the pod which executes the deployment. Deployer pods only act within the
+// namespace of their deployment, so deployments without a namespace are refused.
+func (dc *CustomPodDeploymentController) makeDeploymentPod(deployment *deployapi.Deployment) (*kapi.Pod, error) {
+	if len(deployment.Namespace) == 0 {
+		return nil, fmt.Errorf("deployment %s has no namespace", deployment.ID)
+	}
+	podID := deployutil.DeployerPodIDForDeployment(deployment)
 
 	envVars := deployment.Strategy.CustomPod.Environment
 	envVars = append(envVars, kapi.EnvVar{Name: "KUBERNETES_DEPLOYMENT_ID", Value: deployment.ID})
+	envVars = append(envVars, kapi.EnvVar{Name: "KUBERNETES_DEPLOYMENT_NAMESPACE", Value: deployment.Namespace})
+	if dc.TokenIssuer != nil {
+		token, err := dc.TokenIssuer.IssueToken(deployment.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to issue a token for namespace %s: %v", deployment.Namespace, err)
+		}
+		envVars = append(envVars, kapi.EnvVar{Name: "BEARER_TOKEN", Value: token})
+	}
 	for _, env := range dc.Environment {
 		envVars = append(envVars, env)
 	}
@@ -163,7 +209,8 @@ func (dc *CustomPodDeploymentController) makeDeploymentPod(deployment *deployapi
 
 	pod := &kapi.Pod{
 		TypeMeta: kapi.TypeMeta{
-			ID: podID,
+			ID:        podID,
+			Namespace: deployment.Namespace,
 		},
 		DesiredState: kapi.PodState{
 			Manifest: kapi.ContainerManifest{
@@ -181,12 +228,12 @@ func (dc *CustomPodDeploymentController) makeDeploymentPod(deployment *deployapi
 			},
 		},
 		Labels: map[string]string{
-			"deployment": deployment.ID,
+			deployapi.DeploymentLabel: deployment.ID,
 		},
 	}
 	if dc.UseLocalImages {
 		pod.DesiredState.Manifest.Containers[0].ImagePullPolicy = kapi.PullIfNotPresent
 	}
 
-	return pod
+	return pod, nil
 }
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"fmt"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -29,11 +30,22 @@ func (i *testDcPodInterface) DeletePod(ctx kapi.Context, id string) error {
 	return i.DeletePodFunc(id)
 }
 
+type testTokenIssuer struct {
+	namespaces []string
+	err        error
+}
+
+func (i *testTokenIssuer) IssueToken(namespace string) (string, error) {
+	i.namespaces = append(i.namespaces, namespace)
+	return "token-" + namespace, i.err
+}
+
 func TestHandleNew(t *testing.T) {
 	var (
 		updatedDeployment *deployapi.Deployment
 		createdPod        *kapi.Pod
 	)
+	tokens := &testTokenIssuer{}
 
 	controller := &CustomPodDeploymentController{
 		DeploymentInterface: &testDcDeploymentInterface{
@@ -50,9 +62,11 @@ func TestHandleNew(t *testing.T) {
 		},
 		NextDeployment: func() *deployapi.Deployment {
 			deployment := customPodDeployment()
+			deployment.Namespace = "test"
 			deployment.Status = deployapi.DeploymentStatusNew
 			return deployment
 		},
+		TokenIssuer: tokens,
 	}
 
 	// Verify pending -> running now that the pod is running
@@ -69,6 +83,77 @@ func TestHandleNew(t *testing.T) {
 	if createdPod == nil {
 		t.Fatalf("expected a pod to be created")
 	}
+
+	if e, a := updatedDeployment.Namespace, createdPod.Namespace; e != a {
+		t.Fatalf("expected pod namespace %s, got %s", e, a)
+	}
+
+	env := map[string]string{}
+	for _, e := range createdPod.DesiredState.Manifest.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if e, a := updatedDeployment.Namespace, env["KUBERNETES_DEPLOYMENT_NAMESPACE"]; e != a {
+		t.Fatalf("expected KUBERNETES_DEPLOYMENT_NAMESPACE %s, got %s", e, a)
+	}
+	if e, a := "token-test", env["BEARER_TOKEN"]; e != a {
+		t.Fatalf("expected BEARER_TOKEN %s, got %s", e, a)
+	}
+	if e, a := []string{"test"}, tokens.namespaces; len(a) != 1 || e[0] != a[0] {
+		t.Fatalf("expected a token to be issued for namespaces %v, got %v", e, a)
+	}
+
+	if updatedDeployment.StartTimestamp.IsZero() {
+		t.Fatalf("expected a start timestamp to be set")
+	}
+
+	if e, a := 1, len(updatedDeployment.Events); e != a {
+		t.Fatalf("expected %d deployment events, got %d", e, a)
+	}
+
+	if e, a := deployapi.DeploymentEventPodCreated, updatedDeployment.Events[0].Type; e != a {
+		t.Fatalf("expected event type %s, got %s", e, a)
+	}
+}
+
+func TestHandleNewRefused(t *testing.T) {
+	testCases := map[string]struct {
+		namespace string
+		tokens    *testTokenIssuer
+	}{
+		"no namespace":       {"", &testTokenIssuer{}},
+		"token not issuable": {"test", &testTokenIssuer{err: fmt.Errorf("etcd down")}},
+	}
+
+	for name, testCase := range testCases {
+		var updatedDeployment *deployapi.Deployment
+		controller := &CustomPodDeploymentController{
+			DeploymentInterface: &testDcDeploymentInterface{
+				UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+					updatedDeployment = deployment
+					return deployment, nil
+				},
+			},
+			PodInterface: &testDcPodInterface{
+				CreatePodFunc: func(pod *kapi.Pod) (*kapi.Pod, error) {
+					t.Fatalf("%s: unexpected creation of pod %#v", name, pod)
+					return nil, nil
+				},
+			},
+			NextDeployment: func() *deployapi.Deployment {
+				deployment := customPodDeployment()
+				deployment.Namespace = testCase.namespace
+				deployment.Status = deployapi.DeploymentStatusNew
+				return deployment
+			},
+			TokenIssuer: testCase.tokens,
+		}
+
+		controller.HandleDeployment()
+
+		if updatedDeployment == nil || updatedDeployment.Status != deployapi.DeploymentStatusFailed {
+			t.Errorf("%s: expected the deployment to fail, got %#v", name, updatedDeployment)
+		}
+	}
 }
 
 func TestHandleNewDeploymentWrongType(t *testing.T) {
@@ -191,6 +276,14 @@ func TestHandlePodTerminatedNotOk(t *testing.T) {
 	if e, a := deployapi.DeploymentStatusFailed, updatedDeployment.Status; e != a {
 		t.Fatalf("expected updated deployment status %s, got %s", e, a)
 	}
+
+	if len(updatedDeployment.StatusMessage) == 0 {
+		t.Fatalf("expected a status message explaining the failure")
+	}
+
+	if updatedDeployment.CompletionTimestamp.IsZero() {
+		t.Fatalf("expected a completion timestamp to be set")
+	}
 }
 
 func basicDeployment() *deployapi.Deployment {
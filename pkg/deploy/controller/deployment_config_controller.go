@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"fmt"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/golang/glog"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
@@ -11,12 +14,23 @@ import (
 
 // DeploymentConfigController is responsible for creating a Deployment when a DeploymentConfig is
 // updated with a new LatestVersion. Any deployment created is correlated to a DeploymentConfig
-// by setting the DeploymentConfigLabel on the deployment.
+// by setting the DeploymentConfigLabel on the deployment. No deployment is created while the
+// DeploymentConfig is paused; the latest version is deployed once it is resumed.
+//
+// DeploymentConfigs are processed by ID from a work queue. A config which fails to sync is retried
+// with an increasing delay, and once MaxRetries attempts have failed the error is recorded as a
+// DeploymentConfigConditionDeployFailed condition on the config.
 type DeploymentConfigController struct {
-	DeploymentInterface deploymentInterface
-
-	// Blocks until the next DeploymentConfig is available
-	NextDeploymentConfig func() *deployapi.DeploymentConfig
+	DeploymentInterface       deploymentInterface
+	DeploymentConfigInterface dcDeploymentConfigInterface
+
+	// Queue yields the IDs of the DeploymentConfigs to sync.
+	Queue workQueue
+	// DeploymentConfigStore holds the current state of the DeploymentConfigs in the queue.
+	DeploymentConfigStore cache.Store
+	// MaxRetries is the number of consecutive failures to sync a DeploymentConfig which are retried
+	// before the failure is recorded on the config.
+	MaxRetries int
 }
 
 type deploymentInterface interface {
@@ -24,30 +38,101 @@ type deploymentInterface interface {
 	CreateDeployment(ctx kapi.Context, deployment *deployapi.Deployment) (*deployapi.Deployment, error)
 }
 
+type dcDeploymentConfigInterface interface {
+	UpdateDeploymentConfig(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error)
+}
+
+type workQueue interface {
+	Pop() string
+	Done(id string)
+	Retry(id string)
+	Forget(id string)
+	Failures(id string) int
+}
+
 // Process DeploymentConfig events one at a time.
 func (c *DeploymentConfigController) Run() {
 	go util.Forever(c.HandleDeploymentConfig, 0)
 }
 
-// Process a single DeploymentConfig event.
+// Process a single DeploymentConfig from the queue.
 func (c *DeploymentConfigController) HandleDeploymentConfig() {
-	config := c.NextDeploymentConfig()
+	id := c.Queue.Pop()
+	defer c.Queue.Done(id)
+
+	obj, exists := c.DeploymentConfigStore.Get(id)
+	if !exists {
+		glog.V(4).Infof("DeploymentConfig %s no longer exists", id)
+		c.Queue.Forget(id)
+		return
+	}
+	config := obj.(*deployapi.DeploymentConfig)
 	ctx := kapi.WithNamespace(kapi.NewContext(), config.Namespace)
+
+	err := c.sync(ctx, config)
+	if err == nil {
+		c.Queue.Forget(id)
+		c.updateCondition(ctx, config, nil)
+		return
+	}
+
+	if failures := c.Queue.Failures(id); failures < c.MaxRetries {
+		glog.V(2).Infof("Error syncing deploymentConfig %s (attempt %d), retrying: %v", id, failures+1, err)
+		c.Queue.Retry(id)
+		return
+	}
+
+	glog.V(2).Infof("Giving up syncing deploymentConfig %s after %d attempts: %v", id, c.MaxRetries+1, err)
+	c.Queue.Forget(id)
+	c.updateCondition(ctx, config, err)
+}
+
+// sync creates the latest deployment of the config if it should be deployed.
+func (c *DeploymentConfigController) sync(ctx kapi.Context, config *deployapi.DeploymentConfig) error {
 	deploy, err := c.shouldDeploy(ctx, config)
 	if err != nil {
-		// TODO: better error handling
-		glog.V(2).Infof("Error determining whether to redeploy deploymentConfig %v: %#v", config.ID, err)
-		return
+		return fmt.Errorf("couldn't determine whether to deploy: %v", err)
 	}
 
 	if !deploy {
 		glog.V(4).Infof("Won't deploy from config %s", config.ID)
-		return
+		return nil
+	}
+
+	return c.deploy(ctx, config)
+}
+
+// updateCondition records the error as the DeploymentConfigConditionDeployFailed condition of the
+// config, or clears the condition if err is nil. The config is only saved if the condition changes.
+func (c *DeploymentConfigController) updateCondition(ctx kapi.Context, config *deployapi.DeploymentConfig, err error) {
+	conditions := []deployapi.DeploymentConfigCondition{}
+	var existing *deployapi.DeploymentConfigCondition
+	for i := range config.Conditions {
+		if config.Conditions[i].Type == deployapi.DeploymentConfigConditionDeployFailed {
+			existing = &config.Conditions[i]
+			continue
+		}
+		conditions = append(conditions, config.Conditions[i])
 	}
 
-	err = c.deploy(ctx, config)
+	if err == nil && existing == nil {
+		return
+	}
 	if err != nil {
-		glog.V(2).Infof("Error deploying config %s: %v", config.ID, err)
+		if existing != nil && existing.Message == err.Error() {
+			return
+		}
+		conditions = append(conditions, deployapi.DeploymentConfigCondition{
+			Type:      deployapi.DeploymentConfigConditionDeployFailed,
+			Message:   err.Error(),
+			Timestamp: util.Now(),
+		})
+	}
+
+	newConfig := *config
+	newConfig.Conditions = conditions
+	if _, err := c.DeploymentConfigInterface.UpdateDeploymentConfig(ctx, &newConfig); err != nil {
+		glog.V(2).Infof("Error updating the conditions of deploymentConfig %s: %v", config.ID, err)
 	}
 }
 
@@ -58,6 +143,11 @@ func (c *DeploymentConfigController) shouldDeploy(ctx kapi.Context, config *depl
 		return false, nil
 	}
 
+	if config.Paused {
+		glog.V(4).Infof("Shouldn't deploy config %s with LatestVersion=%d because it is paused", config.ID, config.LatestVersion)
+		return false, nil
+	}
+
 	deployment, err := c.latestDeploymentForConfig(ctx, config)
 	if deployment != nil {
 		glog.V(4).Infof("Shouldn't deploy because a deployment '%s' already exists for latest config %s", deployment.ID, config.ID)
@@ -106,6 +196,7 @@ func (c *DeploymentConfigController) deploy(ctx kapi.Context, config *deployapi.
 		Strategy:           config.Template.Strategy,
 		ControllerTemplate: config.Template.ControllerTemplate,
 		Details:            config.Details,
+		Images:             deployutil.DeploymentImagesForConfig(config),
 	}
 
 	glog.V(4).Infof("Creating new deployment from config %s", config.ID)
@@ -1,10 +1,14 @@
 package controller
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	oscache "github.com/openshift/origin/pkg/client/cache"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 )
 
@@ -21,7 +25,19 @@ func (i *testDeploymentInterface) CreateDeployment(ctx kapi.Context, deployment
 	return i.CreateDeploymentFunc(deployment)
 }
 
+type testDcDeploymentConfigInterface struct {
+	UpdateDeploymentConfigFunc func(config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error)
+}
+
+func (i *testDcDeploymentConfigInterface) UpdateDeploymentConfig(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+	return i.UpdateDeploymentConfigFunc(config)
+}
+
 func TestHandleNewDeploymentConfig(t *testing.T) {
+	deploymentConfig := manualDeploymentConfig()
+	deploymentConfig.LatestVersion = 0
+	queue, store := queuedConfig(deploymentConfig)
+
 	controller := &DeploymentConfigController{
 		DeploymentInterface: &testDeploymentInterface{
 			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
@@ -33,11 +49,8 @@ func TestHandleNewDeploymentConfig(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			deploymentConfig := manualDeploymentConfig()
-			deploymentConfig.LatestVersion = 0
-			return deploymentConfig
-		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
 	}
 
 	controller.HandleDeploymentConfig()
@@ -48,6 +61,7 @@ func TestHandleInitialDeployment(t *testing.T) {
 	deploymentConfig.LatestVersion = 1
 
 	var deployed *deployapi.Deployment
+	queue, store := queuedConfig(deploymentConfig)
 
 	controller := &DeploymentConfigController{
 		DeploymentInterface: &testDeploymentInterface{
@@ -59,9 +73,8 @@ func TestHandleInitialDeployment(t *testing.T) {
 				return deployment, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return deploymentConfig
-		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
 	}
 
 	controller.HandleDeploymentConfig()
@@ -75,7 +88,49 @@ func TestHandleInitialDeployment(t *testing.T) {
 	}
 }
 
+func TestHandlePausedDeploymentConfig(t *testing.T) {
+	deploymentConfig := manualDeploymentConfig()
+	deploymentConfig.LatestVersion = 2
+	deploymentConfig.Paused = true
+
+	var deployed *deployapi.Deployment
+	queue, store := queuedConfig(deploymentConfig)
+
+	controller := &DeploymentConfigController{
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return nil, kerrors.NewNotFound("deployment", id)
+			},
+			CreateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				deployed = deployment
+				return deployment, nil
+			},
+		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
+	}
+
+	controller.HandleDeploymentConfig()
+
+	if deployed != nil {
+		t.Fatalf("unexpected deployment of paused config: %#v", deployed)
+	}
+
+	// the pending version is deployed once the config is resumed
+	deploymentConfig.Paused = false
+	queue.Add(deploymentConfig.ID, deploymentConfig)
+	controller.HandleDeploymentConfig()
+
+	if deployed == nil {
+		t.Fatalf("expected a deployment")
+	}
+}
+
 func TestHandleConfigChangeNoPodTemplateDiff(t *testing.T) {
+	deploymentConfig := manualDeploymentConfig()
+	deploymentConfig.LatestVersion = 0
+	queue, store := queuedConfig(deploymentConfig)
+
 	controller := &DeploymentConfigController{
 		DeploymentInterface: &testDeploymentInterface{
 			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
@@ -86,11 +141,8 @@ func TestHandleConfigChangeNoPodTemplateDiff(t *testing.T) {
 				return nil, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			deploymentConfig := manualDeploymentConfig()
-			deploymentConfig.LatestVersion = 0
-			return deploymentConfig
-		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
 	}
 
 	controller.HandleDeploymentConfig()
@@ -102,6 +154,7 @@ func TestHandleConfigChangeWithPodTemplateDiff(t *testing.T) {
 	deploymentConfig.Template.ControllerTemplate.PodTemplate.Labels["foo"] = "bar"
 
 	var deployed *deployapi.Deployment
+	queue, store := queuedConfig(deploymentConfig)
 
 	controller := &DeploymentConfigController{
 		DeploymentInterface: &testDeploymentInterface{
@@ -113,9 +166,8 @@ func TestHandleConfigChangeWithPodTemplateDiff(t *testing.T) {
 				return deployment, nil
 			},
 		},
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return deploymentConfig
-		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
 	}
 
 	controller.HandleDeploymentConfig()
@@ -129,6 +181,100 @@ func TestHandleConfigChangeWithPodTemplateDiff(t *testing.T) {
 	}
 }
 
+func TestHandleDeploymentConfigRetriesFailures(t *testing.T) {
+	deploymentConfig := manualDeploymentConfig()
+	deploymentConfig.LatestVersion = 1
+	queue, store := queuedConfig(deploymentConfig)
+
+	attempts := 0
+	var updated *deployapi.DeploymentConfig
+	controller := &DeploymentConfigController{
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return nil, kerrors.NewNotFound("deployment", id)
+			},
+			CreateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				attempts++
+				return nil, fmt.Errorf("unable to create deployment")
+			},
+		},
+		DeploymentConfigInterface: &testDcDeploymentConfigInterface{
+			UpdateDeploymentConfigFunc: func(config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				updated = config
+				return config, nil
+			},
+		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
+		MaxRetries:            2,
+	}
+
+	// the failed config is queued again after a backoff, so each call blocks until the retry
+	for i := 0; i < 3; i++ {
+		if updated != nil {
+			t.Fatalf("unexpected update after %d attempts: %#v", attempts, updated)
+		}
+		controller.HandleDeploymentConfig()
+	}
+
+	if e, a := 3, attempts; e != a {
+		t.Fatalf("expected %d attempts, got %d", e, a)
+	}
+	if updated == nil || len(updated.Conditions) != 1 {
+		t.Fatalf("expected a failure condition to be recorded, got %#v", updated)
+	}
+	if e, a := deployapi.DeploymentConfigConditionDeployFailed, updated.Conditions[0].Type; e != a {
+		t.Fatalf("expected condition %s, got %s", e, a)
+	}
+	if failures := queue.Failures(deploymentConfig.ID); failures != 0 {
+		t.Fatalf("expected the failures to be forgotten, got %d", failures)
+	}
+}
+
+func TestHandleDeploymentConfigClearsFailure(t *testing.T) {
+	deploymentConfig := manualDeploymentConfig()
+	deploymentConfig.LatestVersion = 1
+	deploymentConfig.Conditions = []deployapi.DeploymentConfigCondition{
+		{Type: deployapi.DeploymentConfigConditionDeployFailed, Message: "unable to create deployment"},
+	}
+	queue, store := queuedConfig(deploymentConfig)
+
+	var updated *deployapi.DeploymentConfig
+	controller := &DeploymentConfigController{
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return nil, kerrors.NewNotFound("deployment", id)
+			},
+			CreateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				return deployment, nil
+			},
+		},
+		DeploymentConfigInterface: &testDcDeploymentConfigInterface{
+			UpdateDeploymentConfigFunc: func(config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				updated = config
+				return config, nil
+			},
+		},
+		Queue:                 queue,
+		DeploymentConfigStore: store,
+	}
+
+	controller.HandleDeploymentConfig()
+
+	if updated == nil || len(updated.Conditions) != 0 {
+		t.Fatalf("expected the failure condition to be cleared, got %#v", updated)
+	}
+}
+
+// queuedConfig returns a work queue holding the ID of the config and a store holding the config.
+func queuedConfig(config *deployapi.DeploymentConfig) (*oscache.WorkQueue, cache.Store) {
+	queue := oscache.NewWorkQueue(time.Millisecond, 10*time.Millisecond)
+	queue.Add(config.ID, config)
+	store := cache.NewStore()
+	store.Add(config.ID, config)
+	return queue, store
+}
+
 func manualDeploymentConfig() *deployapi.DeploymentConfig {
 	return &deployapi.DeploymentConfig{
 		TypeMeta: kapi.TypeMeta{ID: "manual-deploy-config"},
@@ -1,6 +1,8 @@
 package factory
 
 import (
+	"time"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
@@ -9,26 +11,71 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	osclient "github.com/openshift/origin/pkg/client"
+	oscache "github.com/openshift/origin/pkg/client/cache"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	controller "github.com/openshift/origin/pkg/deploy/controller"
+	"github.com/openshift/origin/pkg/deploy/metrics"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
-// DeploymentConfigControllerFactory can create a DeploymentConfigController which obtains
-// DeploymentConfigs from a queue populated from a watch of all DeploymentConfigs.
+// SharedCaches holds the caches of DeploymentConfigs and Deployments shared by the deploy controllers,
+// so that each resource is listed and watched once however many controllers consume it.
+// DeploymentConfigs are indexed by controller.ImageRepositoryIndex and Deployments by
+// controller.DeploymentConfigIndex.
+type SharedCaches struct {
+	DeploymentConfigs *oscache.Informer
+	Deployments       *oscache.Informer
+}
+
+// NewSharedCaches creates caches of all DeploymentConfigs and Deployments. The caches start watching
+// when the first controller using them is created.
+func NewSharedCaches(client osclient.Interface) *SharedCaches {
+	return &SharedCaches{
+		DeploymentConfigs: oscache.NewInformer(&deploymentConfigLW{client}, &deployapi.DeploymentConfig{}, oscache.Indexers{
+			controller.ImageRepositoryIndex: controller.IndexDeploymentConfigByImageRepository,
+		}),
+		Deployments: oscache.NewInformer(&deploymentLW{client: client, field: labels.Everything()}, &deployapi.Deployment{}, oscache.Indexers{
+			controller.DeploymentConfigIndex: controller.IndexDeploymentByConfig,
+		}),
+	}
+}
+
+// deploymentConfigQueue returns a queue of all DeploymentConfigs fed by the shared cache.
+func (c *SharedCaches) deploymentConfigQueue() *cache.FIFO {
+	queue := cache.NewFIFO()
+	c.DeploymentConfigs.AddQueue(queue, nil)
+	c.DeploymentConfigs.Run()
+	return queue
+}
+
+// deploymentQueue returns a queue of the Deployments with the given strategy fed by the shared cache.
+func (c *SharedCaches) deploymentQueue(strategy deployapi.DeploymentStrategyType) *cache.FIFO {
+	queue := cache.NewFIFO()
+	c.Deployments.AddQueue(queue, func(obj interface{}) bool {
+		return obj.(*deployapi.Deployment).Strategy.Type == strategy
+	})
+	c.Deployments.Run()
+	return queue
+}
+
+// DeploymentConfigControllerFactory can create a DeploymentConfigController which obtains the IDs
+// of DeploymentConfigs from a rate limited work queue populated from a watch of all DeploymentConfigs.
 type DeploymentConfigControllerFactory struct {
 	Client *osclient.Client
+	Caches *SharedCaches
 }
 
 func (factory *DeploymentConfigControllerFactory) Create() *controller.DeploymentConfigController {
-	queue := cache.NewFIFO()
-	cache.NewReflector(&deploymentConfigLW{factory.Client}, &deployapi.DeploymentConfig{}, queue).Run()
+	queue := oscache.NewWorkQueue(1*time.Second, 5*time.Minute)
+	factory.Caches.DeploymentConfigs.AddQueue(queue, nil)
+	factory.Caches.DeploymentConfigs.Run()
 
 	return &controller.DeploymentConfigController{
-		DeploymentInterface: factory.Client,
-		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
-			return queue.Pop().(*deployapi.DeploymentConfig)
-		},
+		DeploymentInterface:       factory.Client,
+		DeploymentConfigInterface: factory.Client,
+		Queue:                     queue,
+		DeploymentConfigStore:     factory.Caches.DeploymentConfigs.Store(),
+		MaxRetries:                5,
 	}
 }
 
@@ -37,12 +84,11 @@ func (factory *DeploymentConfigControllerFactory) Create() *controller.Deploymen
 type BasicDeploymentControllerFactory struct {
 	Client     *osclient.Client
 	KubeClient *kclient.Client
+	Caches     *SharedCaches
 }
 
 func (factory *BasicDeploymentControllerFactory) Create() *controller.BasicDeploymentController {
-	field := labels.SelectorFromSet(labels.Set{"Strategy": string(deployapi.DeploymentStrategyTypeBasic)})
-	queue := cache.NewFIFO()
-	cache.NewReflector(&deploymentLW{client: factory.Client, field: field}, &deployapi.Deployment{}, queue).Run()
+	queue := factory.Caches.deploymentQueue(deployapi.DeploymentStrategyTypeBasic)
 
 	return &controller.BasicDeploymentController{
 		DeploymentUpdater:           factory.Client,
@@ -53,23 +99,46 @@ func (factory *BasicDeploymentControllerFactory) Create() *controller.BasicDeplo
 	}
 }
 
+// RecreateDeploymentControllerFactory can create a RecreateDeploymentController which obtains Deployments
+// from a queue populated from a watch of Deployments whose strategy is DeploymentStrategyTypeRecreate.
+type RecreateDeploymentControllerFactory struct {
+	Client       *osclient.Client
+	KubeClient   *kclient.Client
+	Caches       *SharedCaches
+	PollInterval time.Duration
+	Timeout      time.Duration
+}
+
+func (factory *RecreateDeploymentControllerFactory) Create() *controller.RecreateDeploymentController {
+	queue := factory.Caches.deploymentQueue(deployapi.DeploymentStrategyTypeRecreate)
+
+	return &controller.RecreateDeploymentController{
+		DeploymentUpdater:           factory.Client,
+		ReplicationControllerClient: factory.KubeClient,
+		PodClient:                   factory.KubeClient,
+		NextDeployment: func() *deployapi.Deployment {
+			return queue.Pop().(*deployapi.Deployment)
+		},
+		PollInterval: factory.PollInterval,
+		Timeout:      factory.Timeout,
+	}
+}
+
 // CustomPodDeploymentControllerFactory can create a CustomPodDeploymentController which obtains Deployments
 // from a queue populated from a watch of Deployments whose strategy is DeploymentStrategyTypeCustomPod.
 // Pods are obtained from a queue populated from a watch of all pods.
 type CustomPodDeploymentControllerFactory struct {
 	Client         *osclient.Client
 	KubeClient     *kclient.Client
+	Caches         *SharedCaches
 	Environment    []kapi.EnvVar
 	DefaultImage   string
 	UseLocalImages bool
+	TokenIssuer    controller.TokenIssuer
 }
 
 func (factory *CustomPodDeploymentControllerFactory) Create() *controller.CustomPodDeploymentController {
-	deploymentFieldSelector := labels.SelectorFromSet(labels.Set{"Strategy": string(deployapi.DeploymentStrategyTypeCustomPod)})
-	dQueue := cache.NewFIFO()
-	cache.NewReflector(&deploymentLW{client: factory.Client, field: deploymentFieldSelector}, &deployapi.Deployment{}, dQueue).Run()
-	dStore := cache.NewStore()
-	cache.NewReflector(&deploymentLW{client: factory.Client, field: deploymentFieldSelector}, &deployapi.Deployment{}, dStore).Run()
+	dQueue := factory.Caches.deploymentQueue(deployapi.DeploymentStrategyTypeCustomPod)
 	pQueue := cache.NewFIFO()
 	pSelector, _ := labels.ParseSelector("deployment!=")
 	cache.NewReflector(&podLW{client: factory.KubeClient, labelSelector: pSelector}, &kapi.Pod{}, pQueue).Run()
@@ -84,9 +153,10 @@ func (factory *CustomPodDeploymentControllerFactory) Create() *controller.Custom
 		NextPod: func() *kapi.Pod {
 			return pQueue.Pop().(*kapi.Pod)
 		},
-		DeploymentStore: dStore,
+		DeploymentStore: factory.Caches.Deployments.Store(),
 		DefaultImage:    factory.DefaultImage,
 		UseLocalImages:  factory.UseLocalImages,
+		TokenIssuer:     factory.TokenIssuer,
 	}
 }
 
@@ -94,21 +164,19 @@ func (factory *CustomPodDeploymentControllerFactory) Create() *controller.Custom
 // from a queue populated from a watch of all DeploymentConfigs.
 type DeploymentConfigChangeControllerFactory struct {
 	Client osclient.Interface
+	Caches *SharedCaches
 }
 
 func (factory *DeploymentConfigChangeControllerFactory) Create() *controller.DeploymentConfigChangeController {
-	queue := cache.NewFIFO()
-	cache.NewReflector(&deploymentConfigLW{factory.Client}, &deployapi.DeploymentConfig{}, queue).Run()
-
-	store := cache.NewStore()
-	cache.NewReflector(&deploymentLW{client: factory.Client, field: labels.Everything()}, &deployapi.Deployment{}, store).Run()
+	queue := factory.Caches.deploymentConfigQueue()
+	factory.Caches.Deployments.Run()
 
 	return &controller.DeploymentConfigChangeController{
 		ChangeStrategy: factory.Client,
 		NextDeploymentConfig: func() *deployapi.DeploymentConfig {
 			return queue.Pop().(*deployapi.DeploymentConfig)
 		},
-		DeploymentStore: store,
+		DeploymentStore: factory.Caches.Deployments.Store(),
 	}
 }
 
@@ -116,24 +184,44 @@ func (factory *DeploymentConfigChangeControllerFactory) Create() *controller.Dep
 // from a queue populated from a watch of all ImageRepositories.
 type ImageChangeControllerFactory struct {
 	Client *osclient.Client
+	Caches *SharedCaches
 }
 
 func (factory *ImageChangeControllerFactory) Create() *controller.ImageChangeController {
 	queue := cache.NewFIFO()
 	cache.NewReflector(&imageRepositoryLW{factory.Client}, &imageapi.ImageRepository{}, queue).Run()
-
-	store := cache.NewStore()
-	cache.NewReflector(&deploymentConfigLW{factory.Client}, &deployapi.DeploymentConfig{}, store).Run()
+	factory.Caches.DeploymentConfigs.Run()
 
 	return &controller.ImageChangeController{
 		DeploymentConfigInterface: factory.Client,
-		DeploymentConfigStore:     store,
+		DeploymentConfigStore:     factory.Caches.DeploymentConfigs.Store(),
 		NextImageRepository: func() *imageapi.ImageRepository {
 			return queue.Pop().(*imageapi.ImageRepository)
 		},
 	}
 }
 
+// DeploymentConfigAutoscalerControllerFactory can create a DeploymentConfigAutoscalerController which
+// evaluates the DeploymentConfigAutoscalers held by a store populated from a watch of all autoscalers.
+type DeploymentConfigAutoscalerControllerFactory struct {
+	Client   *osclient.Client
+	Metrics  *metrics.HTTPSource
+	Interval time.Duration
+}
+
+func (factory *DeploymentConfigAutoscalerControllerFactory) Create() *controller.DeploymentConfigAutoscalerController {
+	store := cache.NewStore()
+	cache.NewReflector(&deploymentConfigAutoscalerLW{factory.Client}, &deployapi.DeploymentConfigAutoscaler{}, store).Run()
+
+	return &controller.DeploymentConfigAutoscalerController{
+		AutoscalerInterface:       factory.Client,
+		DeploymentConfigInterface: factory.Client,
+		AutoscalerStore:           store,
+		Metrics:                   factory.Metrics,
+		Interval:                  factory.Interval,
+	}
+}
+
 // podLW is a ListWatcher implementation for pods.
 type podLW struct {
 	client        *kclient.Client
@@ -220,10 +308,25 @@ type imageRepositoryLW struct {
 
 // List lists all ImageRepositories.
 func (lw *imageRepositoryLW) List() (runtime.Object, error) {
-	return lw.client.ListImageRepositories(kapi.NewContext(), labels.Everything())
+	return lw.client.ListImageRepositories(kapi.NewContext(), labels.Everything(), labels.Everything())
 }
 
 // Watch watches all ImageRepositories.
 func (lw *imageRepositoryLW) Watch(resourceVersion string) (watch.Interface, error) {
 	return lw.client.WatchImageRepositories(kapi.NewContext(), labels.Everything(), labels.Everything(), "0")
 }
+
+// deploymentConfigAutoscalerLW is a ListWatcher for DeploymentConfigAutoscalers.
+type deploymentConfigAutoscalerLW struct {
+	client osclient.Interface
+}
+
+// List lists all DeploymentConfigAutoscalers.
+func (lw *deploymentConfigAutoscalerLW) List() (runtime.Object, error) {
+	return lw.client.ListDeploymentConfigAutoscalers(kapi.NewContext(), labels.Everything())
+}
+
+// Watch watches all DeploymentConfigAutoscalers.
+func (lw *deploymentConfigAutoscalerLW) Watch(resourceVersion string) (watch.Interface, error) {
+	return lw.client.WatchDeploymentConfigAutoscalers(kapi.NewContext(), labels.Everything(), labels.Everything(), resourceVersion)
+}
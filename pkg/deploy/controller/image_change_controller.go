@@ -1,25 +1,29 @@
 package controller
 
 import (
-	"strings"
+	"reflect"
 
 	"github.com/golang/glog"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
+	oscache "github.com/openshift/origin/pkg/client/cache"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
 // ImageChangeController watches for changes to ImageRepositories and regenerates
-// DeploymentConfigs when a new version of a tag referenced by a DeploymentConfig
-// is available.
+// DeploymentConfigs when a tag referenced by a DeploymentConfig refers to an image
+// other than the one its trigger last resolved to. New images for tags referenced by
+// triggers which aren't automatic are recorded as pending image updates on the
+// DeploymentConfig until approved.
 type ImageChangeController struct {
 	DeploymentConfigInterface icDeploymentConfigInterface
 	NextImageRepository       func() *imageapi.ImageRepository
-	DeploymentConfigStore     cache.Store
+	// DeploymentConfigStore is indexed by ImageRepositoryIndex.
+	DeploymentConfigStore oscache.Indexer
 }
 
 type icDeploymentConfigInterface interface {
@@ -40,35 +44,42 @@ func (c *ImageChangeController) HandleImageRepo() {
 
 	imageRepoCtx := kapi.WithNamespace(kapi.NewContext(), imageRepo.Namespace)
 
-	for _, c := range c.DeploymentConfigStore.List() {
-		config := c.(*deployapi.DeploymentConfig)
+	for _, obj := range c.DeploymentConfigStore.Index(ImageRepositoryIndex, imageRepo.DockerImageRepository) {
+		config := obj.(*deployapi.DeploymentConfig)
 		glog.V(4).Infof("Detecting changed images for deploymentConfig %s", config.ID)
 
 		// Extract relevant triggers for this imageRepo for this config
 		triggersForConfig := []deployapi.DeploymentTriggerImageChangeParams{}
 		for _, trigger := range config.Triggers {
 			if trigger.Type == deployapi.DeploymentTriggerOnImageChange &&
-				trigger.ImageChangeParams.Automatic &&
 				trigger.ImageChangeParams.RepositoryName == imageRepo.DockerImageRepository {
 				triggersForConfig = append(triggersForConfig, *trigger.ImageChangeParams)
 			}
 		}
 
+		pending := []deployapi.PendingImageUpdate{}
 		for _, params := range triggersForConfig {
 			glog.V(4).Infof("Processing image triggers for deploymentConfig %s", config.ID)
-			containerNames := util.NewStringSet(params.ContainerNames...)
-			for _, container := range config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers {
-				if !containerNames.Has(container.Name) {
-					continue
-				}
-
-				_, tag := parseImage(container.Image)
-				if tag != imageRepo.Tags[params.Tag] {
-					configIDs = append(configIDs, config.ID)
-					firedTriggersForConfig[config.ID] = append(firedTriggersForConfig[config.ID], params)
-				}
+			imageID := imageRepo.Tags[params.Tag]
+			if len(imageID) == 0 || deployutil.ImageChangeTriggerResolved(config, &params, imageID) {
+				continue
+			}
+
+			if params.Automatic {
+				configIDs = append(configIDs, config.ID)
+				firedTriggersForConfig[config.ID] = append(firedTriggersForConfig[config.ID], params)
+			} else {
+				pending = addPendingImageUpdate(pending, deployapi.PendingImageUpdate{
+					RepositoryName: params.RepositoryName,
+					Tag:            params.Tag,
+					ImageID:        imageID,
+				})
 			}
 		}
+
+		if err := c.recordPendingImageUpdates(imageRepoCtx, config, imageRepo.DockerImageRepository, pending); err != nil {
+			glog.V(2).Infof("Error recording pending image updates for deploymentConfig %v: %v", config.ID, err)
+		}
 	}
 
 	for _, configID := range configIDs {
@@ -100,13 +111,37 @@ func (c *ImageChangeController) regenerate(ctx kapi.Context, configID string, tr
 	return nil
 }
 
-func parseImage(name string) (string, string) {
-	index := strings.LastIndex(name, ":")
-	if index == -1 {
-		return "", ""
+// recordPendingImageUpdates replaces the pending image updates of the config for the given repository
+// with the given updates, saving the config only if they differ.
+func (c *ImageChangeController) recordPendingImageUpdates(ctx kapi.Context, config *deployapi.DeploymentConfig, repositoryName string, updates []deployapi.PendingImageUpdate) error {
+	recorded := []deployapi.PendingImageUpdate{}
+	others := []deployapi.PendingImageUpdate{}
+	for _, update := range config.PendingImageUpdates {
+		if update.RepositoryName == repositoryName {
+			recorded = append(recorded, update)
+		} else {
+			others = append(others, update)
+		}
 	}
+	if reflect.DeepEqual(recorded, updates) {
+		return nil
+	}
+
+	glog.V(2).Infof("Recording %d pending image updates from %s for deploymentConfig %s", len(updates), repositoryName, config.ID)
+	newConfig := *config
+	newConfig.PendingImageUpdates = append(others, updates...)
+	_, err := c.DeploymentConfigInterface.UpdateDeploymentConfig(kapi.WithNamespace(ctx, config.Namespace), &newConfig)
+	return err
+}
 
-	return name[:index], name[index+1:]
+// addPendingImageUpdate adds the update unless an update for the same tag is already present.
+func addPendingImageUpdate(updates []deployapi.PendingImageUpdate, update deployapi.PendingImageUpdate) []deployapi.PendingImageUpdate {
+	for _, existing := range updates {
+		if existing.RepositoryName == update.RepositoryName && existing.Tag == update.Tag {
+			return updates
+		}
+	}
+	return append(updates, update)
 }
 
 func generateTriggerDetails(triggers []deployapi.DeploymentTriggerImageChangeParams) *deployapi.DeploymentDetails {
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"reflect"
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -66,7 +67,7 @@ func TestImageChangeForUnregisteredTag(t *testing.T) {
 			},
 		},
 		NextImageRepository: func() *imageapi.ImageRepository {
-			return tagUpdate()
+			return unregisteredTagUpdate()
 		},
 		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
 	}
@@ -75,6 +76,52 @@ func TestImageChangeForUnregisteredTag(t *testing.T) {
 	controller.HandleImageRepo()
 }
 
+func TestImageChangeNotAutomatic(t *testing.T) {
+	config := imageChangeDeploymentConfig()
+	config.Triggers[0].ImageChangeParams.Automatic = false
+
+	var updatedConfig *deployapi.DeploymentConfig
+
+	controller := &ImageChangeController{
+		DeploymentConfigInterface: &testIcDeploymentConfigInterface{
+			UpdateDeploymentConfigFunc: func(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				updatedConfig = config
+				return config, nil
+			},
+			GenerateDeploymentConfigFunc: func(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+				t.Fatalf("unexpected generator call")
+				return nil, nil
+			},
+		},
+		NextImageRepository: func() *imageapi.ImageRepository {
+			return tagUpdate()
+		},
+		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
+	}
+
+	controller.HandleImageRepo()
+
+	if updatedConfig == nil {
+		t.Fatalf("expected the pending image update to be recorded")
+	}
+
+	expected := []deployapi.PendingImageUpdate{
+		{RepositoryName: "registry:8080/openshift/test-image", Tag: "test-tag", ImageID: "ref-2"},
+	}
+	if !reflect.DeepEqual(expected, updatedConfig.PendingImageUpdates) {
+		t.Fatalf("expected pending image updates %#v, got %#v", expected, updatedConfig.PendingImageUpdates)
+	}
+
+	// the same update isn't recorded twice
+	updatedConfig = nil
+	config.PendingImageUpdates = expected
+	controller.HandleImageRepo()
+
+	if updatedConfig != nil {
+		t.Fatalf("unexpected deployment config update: %#v", updatedConfig)
+	}
+}
+
 func TestImageChange(t *testing.T) {
 	var (
 		generatedConfig          *deployapi.DeploymentConfig
@@ -133,6 +180,61 @@ func TestImageChange(t *testing.T) {
 	}
 }
 
+func TestImageChangeForResolvedImageID(t *testing.T) {
+	config := imageChangeDeploymentConfig()
+	config.Triggers[0].ImageChangeParams.LastTriggeredImageID = "ref-1"
+	config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image = "registry:8080/openshift/test-image@sha256:1111"
+
+	controller := &ImageChangeController{
+		DeploymentConfigInterface: &testIcDeploymentConfigInterface{
+			UpdateDeploymentConfigFunc: func(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				t.Fatalf("unexpected deployment config update")
+				return nil, nil
+			},
+			GenerateDeploymentConfigFunc: func(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+				t.Fatalf("unexpected generator call")
+				return nil, nil
+			},
+		},
+		NextImageRepository: func() *imageapi.ImageRepository {
+			return originalImageRepo()
+		},
+		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
+	}
+
+	// verify no-op: the tag still refers to the image the trigger last resolved to
+	controller.HandleImageRepo()
+}
+
+func TestImageChangeForNewImageID(t *testing.T) {
+	config := imageChangeDeploymentConfig()
+	config.Triggers[0].ImageChangeParams.LastTriggeredImageID = "ref-1"
+	config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image = "registry:8080/openshift/test-image@sha256:1111"
+
+	var generatedConfig *deployapi.DeploymentConfig
+	controller := &ImageChangeController{
+		DeploymentConfigInterface: &testIcDeploymentConfigInterface{
+			UpdateDeploymentConfigFunc: func(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.DeploymentConfig, error) {
+				return config, nil
+			},
+			GenerateDeploymentConfigFunc: func(ctx kapi.Context, id string) (*deployapi.DeploymentConfig, error) {
+				generatedConfig = regeneratedConfig(ctx)
+				return generatedConfig, nil
+			},
+		},
+		NextImageRepository: func() *imageapi.ImageRepository {
+			return tagUpdate()
+		},
+		DeploymentConfigStore: deploytest.NewFakeDeploymentConfigStore(config),
+	}
+
+	controller.HandleImageRepo()
+
+	if generatedConfig == nil {
+		t.Fatalf("expected config generation to occur")
+	}
+}
+
 // Utilities and convenience methods
 
 func originalImageRepo() *imageapi.ImageRepository {
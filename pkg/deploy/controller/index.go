@@ -0,0 +1,36 @@
+package controller
+
+import (
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+const (
+	// ImageRepositoryIndex is the name of the index of DeploymentConfigs by the Docker image
+	// repositories their image change triggers reference.
+	ImageRepositoryIndex = "imageRepository"
+	// DeploymentConfigIndex is the name of the index of Deployments by the ID of the DeploymentConfig
+	// they were created from.
+	DeploymentConfigIndex = "deploymentConfig"
+)
+
+// IndexDeploymentConfigByImageRepository returns the Docker image repositories referenced by the image
+// change triggers of a DeploymentConfig.
+func IndexDeploymentConfigByImageRepository(obj interface{}) []string {
+	config := obj.(*deployapi.DeploymentConfig)
+	keys := []string{}
+	for _, trigger := range config.Triggers {
+		if trigger.Type == deployapi.DeploymentTriggerOnImageChange && trigger.ImageChangeParams != nil {
+			keys = append(keys, trigger.ImageChangeParams.RepositoryName)
+		}
+	}
+	return keys
+}
+
+// IndexDeploymentByConfig returns the ID of the DeploymentConfig a Deployment was created from, if any.
+func IndexDeploymentByConfig(obj interface{}) []string {
+	deployment := obj.(*deployapi.Deployment)
+	if configID, ok := deployment.Labels[deployapi.DeploymentConfigLabel]; ok {
+		return []string{configID}
+	}
+	return []string{}
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// RecreateDeploymentController implements the DeploymentStrategyTypeRecreate deployment strategy. Its
+// behavior is to create the replication controller of the new deployment without replicas, scale the
+// replication controllers of previous deployments for the same DeploymentConfig to zero, wait for their
+// pods to terminate, and only then scale up the new replication controller and remove the previous
+// ones. Two versions of a deployment never run side by side. If the deployment fails before the
+// previous replication controllers are removed, they are scaled back up. Running deployments, such as
+// those interrupted by a restart of the controller, are resumed.
+type RecreateDeploymentController struct {
+	DeploymentUpdater           bdcDeploymentUpdater
+	ReplicationControllerClient bdcReplicationControllerClient
+	PodClient                   rdcPodClient
+	NextDeployment              func() *deployapi.Deployment
+	// PollInterval is how often to check whether the pods of previous deployments have terminated.
+	PollInterval time.Duration
+	// Timeout is how long to wait for the pods of previous deployments to terminate when the
+	// deployment doesn't specify a timeout.
+	Timeout time.Duration
+}
+
+type rdcPodClient interface {
+	ListPods(ctx kapi.Context, selector labels.Selector) (*kapi.PodList, error)
+}
+
+func (dc *RecreateDeploymentController) Run() {
+	go util.Forever(func() { dc.HandleDeployment() }, 0)
+}
+
+// HandleDeployment executes a single Deployment. It's assumed that the strategy of the deployment is
+// DeploymentStrategyTypeRecreate.
+func (dc *RecreateDeploymentController) HandleDeployment() error {
+	deployment := dc.NextDeployment()
+
+	if deployment.Strategy.Type != deployapi.DeploymentStrategyTypeRecreate {
+		glog.V(4).Infof("Ignoring deployment %s due to incompatible strategy type %s", deployment.ID, deployment.Strategy.Type)
+		return nil
+	}
+
+	ctx := kapi.WithNamespace(kapi.NewContext(), deployment.Namespace)
+
+	switch deployment.Status {
+	case deployapi.DeploymentStatusNew:
+		// waiting for previous pods may take a while, so report progress before starting
+		deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusRunning, "")
+		saved, err := dc.saveDeployment(ctx, deployment)
+		if err != nil {
+			return err
+		}
+		deployment = saved
+	case deployapi.DeploymentStatusRunning:
+		glog.V(2).Infof("Resuming running deployment %s", deployment.ID)
+	default:
+		return nil
+	}
+
+	nextStatus, message := dc.handleRunning(ctx, deployment)
+	deployutil.SetDeploymentStatus(deployment, nextStatus, message)
+	_, err := dc.saveDeployment(ctx, deployment)
+	return err
+}
+
+// handleRunning creates the replication controller for the deployment without replicas, scales down
+// previous replication controllers, waits for their pods to terminate, and then scales up the
+// replication controller for the deployment and removes the previous ones. The replication controller
+// of a resumed deployment is reused if it was created already. It returns the next status of the
+// deployment along with a message explaining any failure.
+func (dc *RecreateDeploymentController) handleRunning(ctx kapi.Context, deployment *deployapi.Deployment) (deployapi.DeploymentStatus, string) {
+	controllers, err := listPreviousControllers(ctx, dc.ReplicationControllerClient, deployment)
+	if err != nil {
+		return failDeployment(deployment, "Unable to get list of replication controllers for previous deployments: %v", err)
+	}
+
+	var current *kapi.ReplicationController
+	previous := []kapi.ReplicationController{}
+	for i := range controllers.Items {
+		if controllers.Items[i].Labels[deployapi.DeploymentLabel] == deployment.ID {
+			current = &controllers.Items[i]
+		} else {
+			previous = append(previous, controllers.Items[i])
+		}
+	}
+
+	if current == nil {
+		created, err := createController(ctx, dc.ReplicationControllerClient, deployment, 0)
+		if err != nil {
+			return failDeployment(deployment, "Unable to create replication controller: %v", err)
+		}
+		current = created
+	}
+
+	for i, rc := range previous {
+		if err := scaleController(ctx, dc.ReplicationControllerClient, deployment, rc.ID, 0); err != nil {
+			dc.restoreControllers(ctx, deployment, previous[:i])
+			return failDeployment(deployment, "Unable to scale down previous deployment: %v", err)
+		}
+	}
+
+	timeout := dc.Timeout
+	if deployment.Strategy.Recreate != nil && deployment.Strategy.Recreate.TimeoutSeconds > 0 {
+		timeout = time.Duration(deployment.Strategy.Recreate.TimeoutSeconds) * time.Second
+	}
+	for _, rc := range previous {
+		if err := dc.waitForPodsToTerminate(ctx, &rc, timeout); err != nil {
+			dc.restoreControllers(ctx, deployment, previous)
+			return failDeployment(deployment, "Pods of replication controller %s did not terminate: %v", rc.ID, err)
+		}
+	}
+
+	if err := scaleController(ctx, dc.ReplicationControllerClient, deployment, current.ID, deployment.ControllerTemplate.Replicas); err != nil {
+		dc.restoreControllers(ctx, deployment, previous)
+		return failDeployment(deployment, "Unable to scale up deployment: %v", err)
+	}
+
+	failures := []string{}
+	for _, rc := range previous {
+		if err := deleteController(ctx, dc.ReplicationControllerClient, deployment, rc.ID); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return failDeployment(deployment, "Unable to remove previous replication controllers: %s", strings.Join(failures, "; "))
+	}
+
+	return deployapi.DeploymentStatusComplete, ""
+}
+
+// restoreControllers scales the replication controllers of previous deployments back to the replicas
+// they had when the deployment started, so the application keeps running when the deployment fails.
+func (dc *RecreateDeploymentController) restoreControllers(ctx kapi.Context, deployment *deployapi.Deployment, controllers []kapi.ReplicationController) {
+	for _, rc := range controllers {
+		if err := scaleController(ctx, dc.ReplicationControllerClient, deployment, rc.ID, rc.DesiredState.Replicas); err != nil {
+			glog.V(2).Infof("Unable to restore previous replication controller %s of deployment %s: %v", rc.ID, deployment.ID, err)
+		}
+	}
+}
+
+// waitForPodsToTerminate blocks until no pods match the replica selector of the controller, or the
+// timeout is reached.
+func (dc *RecreateDeploymentController) waitForPodsToTerminate(ctx kapi.Context, controller *kapi.ReplicationController, timeout time.Duration) error {
+	if len(controller.DesiredState.ReplicaSelector) == 0 {
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(controller.DesiredState.ReplicaSelector)
+	return wait.Poll(dc.PollInterval, timeout, func() (bool, error) {
+		pods, err := dc.PodClient.ListPods(ctx, selector)
+		if err != nil {
+			return false, err
+		}
+		glog.V(4).Infof("Waiting for %d pods of replication controller %s to terminate", len(pods.Items), controller.ID)
+		return len(pods.Items) == 0, nil
+	})
+}
+
+func (dc *RecreateDeploymentController) saveDeployment(ctx kapi.Context, deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+	glog.V(4).Infof("Saving deployment %v status: %v", deployment.ID, deployment.Status)
+	saved, err := dc.DeploymentUpdater.UpdateDeployment(ctx, deployment)
+	if err != nil {
+		glog.V(2).Infof("Received error while saving deployment %v: %v", deployment.ID, err)
+		return nil, err
+	}
+	return saved, nil
+}
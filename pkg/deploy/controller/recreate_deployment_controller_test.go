@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+type testRdcPodClient struct {
+	ListPodsFunc func(selector labels.Selector) (*kapi.PodList, error)
+}
+
+func (c *testRdcPodClient) ListPods(ctx kapi.Context, selector labels.Selector) (*kapi.PodList, error) {
+	return c.ListPodsFunc(selector)
+}
+
+func recreateDeployment() *deployapi.Deployment {
+	d := configDeployment()
+	d.Strategy = deployapi.DeploymentStrategy{Type: deployapi.DeploymentStrategyTypeRecreate}
+	d.ControllerTemplate.Replicas = 2
+	return d
+}
+
+func TestRecreateHandleNewOrdering(t *testing.T) {
+	actions := []string{}
+	statuses := []deployapi.DeploymentStatus{}
+	remainingPods := 2
+
+	client := &testBdcReplicationControllerClient{
+		ListReplicationControllersFunc: func(selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+			return &kapi.ReplicationControllerList{
+				Items: []kapi.ReplicationController{
+					{
+						TypeMeta:     kapi.TypeMeta{ID: "config-1"},
+						DesiredState: kapi.ReplicationControllerState{ReplicaSelector: map[string]string{"a": "b"}},
+					},
+				},
+			}, nil
+		},
+		GetReplicationControllerFunc: func(id string) (*kapi.ReplicationController, error) {
+			return &kapi.ReplicationController{TypeMeta: kapi.TypeMeta{ID: id}}, nil
+		},
+		CreateReplicationControllerFunc: func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			actions = append(actions, "create")
+			ctrl.ID = "config-2"
+			return ctrl, nil
+		},
+		UpdateReplicationControllerFunc: func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			actions = append(actions, "scale "+ctrl.ID)
+			return ctrl, nil
+		},
+		DeleteReplicationControllerFunc: func(id string) error {
+			actions = append(actions, "delete "+id)
+			return nil
+		},
+	}
+
+	controller := &RecreateDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				statuses = append(statuses, deployment.Status)
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: client,
+		PodClient: &testRdcPodClient{
+			ListPodsFunc: func(selector labels.Selector) (*kapi.PodList, error) {
+				list := &kapi.PodList{}
+				for i := 0; i < remainingPods; i++ {
+					list.Items = append(list.Items, kapi.Pod{})
+				}
+				if remainingPods > 0 {
+					actions = append(actions, "wait")
+					remainingPods--
+				}
+				return list, nil
+			},
+		},
+		NextDeployment: recreateDeployment,
+		PollInterval:   time.Millisecond,
+		Timeout:        time.Second,
+	}
+
+	if err := controller.HandleDeployment(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedActions := []string{"create", "scale config-1", "wait", "wait", "scale config-2", "delete config-1"}
+	if !reflect.DeepEqual(expectedActions, actions) {
+		t.Fatalf("expected actions %v, got %v", expectedActions, actions)
+	}
+
+	expectedStatuses := []deployapi.DeploymentStatus{deployapi.DeploymentStatusRunning, deployapi.DeploymentStatusComplete}
+	if !reflect.DeepEqual(expectedStatuses, statuses) {
+		t.Fatalf("expected statuses %v, got %v", expectedStatuses, statuses)
+	}
+}
+
+// recordingControllerClient returns a client listing the given replication controllers, which records
+// the replicas every controller is scaled to and the controllers created and deleted.
+func recordingControllerClient(actions *[]string, controllers ...kapi.ReplicationController) *testBdcReplicationControllerClient {
+	return &testBdcReplicationControllerClient{
+		ListReplicationControllersFunc: func(selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+			return &kapi.ReplicationControllerList{Items: controllers}, nil
+		},
+		GetReplicationControllerFunc: func(id string) (*kapi.ReplicationController, error) {
+			return &kapi.ReplicationController{TypeMeta: kapi.TypeMeta{ID: id}}, nil
+		},
+		CreateReplicationControllerFunc: func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			*actions = append(*actions, "create")
+			ctrl.ID = "config-2"
+			return ctrl, nil
+		},
+		UpdateReplicationControllerFunc: func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+			*actions = append(*actions, fmt.Sprintf("scale %s to %d", ctrl.ID, ctrl.DesiredState.Replicas))
+			return ctrl, nil
+		},
+		DeleteReplicationControllerFunc: func(id string) error {
+			*actions = append(*actions, "delete "+id)
+			return nil
+		},
+	}
+}
+
+func TestRecreateHandleNewPodsNeverTerminate(t *testing.T) {
+	var updatedDeployment *deployapi.Deployment
+	actions := []string{}
+
+	client := recordingControllerClient(&actions, kapi.ReplicationController{
+		TypeMeta:     kapi.TypeMeta{ID: "config-1"},
+		DesiredState: kapi.ReplicationControllerState{Replicas: 3, ReplicaSelector: map[string]string{"a": "b"}},
+	})
+
+	controller := &RecreateDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				updatedDeployment = deployment
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: client,
+		PodClient: &testRdcPodClient{
+			ListPodsFunc: func(selector labels.Selector) (*kapi.PodList, error) {
+				return &kapi.PodList{Items: []kapi.Pod{{}}}, nil
+			},
+		},
+		NextDeployment: recreateDeployment,
+		PollInterval:   time.Millisecond,
+		Timeout:        10 * time.Millisecond,
+	}
+
+	controller.HandleDeployment()
+
+	if e, a := deployapi.DeploymentStatusFailed, updatedDeployment.Status; e != a {
+		t.Fatalf("expected updated deployment status %s, got %s", e, a)
+	}
+	expectedActions := []string{"create", "scale config-1 to 0", "scale config-1 to 3"}
+	if !reflect.DeepEqual(expectedActions, actions) {
+		t.Fatalf("expected the previous deployment to be restored with actions %v, got %v", expectedActions, actions)
+	}
+}
+
+func TestRecreateHandleNewScaleUpFails(t *testing.T) {
+	var updatedDeployment *deployapi.Deployment
+	actions := []string{}
+
+	client := recordingControllerClient(&actions, kapi.ReplicationController{
+		TypeMeta:     kapi.TypeMeta{ID: "config-1"},
+		DesiredState: kapi.ReplicationControllerState{Replicas: 3},
+	})
+	update := client.UpdateReplicationControllerFunc
+	client.UpdateReplicationControllerFunc = func(ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+		if ctrl.ID == "config-2" {
+			return nil, fmt.Errorf("quota exceeded")
+		}
+		return update(ctrl)
+	}
+
+	controller := &RecreateDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				updatedDeployment = deployment
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: client,
+		PodClient:                   &testRdcPodClient{},
+		NextDeployment:              recreateDeployment,
+		PollInterval:                time.Millisecond,
+		Timeout:                     time.Second,
+	}
+
+	controller.HandleDeployment()
+
+	if e, a := deployapi.DeploymentStatusFailed, updatedDeployment.Status; e != a {
+		t.Fatalf("expected updated deployment status %s, got %s", e, a)
+	}
+	expectedActions := []string{"create", "scale config-1 to 0", "scale config-1 to 3"}
+	if !reflect.DeepEqual(expectedActions, actions) {
+		t.Fatalf("expected the previous deployment to be restored with actions %v, got %v", expectedActions, actions)
+	}
+}
+
+func TestRecreateHandleRunningResumes(t *testing.T) {
+	actions := []string{}
+	statuses := []deployapi.DeploymentStatus{}
+
+	client := recordingControllerClient(&actions,
+		kapi.ReplicationController{TypeMeta: kapi.TypeMeta{ID: "config-1"}},
+		kapi.ReplicationController{
+			TypeMeta: kapi.TypeMeta{ID: "config-2"},
+			Labels:   map[string]string{deployapi.DeploymentLabel: "deploy1"},
+		},
+	)
+
+	controller := &RecreateDeploymentController{
+		DeploymentUpdater: &testDcDeploymentInterface{
+			UpdateDeploymentFunc: func(deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+				statuses = append(statuses, deployment.Status)
+				return deployment, nil
+			},
+		},
+		ReplicationControllerClient: client,
+		PodClient:                   &testRdcPodClient{},
+		NextDeployment: func() *deployapi.Deployment {
+			deployment := recreateDeployment()
+			deployment.Status = deployapi.DeploymentStatusRunning
+			return deployment
+		},
+		PollInterval: time.Millisecond,
+		Timeout:      time.Second,
+	}
+
+	if err := controller.HandleDeployment(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedActions := []string{"scale config-1 to 0", "scale config-2 to 2", "delete config-1"}
+	if !reflect.DeepEqual(expectedActions, actions) {
+		t.Fatalf("expected actions %v, got %v", expectedActions, actions)
+	}
+	expectedStatuses := []deployapi.DeploymentStatus{deployapi.DeploymentStatusComplete}
+	if !reflect.DeepEqual(expectedStatuses, statuses) {
+		t.Fatalf("expected statuses %v, got %v", expectedStatuses, statuses)
+	}
+}
@@ -26,3 +26,6 @@ func (s FakeDeploymentConfigStore) Get(id string) (item interface{}, exists bool
 	return nil, false
 }
 func (s FakeDeploymentConfigStore) Replace(idToObj map[string]interface{}) {}
+func (s FakeDeploymentConfigStore) Index(name, key string) []interface{} {
+	return []interface{}{s.DeploymentConfig}
+}
@@ -30,3 +30,10 @@ func (s FakeDeploymentStore) Get(id string) (item interface{}, exists bool) {
 	return s.Deployment, true
 }
 func (s FakeDeploymentStore) Replace(idToObj map[string]interface{}) {}
+func (s FakeDeploymentStore) Index(name, key string) []interface{} {
+	if s.Deployment == nil || s.Deployment.Labels[deployapi.DeploymentConfigLabel] != key {
+		return []interface{}{}
+	}
+
+	return []interface{}{s.Deployment}
+}
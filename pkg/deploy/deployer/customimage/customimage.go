@@ -1,86 +1,196 @@
 package customimage
 
 import (
+	"fmt"
+	"time"
+
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
 	"github.com/golang/glog"
+	"gopkg.in/v1/yaml"
+
 	osclient "github.com/openshift/origin/pkg/client"
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
-	"gopkg.in/v1/yaml"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
+// CustomImageDeployer performs a deployment from within a deployer pod. It creates the replication
+// controller for the deployment and removes those of previous deployments of the same DeploymentConfig.
+// Progress is recorded as events on the Deployment; if any step fails, the new replication controller
+// is rolled back, previous replication controllers are restored and the failure reason is recorded.
 type CustomImageDeployer struct {
-	KClient  *kclient.Client
+	KClient  kclient.Interface
 	OSClient osclient.Interface
+
+	// Namespace is the namespace of the deployment. The deployer only operates on replication
+	// controllers within this namespace.
+	Namespace string
+	// PollInterval is how often to check whether the pods of previous deployments have terminated.
+	PollInterval time.Duration
+	// Timeout is how long to wait for the pods of previous deployments to terminate. If zero, the
+	// deployer doesn't wait.
+	Timeout time.Duration
 }
 
-func (d *CustomImageDeployer) Deploy(deploymentID string) {
-	ctx := kapi.NewContext()
+// Deploy executes the deployment with the given id, returning an error if the deployment failed.
+func (d *CustomImageDeployer) Deploy(deploymentID string) error {
+	if len(d.Namespace) == 0 {
+		return fmt.Errorf("a namespace is required to deploy %s", deploymentID)
+	}
+	ctx := kapi.WithNamespace(kapi.NewContext(), d.Namespace)
 	glog.V(2).Infof("Retrieving deployment id: %v", deploymentID)
 
-	var deployment *deployapi.Deployment
-	var err error
-	if deployment, err = d.OSClient.GetDeployment(ctx, deploymentID); err != nil {
-		glog.Fatalf("An error occurred retrieving the deployment object: %v", err)
-		return
+	deployment, err := d.OSClient.GetDeployment(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("an error occurred retrieving the deployment object: %v", err)
 	}
 
-	var replicationControllers *kapi.ReplicationControllerList
+	replicationControllers := &kapi.ReplicationControllerList{}
 	configID, hasConfigID := deployment.Labels[deployapi.DeploymentConfigLabel]
 	if hasConfigID {
 		selector, _ := labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + configID)
-		replicationControllers, err = d.KClient.ListReplicationControllers(ctx, selector)
+		list, err := d.KClient.ListReplicationControllers(ctx, selector)
 		if err != nil {
-			glog.Fatalf("Unable to get list of replication controllers: %v\n", err)
-			return
+			return d.fail(ctx, deployment, "Unable to get list of replication controllers: %v", err)
+		}
+		for _, rc := range list.Items {
+			if rc.Namespace != d.Namespace {
+				glog.V(2).Infof("Ignoring replication controller %s in namespace %s", rc.ID, rc.Namespace)
+				continue
+			}
+			replicationControllers.Items = append(replicationControllers.Items, rc)
 		}
 	}
 
-	controller := &kapi.ReplicationController{
-		DesiredState: deployment.ControllerTemplate,
-		Labels:       map[string]string{deployapi.DeploymentConfigLabel: configID, "deploymentID": deploymentID},
-	}
-	if controller.DesiredState.PodTemplate.Labels == nil {
-		controller.DesiredState.PodTemplate.Labels = make(map[string]string)
-	}
-	if hasConfigID {
-		controller.DesiredState.PodTemplate.Labels[deployapi.DeploymentConfigLabel] = configID
-	}
-	controller.DesiredState.PodTemplate.Labels["deploymentID"] = deploymentID
+	controller := deployutil.ReplicationControllerForDeployment(deployment)
 
 	glog.V(2).Info("Creating replication controller")
 	obj, _ := yaml.Marshal(controller)
 	glog.V(4).Info(string(obj))
 
-	if _, err := d.KClient.CreateReplicationController(ctx, controller); err != nil {
-		glog.Fatalf("An error occurred creating the replication controller: %v", err)
-		return
+	created, err := d.KClient.CreateReplicationController(ctx, controller)
+	if err != nil {
+		return d.fail(ctx, deployment, "An error occurred creating the replication controller: %v", err)
 	}
-
 	glog.Info("Created replication controller")
+	d.recordEvent(ctx, deployment, deployapi.DeploymentEventReplicationControllerCreated,
+		"Created replication controller %s with %d replicas", created.ID, created.DesiredState.Replicas)
 
 	// For this simple deploy, remove previous replication controllers
+	stopped := []kapi.ReplicationController{}
 	for _, rc := range replicationControllers.Items {
 		glog.Infof("Stopping replication controller: %v", rc.ID)
-		obj, _ := yaml.Marshal(rc)
-		glog.Info(string(obj))
-		rcObj, err1 := d.KClient.GetReplicationController(ctx, rc.ID)
-		if err1 != nil {
-			glog.Fatalf("Unable to get replication controller %s - error: %#v\n", rc.ID, err1)
+		rcObj, err := d.KClient.GetReplicationController(ctx, rc.ID)
+		if err != nil {
+			d.rollback(ctx, deployment, created, stopped)
+			return d.fail(ctx, deployment, "Unable to get replication controller %s: %v", rc.ID, err)
 		}
+		replicas := rcObj.DesiredState.Replicas
 		rcObj.DesiredState.Replicas = 0
-		_, err := d.KClient.UpdateReplicationController(ctx, rcObj)
-		if err != nil {
-			glog.Fatalf("Unable to stop replication controller %s - error: %#v\n", rc.ID, err)
+		if _, err := d.KClient.UpdateReplicationController(ctx, rcObj); err != nil {
+			d.rollback(ctx, deployment, created, stopped)
+			return d.fail(ctx, deployment, "Unable to stop replication controller %s: %v", rc.ID, err)
+		}
+		rcObj.DesiredState.Replicas = replicas
+		stopped = append(stopped, *rcObj)
+		d.recordEvent(ctx, deployment, deployapi.DeploymentEventReplicationControllerScaled,
+			"Scaled replication controller %s to 0 replicas", rc.ID)
+	}
+
+	for _, rc := range stopped {
+		if err := d.waitForPodsToTerminate(ctx, &rc); err != nil {
+			d.rollback(ctx, deployment, created, stopped)
+			return d.fail(ctx, deployment, "Pods of replication controller %s did not terminate: %v", rc.ID, err)
 		}
 	}
 
 	for _, rc := range replicationControllers.Items {
 		glog.Infof("Deleting replication controller %s", rc.ID)
-		err := d.KClient.DeleteReplicationController(ctx, rc.ID)
+		if err := d.KClient.DeleteReplicationController(ctx, rc.ID); err != nil {
+			// the new deployment is already serving, so a leftover controller with no replicas is
+			// reported rather than rolled back
+			return d.fail(ctx, deployment, "Unable to remove replication controller %s: %v", rc.ID, err)
+		}
+		d.recordEvent(ctx, deployment, deployapi.DeploymentEventReplicationControllerDeleted,
+			"Deleted replication controller %s", rc.ID)
+	}
+
+	return nil
+}
+
+// waitForPodsToTerminate blocks until no pods match the replica selector of the controller, or the
+// timeout is reached.
+func (d *CustomImageDeployer) waitForPodsToTerminate(ctx kapi.Context, controller *kapi.ReplicationController) error {
+	if d.Timeout == 0 || len(controller.DesiredState.ReplicaSelector) == 0 {
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(controller.DesiredState.ReplicaSelector)
+	return wait.Poll(d.PollInterval, d.Timeout, func() (bool, error) {
+		pods, err := d.KClient.ListPods(ctx, selector)
 		if err != nil {
-			glog.Fatalf("Unable to remove replication controller %s - error: %#v\n", rc.ID, err)
+			return false, err
 		}
+		return len(pods.Items) == 0, nil
+	})
+}
+
+// rollback removes the replication controller created for the deployment and restores the replica
+// counts of previous replication controllers which were already stopped. Errors are logged, since
+// the original failure is what gets reported.
+func (d *CustomImageDeployer) rollback(ctx kapi.Context, deployment *deployapi.Deployment, created *kapi.ReplicationController, stopped []kapi.ReplicationController) {
+	for i := range stopped {
+		rc := &stopped[i]
+		glog.Infof("Restoring replication controller %s to %d replicas", rc.ID, rc.DesiredState.Replicas)
+		if _, err := d.KClient.UpdateReplicationController(ctx, rc); err != nil {
+			glog.Errorf("Unable to restore replication controller %s: %v", rc.ID, err)
+			continue
+		}
+		d.recordEvent(ctx, deployment, deployapi.DeploymentEventReplicationControllerScaled,
+			"Restored replication controller %s to %d replicas", rc.ID, rc.DesiredState.Replicas)
+	}
+
+	glog.Infof("Rolling back replication controller %s", created.ID)
+	if err := d.KClient.DeleteReplicationController(ctx, created.ID); err != nil {
+		glog.Errorf("Unable to roll back replication controller %s: %v", created.ID, err)
+		return
+	}
+	d.recordEvent(ctx, deployment, deployapi.DeploymentEventReplicationControllerDeleted,
+		"Rolled back replication controller %s", created.ID)
+}
+
+// fail records the failure reason on the deployment and returns it as an error.
+func (d *CustomImageDeployer) fail(ctx kapi.Context, deployment *deployapi.Deployment, format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	glog.Errorf("Deployment %s failed: %s", deployment.ID, message)
+	d.updateDeployment(ctx, deployment, func(latest *deployapi.Deployment) {
+		deployutil.RecordDeploymentEvent(latest, deployapi.DeploymentEventFailed, "%s", message)
+		latest.StatusMessage = message
+	})
+	return fmt.Errorf("%s", message)
+}
+
+// recordEvent records a step of the deployment on the Deployment.
+func (d *CustomImageDeployer) recordEvent(ctx kapi.Context, deployment *deployapi.Deployment, eventType deployapi.DeploymentEventType, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	d.updateDeployment(ctx, deployment, func(latest *deployapi.Deployment) {
+		deployutil.RecordDeploymentEvent(latest, eventType, "%s", message)
+	})
+}
+
+// updateDeployment applies the change to the latest version of the deployment and saves it. The
+// deployment controller updates the status of the same object, so the latest version is retrieved
+// before each update. Failing to record progress doesn't fail the deployment.
+func (d *CustomImageDeployer) updateDeployment(ctx kapi.Context, deployment *deployapi.Deployment, change func(*deployapi.Deployment)) {
+	latest, err := d.OSClient.GetDeployment(ctx, deployment.ID)
+	if err != nil {
+		glog.Errorf("Unable to retrieve deployment %s: %v", deployment.ID, err)
+		return
+	}
+	change(latest)
+	if _, err := d.OSClient.UpdateDeployment(ctx, latest); err != nil {
+		glog.Errorf("Unable to update deployment %s: %v", deployment.ID, err)
 	}
 }
@@ -0,0 +1,125 @@
+package customimage
+
+import (
+	"fmt"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// testOSClient serves a single deployment and records updates to it.
+type testOSClient struct {
+	osclient.Fake
+	Deployment *deployapi.Deployment
+}
+
+func (c *testOSClient) GetDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error) {
+	return c.Deployment, nil
+}
+
+func (c *testOSClient) UpdateDeployment(ctx kapi.Context, deployment *deployapi.Deployment) (*deployapi.Deployment, error) {
+	c.Deployment = deployment
+	return deployment, nil
+}
+
+// testKClient lists a previous replication controller in the deployment namespace and one in another
+// namespace, and fails updates when asked to.
+type testKClient struct {
+	kclient.Fake
+	UpdateErr error
+}
+
+func (c *testKClient) ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+	return &kapi.ReplicationControllerList{
+		Items: []kapi.ReplicationController{
+			{TypeMeta: kapi.TypeMeta{ID: "config-1", Namespace: "default"}},
+			{TypeMeta: kapi.TypeMeta{ID: "config-1", Namespace: "other"}},
+		},
+	}, nil
+}
+
+func (c *testKClient) CreateReplicationController(ctx kapi.Context, controller *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	c.Fake.CreateReplicationController(ctx, controller)
+	controller.ID = "config-2"
+	return controller, nil
+}
+
+func (c *testKClient) UpdateReplicationController(ctx kapi.Context, controller *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	c.Fake.UpdateReplicationController(ctx, controller)
+	return controller, c.UpdateErr
+}
+
+func deployment() *deployapi.Deployment {
+	return &deployapi.Deployment{
+		TypeMeta: kapi.TypeMeta{ID: "config-2", Namespace: "default"},
+		Labels:   map[string]string{deployapi.DeploymentConfigLabel: "config"},
+		Status:   deployapi.DeploymentStatusRunning,
+	}
+}
+
+func TestDeployOk(t *testing.T) {
+	kClient := &testKClient{}
+	osClient := &testOSClient{Deployment: deployment()}
+	deployer := &CustomImageDeployer{KClient: kClient, OSClient: osClient, Namespace: "default"}
+
+	if err := deployer.Deploy("config-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"create-controller", "get-controller", "update-controller", "delete-controller"}
+	if e, a := len(expected), len(kClient.Actions); e != a {
+		t.Fatalf("expected actions %v, got %#v", expected, kClient.Actions)
+	}
+	for i, action := range expected {
+		if a := kClient.Actions[i].Action; action != a {
+			t.Errorf("expected action %d to be %s, got %s", i, action, a)
+		}
+	}
+
+	if e, a := 3, len(osClient.Deployment.Events); e != a {
+		t.Fatalf("expected %d deployment events, got %#v", e, osClient.Deployment.Events)
+	}
+}
+
+func TestDeployRollsBackOnFailure(t *testing.T) {
+	kClient := &testKClient{UpdateErr: fmt.Errorf("update failed")}
+	osClient := &testOSClient{Deployment: deployment()}
+	deployer := &CustomImageDeployer{KClient: kClient, OSClient: osClient, Namespace: "default"}
+
+	if err := deployer.Deploy("config-2"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	last := kClient.Actions[len(kClient.Actions)-1]
+	if last.Action != "delete-controller" || last.Value != "config-2" {
+		t.Fatalf("expected the new replication controller to be rolled back, got %#v", kClient.Actions)
+	}
+
+	if len(osClient.Deployment.StatusMessage) == 0 {
+		t.Fatalf("expected the failure reason to be recorded on the deployment")
+	}
+
+	events := osClient.Deployment.Events
+	if e, a := deployapi.DeploymentEventFailed, events[len(events)-1].Type; e != a {
+		t.Fatalf("expected last event %s, got %s", e, a)
+	}
+}
+
+func TestDeployRequiresNamespace(t *testing.T) {
+	kClient := &testKClient{}
+	osClient := &testOSClient{Deployment: deployment()}
+	deployer := &CustomImageDeployer{KClient: kClient, OSClient: osClient}
+
+	if err := deployer.Deploy("config-2"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if len(kClient.Actions) != 0 {
+		t.Fatalf("expected no actions, got %#v", kClient.Actions)
+	}
+}
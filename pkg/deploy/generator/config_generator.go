@@ -22,6 +22,7 @@ type DeploymentConfigGenerator struct {
 	DeploymentInterface       deploymentInterface
 	DeploymentConfigInterface deploymentConfigInterface
 	ImageRepositoryInterface  imageRepositoryInterface
+	ImageInterface            imageInterface
 }
 
 type deploymentInterface interface {
@@ -36,9 +37,26 @@ type imageRepositoryInterface interface {
 	ListImageRepositories(ctx kapi.Context, labels labels.Selector) (*imageapi.ImageRepositoryList, error)
 }
 
+type imageInterface interface {
+	GetImage(ctx kapi.Context, id string) (*imageapi.Image, error)
+}
+
 // Generate returns a potential future DeploymentConfig based on the DeploymentConfig specified
-// by deploymentConfigID.
+// by deploymentConfigID. New images for image change triggers which aren't automatic await approval
+// and are left out.
 func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, deploymentConfigID string) (*deployapi.DeploymentConfig, error) {
+	return g.generate(ctx, deploymentConfigID, nil)
+}
+
+// GenerateApproved returns a potential future DeploymentConfig like Generate, additionally rolling out
+// the approved image updates of triggers which aren't automatic. The approved image of each update is
+// deployed, and only approved updates are removed from the pending image updates of the config. A
+// conflict is returned if an approved update is no longer pending with the same image.
+func (g *DeploymentConfigGenerator) GenerateApproved(ctx kapi.Context, deploymentConfigID string, approved []deployapi.PendingImageUpdate) (*deployapi.DeploymentConfig, error) {
+	return g.generate(ctx, deploymentConfigID, approved)
+}
+
+func (g *DeploymentConfigGenerator) generate(ctx kapi.Context, deploymentConfigID string, approved []deployapi.PendingImageUpdate) (*deployapi.DeploymentConfig, error) {
 	glog.V(4).Infof("Generating new deployment config from deploymentConfig %v", deploymentConfigID)
 
 	deploymentConfig, err := g.DeploymentConfigInterface.GetDeploymentConfig(ctx, deploymentConfigID)
@@ -47,6 +65,13 @@ func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, deploymentConfigI
 		return nil, err
 	}
 
+	for _, update := range approved {
+		pending, ok := findImageUpdate(deploymentConfig.PendingImageUpdates, update.RepositoryName, update.Tag)
+		if !ok || pending.ImageID != update.ImageID {
+			return nil, errors.NewConflict("deploymentConfig", deploymentConfig.ID, fmt.Errorf("image %s is no longer pending for %s:%s", update.ImageID, update.RepositoryName, update.Tag))
+		}
+	}
+
 	deploymentID := deployutil.LatestDeploymentIDForConfig(deploymentConfig)
 
 	deployment, err := g.DeploymentInterface.GetDeployment(ctx, deploymentID)
@@ -60,22 +85,40 @@ func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, deploymentConfigI
 	referencedRepoNames := referencedRepoNames(deploymentConfig)
 	referencedRepos := imageReposByDockerImageRepo(ctx, g.ImageRepositoryInterface, referencedRepoNames)
 
-	for _, repoName := range referencedRepoNames.List() {
-		params := deployutil.ParamsForImageChangeTrigger(deploymentConfig, repoName)
+	for _, trigger := range deploymentConfig.Triggers {
+		if trigger.Type != deployapi.DeploymentTriggerOnImageChange {
+			continue
+		}
+		params := trigger.ImageChangeParams
 		repo, ok := referencedRepos[params.RepositoryName]
 		if !ok {
 			return nil, fmt.Errorf("Config references unknown ImageRepository '%s'", params.RepositoryName)
 		}
 
 		// TODO: If the tag is missing, what's the correct reaction?
-		tag, tagExists := repo.Tags[params.Tag]
+		imageID, tagExists := repo.Tags[params.Tag]
+		if !params.Automatic {
+			update, ok := findImageUpdate(approved, params.RepositoryName, params.Tag)
+			if !ok {
+				continue
+			}
+			imageID, tagExists = update.ImageID, true
+		}
 		if !tagExists {
-			glog.V(4).Infof("No tag %s found for repository %s (potentially invalid DeploymentConfig status)", tag, repoName)
+			glog.V(4).Infof("No tag %s found for repository %s (potentially invalid DeploymentConfig status)", params.Tag, params.RepositoryName)
+			continue
+		}
+
+		// containers deployed before triggers recorded image IDs name the image in their pull spec;
+		// keep them as they are rather than redeploying the same image under another reference
+		if len(params.LastTriggeredImageID) == 0 && deployutil.ImageChangeTriggerResolved(deploymentConfig, params, imageID) {
+			params.LastTriggeredImageID = imageID
 			continue
 		}
 
-		newImage := repo.DockerImageRepository + ":" + tag
+		newImage := g.imageReference(ctx, &repo, imageID)
 		updateContainers(&configPodTemplate, util.NewStringSet(params.ContainerNames...), newImage)
+		params.LastTriggeredImageID = imageID
 	}
 
 	if deployment == nil {
@@ -85,15 +128,118 @@ func (g *DeploymentConfigGenerator) Generate(ctx kapi.Context, deploymentConfigI
 			// reset the details of the deployment trigger for this deploymentConfig
 			deploymentConfig.Details = nil
 		}
-	} else if !deployutil.PodTemplatesEqual(configPodTemplate, deployment.ControllerTemplate.PodTemplate) {
+	} else if !deployutil.PodTemplatesEqual(configPodTemplate, deployment.ControllerTemplate.PodTemplate) ||
+		deployedImagesChanged(deployment, deployutil.DeploymentImagesForConfig(deploymentConfig)) {
 		deploymentConfig.LatestVersion += 1
 		// reset the details of the deployment trigger for this deploymentConfig
 		deploymentConfig.Details = nil
 	}
 
+	deploymentConfig.PendingImageUpdates = withoutImageUpdates(deploymentConfig.PendingImageUpdates, approved)
+
 	return deploymentConfig, nil
 }
 
+// Diff returns the changes the next deployment of the DeploymentConfig specified by deploymentConfigID
+// would make to the pod template of its most recent deployment.
+func (g *DeploymentConfigGenerator) Diff(ctx kapi.Context, deploymentConfigID string) (*deployapi.DeploymentConfigDiff, error) {
+	deploymentConfig, err := g.DeploymentConfigInterface.GetDeploymentConfig(ctx, deploymentConfigID)
+	if err != nil {
+		glog.V(4).Infof("Error getting deploymentConfig for id %v", deploymentConfigID)
+		return nil, err
+	}
+
+	deployment, err := g.latestDeployment(ctx, deploymentConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := g.Generate(ctx, deploymentConfigID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &deployapi.DeploymentConfigDiff{
+		TypeMeta:      kapi.TypeMeta{ID: deploymentConfig.ID, Namespace: deploymentConfig.Namespace},
+		LatestVersion: next.LatestVersion,
+	}
+	deployed := kapi.PodTemplate{}
+	if deployment != nil {
+		diff.DeploymentID = deployment.ID
+		deployed = deployment.ControllerTemplate.PodTemplate
+	}
+	diff.Changes = deployutil.DiffPodTemplates(deployed, next.Template.ControllerTemplate.PodTemplate)
+
+	return diff, nil
+}
+
+// latestDeployment returns the most recent deployment of the config, which may precede the config's
+// LatestVersion if that version hasn't been deployed yet. It returns nil if no deployment exists.
+func (g *DeploymentConfigGenerator) latestDeployment(ctx kapi.Context, config *deployapi.DeploymentConfig) (*deployapi.Deployment, error) {
+	candidate := *config
+	for ; candidate.LatestVersion > 0; candidate.LatestVersion-- {
+		deployment, err := g.DeploymentInterface.GetDeployment(ctx, deployutil.LatestDeploymentIDForConfig(&candidate))
+		if err == nil {
+			return deployment, nil
+		}
+		if !errors.IsNotFound(err) {
+			glog.V(2).Infof("Error getting deployment: %#v", err)
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// imageReference returns the exact pull spec of the image with the given ID, falling back to the
+// repository with the ID as its tag if the image isn't registered or doesn't record one.
+func (g *DeploymentConfigGenerator) imageReference(ctx kapi.Context, repo *imageapi.ImageRepository, imageID string) string {
+	image, err := g.ImageInterface.GetImage(ctx, imageID)
+	if err != nil {
+		glog.V(4).Infof("Error getting image %s: %v", imageID, err)
+	} else if len(image.DockerImageReference) > 0 {
+		return image.DockerImageReference
+	}
+	return repo.DockerImageRepository + ":" + imageID
+}
+
+// deployedImagesChanged returns true if any container recorded on the deployment now resolves to a
+// different image, even though its pull spec may be unchanged.
+func deployedImagesChanged(deployment *deployapi.Deployment, images []deployapi.DeploymentImage) bool {
+	for _, image := range images {
+		for _, deployed := range deployment.Images {
+			if deployed.ContainerName == image.ContainerName && deployed.ImageID != image.ImageID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findImageUpdate returns the update for the given repository and tag, if any.
+func findImageUpdate(updates []deployapi.PendingImageUpdate, repositoryName, tag string) (deployapi.PendingImageUpdate, bool) {
+	for _, update := range updates {
+		if update.RepositoryName == repositoryName && update.Tag == tag {
+			return update, true
+		}
+	}
+	return deployapi.PendingImageUpdate{}, false
+}
+
+// withoutImageUpdates returns the pending updates which aren't for the repository and tag of one
+// of the removed updates.
+func withoutImageUpdates(pending, removed []deployapi.PendingImageUpdate) []deployapi.PendingImageUpdate {
+	remaining := []deployapi.PendingImageUpdate{}
+	for _, update := range pending {
+		if _, ok := findImageUpdate(removed, update.RepositoryName, update.Tag); !ok {
+			remaining = append(remaining, update)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	return remaining
+}
+
 func updateContainers(template *kapi.PodTemplate, containers util.StringSet, newImage string) {
 	for i, container := range template.DesiredState.Manifest.Containers {
 		if !containers.Has(container.Name) {
@@ -115,6 +115,11 @@ func TestGenerateFromConfigWithUpdatedImageRef(t *testing.T) {
 				return updatedImageRepo(), nil
 			},
 		},
+		ImageInterface: &testImageInterface{
+			GetImageFunc: func(id string) (*imageapi.Image, error) {
+				return nil, kerrors.NewNotFound("image", id)
+			},
+		},
 		DeploymentInterface: &testDeploymentInterface{
 			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
 				return &deployapi.Deployment{
@@ -147,6 +152,335 @@ func TestGenerateFromConfigWithUpdatedImageRef(t *testing.T) {
 	}
 }
 
+func TestGenerateFromConfigWithUnapprovedImageUpdate(t *testing.T) {
+	generator := &DeploymentConfigGenerator{
+		DeploymentConfigInterface: &testDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				config := basicDeploymentConfig()
+				config.Triggers[0].ImageChangeParams.Automatic = false
+				config.PendingImageUpdates = []deployapi.PendingImageUpdate{
+					{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref2"},
+				}
+				return config, nil
+			},
+		},
+		ImageRepositoryInterface: &testImageRepositoryInterface{
+			ListImageRepositoriesFunc: func(labels labels.Selector) (*imageapi.ImageRepositoryList, error) {
+				return updatedImageRepo(), nil
+			},
+		},
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return &deployapi.Deployment{
+					ControllerTemplate: kapi.ReplicationControllerState{
+						PodTemplate: basicPodTemplate(),
+					},
+				}, nil
+			},
+		},
+	}
+
+	config, err := generator.Generate(kapi.NewDefaultContext(), "deploy1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.LatestVersion != 1 {
+		t.Fatalf("Expected config LatestVersion=1, got %d", config.LatestVersion)
+	}
+
+	expected := "registry:8080/repo1:ref1"
+	actual := config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image
+	if expected != actual {
+		t.Fatalf("Expected container image %s, got %s", expected, actual)
+	}
+
+	if e, a := 1, len(config.PendingImageUpdates); e != a {
+		t.Fatalf("Expected %d pending image updates, got %d", e, a)
+	}
+}
+
+func TestGenerateApprovedFromConfigWithPendingImageUpdate(t *testing.T) {
+	generator := &DeploymentConfigGenerator{
+		DeploymentConfigInterface: &testDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				config := basicDeploymentConfig()
+				config.Triggers[0].ImageChangeParams.Automatic = false
+				config.PendingImageUpdates = []deployapi.PendingImageUpdate{
+					{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref2"},
+				}
+				return config, nil
+			},
+		},
+		ImageRepositoryInterface: &testImageRepositoryInterface{
+			ListImageRepositoriesFunc: func(labels labels.Selector) (*imageapi.ImageRepositoryList, error) {
+				// the tag has moved on since the update was approved
+				repos := updatedImageRepo()
+				repos.Items[0].Tags["tag1"] = "ref3"
+				return repos, nil
+			},
+		},
+		ImageInterface: &testImageInterface{
+			GetImageFunc: func(id string) (*imageapi.Image, error) {
+				return nil, kerrors.NewNotFound("image", id)
+			},
+		},
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return &deployapi.Deployment{
+					ControllerTemplate: kapi.ReplicationControllerState{
+						PodTemplate: basicPodTemplate(),
+					},
+				}, nil
+			},
+		},
+	}
+
+	approved := []deployapi.PendingImageUpdate{
+		{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref2"},
+	}
+	config, err := generator.GenerateApproved(kapi.NewDefaultContext(), "deploy1", approved)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.LatestVersion != 2 {
+		t.Fatalf("Expected config LatestVersion=2, got %d", config.LatestVersion)
+	}
+
+	expected := "registry:8080/repo1:ref2"
+	actual := config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image
+	if expected != actual {
+		t.Fatalf("Expected container image %s, got %s", expected, actual)
+	}
+
+	if len(config.PendingImageUpdates) != 0 {
+		t.Fatalf("Expected no pending image updates, got %#v", config.PendingImageUpdates)
+	}
+}
+
+func TestGenerateApprovedFromConfigWithReplacedImageUpdate(t *testing.T) {
+	generator := &DeploymentConfigGenerator{
+		DeploymentConfigInterface: &testDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				config := basicDeploymentConfig()
+				config.Triggers[0].ImageChangeParams.Automatic = false
+				config.PendingImageUpdates = []deployapi.PendingImageUpdate{
+					{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref3"},
+				}
+				return config, nil
+			},
+		},
+	}
+
+	approved := []deployapi.PendingImageUpdate{
+		{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref2"},
+	}
+	_, err := generator.GenerateApproved(kapi.NewDefaultContext(), "deploy1", approved)
+	if !kerrors.IsConflict(err) {
+		t.Fatalf("Expected a conflict error, got %#v", err)
+	}
+}
+
+func TestGenerateFromConfigWithImageReference(t *testing.T) {
+	generator := &DeploymentConfigGenerator{
+		DeploymentConfigInterface: &testDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				return basicDeploymentConfig(), nil
+			},
+		},
+		ImageRepositoryInterface: &testImageRepositoryInterface{
+			ListImageRepositoriesFunc: func(labels labels.Selector) (*imageapi.ImageRepositoryList, error) {
+				return updatedImageRepo(), nil
+			},
+		},
+		ImageInterface: &testImageInterface{
+			GetImageFunc: func(id string) (*imageapi.Image, error) {
+				return &imageapi.Image{
+					TypeMeta:             kapi.TypeMeta{ID: id},
+					DockerImageReference: "registry:8080/repo1@sha256:2222",
+				}, nil
+			},
+		},
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return &deployapi.Deployment{
+					ControllerTemplate: kapi.ReplicationControllerState{
+						PodTemplate: basicPodTemplate(),
+					},
+				}, nil
+			},
+		},
+	}
+
+	config, err := generator.Generate(kapi.NewDefaultContext(), "deploy1")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.LatestVersion != 2 {
+		t.Fatalf("Expected config LatestVersion=2, got %d", config.LatestVersion)
+	}
+
+	expected := "registry:8080/repo1@sha256:2222"
+	actual := config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image
+	if expected != actual {
+		t.Fatalf("Expected container image %s, got %s", expected, actual)
+	}
+
+	if e, a := "ref2", config.Triggers[0].ImageChangeParams.LastTriggeredImageID; e != a {
+		t.Fatalf("Expected the trigger to resolve to image %s, got %s", e, a)
+	}
+}
+
+func TestGenerateFromConfigWithRetaggedImage(t *testing.T) {
+	taggedConfig := func() *deployapi.DeploymentConfig {
+		config := basicDeploymentConfig()
+		config.Triggers[0].ImageChangeParams.LastTriggeredImageID = "ref1"
+		config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image = "registry:8080/repo1:tag1"
+		return config
+	}
+
+	for _, test := range []struct {
+		repos         *imageapi.ImageRepositoryList
+		latestVersion int
+	}{
+		{basicImageRepo(), 1},
+		{updatedImageRepo(), 2},
+	} {
+		generator := &DeploymentConfigGenerator{
+			DeploymentConfigInterface: &testDeploymentConfigInterface{
+				GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+					return taggedConfig(), nil
+				},
+			},
+			ImageRepositoryInterface: &testImageRepositoryInterface{
+				ListImageRepositoriesFunc: func(labels labels.Selector) (*imageapi.ImageRepositoryList, error) {
+					return test.repos, nil
+				},
+			},
+			ImageInterface: &testImageInterface{
+				GetImageFunc: func(id string) (*imageapi.Image, error) {
+					return &imageapi.Image{
+						TypeMeta:             kapi.TypeMeta{ID: id},
+						DockerImageReference: "registry:8080/repo1:tag1",
+					}, nil
+				},
+			},
+			DeploymentInterface: &testDeploymentInterface{
+				GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+					return &deployapi.Deployment{
+						ControllerTemplate: taggedConfig().Template.ControllerTemplate,
+						Images: []deployapi.DeploymentImage{
+							{ContainerName: "container1", ImageID: "ref1", DockerImageReference: "registry:8080/repo1:tag1"},
+						},
+					}, nil
+				},
+			},
+		}
+
+		config, err := generator.Generate(kapi.NewDefaultContext(), "deploy1")
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if config.LatestVersion != test.latestVersion {
+			t.Errorf("Expected config LatestVersion=%d, got %d", test.latestVersion, config.LatestVersion)
+		}
+	}
+}
+
+func TestDiffFromConfigWithUpdatedImageRef(t *testing.T) {
+	generator := &DeploymentConfigGenerator{
+		DeploymentConfigInterface: &testDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				return basicDeploymentConfig(), nil
+			},
+		},
+		ImageRepositoryInterface: &testImageRepositoryInterface{
+			ListImageRepositoriesFunc: func(labels labels.Selector) (*imageapi.ImageRepositoryList, error) {
+				return updatedImageRepo(), nil
+			},
+		},
+		ImageInterface: &testImageInterface{
+			GetImageFunc: func(id string) (*imageapi.Image, error) {
+				return nil, kerrors.NewNotFound("image", id)
+			},
+		},
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return &deployapi.Deployment{
+					TypeMeta: kapi.TypeMeta{ID: id},
+					ControllerTemplate: kapi.ReplicationControllerState{
+						PodTemplate: basicPodTemplate(),
+					},
+				}, nil
+			},
+		},
+	}
+
+	diff, err := generator.Diff(kapi.NewDefaultContext(), "deploy1")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if e, a := "deploy1-1", diff.DeploymentID; e != a {
+		t.Fatalf("Expected diff against deployment %s, got %s", e, a)
+	}
+
+	if diff.LatestVersion != 2 {
+		t.Fatalf("Expected diff LatestVersion=2, got %d", diff.LatestVersion)
+	}
+
+	if len(diff.Changes) != 1 {
+		t.Fatalf("Expected a single change, got %#v", diff.Changes)
+	}
+
+	change := diff.Changes[0]
+	if change.Type != deployapi.DeploymentChangeImage || change.From != "registry:8080/repo1:ref1" || change.To != "registry:8080/repo1:ref2" {
+		t.Fatalf("Unexpected change: %#v", change)
+	}
+}
+
+func TestDiffFromConfigWithNoDeployment(t *testing.T) {
+	generator := &DeploymentConfigGenerator{
+		DeploymentConfigInterface: &testDeploymentConfigInterface{
+			GetDeploymentConfigFunc: func(id string) (*deployapi.DeploymentConfig, error) {
+				return basicDeploymentConfig(), nil
+			},
+		},
+		ImageRepositoryInterface: &testImageRepositoryInterface{
+			ListImageRepositoriesFunc: func(labels labels.Selector) (*imageapi.ImageRepositoryList, error) {
+				return basicImageRepo(), nil
+			},
+		},
+		DeploymentInterface: &testDeploymentInterface{
+			GetDeploymentFunc: func(id string) (*deployapi.Deployment, error) {
+				return nil, kerrors.NewNotFound("deployment", id)
+			},
+		},
+	}
+
+	diff, err := generator.Diff(kapi.NewDefaultContext(), "deploy1")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(diff.DeploymentID) != 0 {
+		t.Fatalf("Expected no deployment, got %s", diff.DeploymentID)
+	}
+
+	for _, change := range diff.Changes {
+		if change.Type != deployapi.DeploymentChangeContainerAdded && change.Type != deployapi.DeploymentChangeEnv && change.Type != deployapi.DeploymentChangePort {
+			t.Fatalf("Expected only additions, got %#v", change)
+		}
+	}
+}
+
 type testDeploymentInterface struct {
 	GetDeploymentFunc func(id string) (*deployapi.Deployment, error)
 }
@@ -171,6 +505,14 @@ func (i *testImageRepositoryInterface) ListImageRepositories(ctx kapi.Context, l
 	return i.ListImageRepositoriesFunc(labels)
 }
 
+type testImageInterface struct {
+	GetImageFunc func(id string) (*imageapi.Image, error)
+}
+
+func (i *testImageInterface) GetImage(ctx kapi.Context, id string) (*imageapi.Image, error) {
+	return i.GetImageFunc(id)
+}
+
 func basicPodTemplate() kapi.PodTemplate {
 	return kapi.PodTemplate{
 		DesiredState: kapi.PodState{
@@ -197,6 +539,7 @@ func basicDeploymentConfig() *deployapi.DeploymentConfig {
 			{
 				Type: deployapi.DeploymentTriggerOnImageChange,
 				ImageChangeParams: &deployapi.DeploymentTriggerImageChangeParams{
+					Automatic: true,
 					ContainerNames: []string{
 						"container1",
 					},
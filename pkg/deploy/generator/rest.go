@@ -45,3 +45,37 @@ func (s *REST) Update(ctx api.Context, obj runtime.Object) (<-chan runtime.Objec
 func (s *REST) Create(ctx api.Context, obj runtime.Object) (<-chan runtime.Object, error) {
 	return nil, errors.New("deploy/generator.REST.Create() is not implemented.")
 }
+
+// DiffREST is a RESTStorage implementation which previews the changes the next deployment of a
+// DeploymentConfig would make. It supports only the Get operation.
+type DiffREST struct {
+	generator *DeploymentConfigGenerator
+}
+
+func NewDiffREST(generator *DeploymentConfigGenerator) apiserver.RESTStorage {
+	return &DiffREST{generator: generator}
+}
+
+func (s *DiffREST) New() runtime.Object {
+	return &deployapi.DeploymentConfigDiff{}
+}
+
+func (s *DiffREST) List(ctx api.Context, labels, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.New("deploy/generator.DiffREST.List() is not implemented.")
+}
+
+func (s *DiffREST) Get(ctx api.Context, id string) (runtime.Object, error) {
+	return s.generator.Diff(ctx, id)
+}
+
+func (s *DiffREST) Delete(ctx api.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/generator.DiffREST.Delete() is not implemented.")
+}
+
+func (s *DiffREST) Update(ctx api.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/generator.DiffREST.Update() is not implemented.")
+}
+
+func (s *DiffREST) Create(ctx api.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/generator.DiffREST.Create() is not implemented.")
+}
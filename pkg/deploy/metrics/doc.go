@@ -0,0 +1,3 @@
+// Package metrics provides sources of the metrics DeploymentConfigAutoscalers scale
+// DeploymentConfigs on.
+package metrics
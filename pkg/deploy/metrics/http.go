@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultTimeout is the time the endpoint of an HTTPSource has to respond before a metric is
+// considered unavailable.
+const DefaultTimeout = 10 * time.Second
+
+// HTTPSource reads metrics from an HTTP endpoint. The endpoint is queried with a GET request whose
+// namespace, deploymentConfig and metric parameters identify the metric, and responds with a JSON
+// object whose value field holds the current value of the metric per replica, for example:
+//
+//	GET <URL>?namespace=default&deploymentConfig=frontend&metric=requestsPerSecond
+//	{"value": 120}
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+type metricValue struct {
+	Value *int `json:"value"`
+}
+
+// NewHTTPSource creates an HTTPSource which queries the endpoint at rawURL and gives up on requests
+// which take longer than DefaultTimeout.
+func NewHTTPSource(rawURL string) *HTTPSource {
+	return &HTTPSource{
+		URL:    rawURL,
+		Client: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// GetMetric returns the value per replica of the named metric of a DeploymentConfig.
+func (s *HTTPSource) GetMetric(namespace, deploymentConfigID, metric string) (int, error) {
+	endpoint, err := url.Parse(s.URL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid metrics URL %q: %v", s.URL, err)
+	}
+	query := endpoint.Query()
+	query.Set("namespace", namespace)
+	query.Set("deploymentConfig", deploymentConfigID)
+	query.Set("metric", metric)
+	endpoint.RawQuery = query.Encode()
+
+	resp, err := s.Client.Get(endpoint.String())
+	if err != nil {
+		return 0, fmt.Errorf("unable to get metric %s of deploymentConfig %s: %v", metric, deploymentConfigID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unable to get metric %s of deploymentConfig %s: metrics source responded %s", metric, deploymentConfigID, resp.Status)
+	}
+
+	value := metricValue{}
+	if err := json.NewDecoder(resp.Body).Decode(&value); err != nil {
+		return 0, fmt.Errorf("unable to decode metric %s of deploymentConfig %s: %v", metric, deploymentConfigID, err)
+	}
+	if value.Value == nil {
+		return 0, fmt.Errorf("metrics source returned no value for metric %s of deploymentConfig %s", metric, deploymentConfigID)
+	}
+	return *value.Value, nil
+}
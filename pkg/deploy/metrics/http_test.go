@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPSourceGetMetric(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		if query.Get("namespace") != "default" || query.Get("deploymentConfig") != "frontend" || query.Get("metric") != "requestsPerSecond" {
+			t.Errorf("Unexpected query: %s", req.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"value": 120}`)
+	}))
+	defer server.Close()
+
+	value, err := NewHTTPSource(server.URL).GetMetric("default", "frontend", "requestsPerSecond")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != 120 {
+		t.Errorf("Expected value 120, got %d", value)
+	}
+}
+
+func TestHTTPSourceGetMetricErrors(t *testing.T) {
+	testCases := map[string]http.HandlerFunc{
+		"error status": func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "unknown metric", http.StatusNotFound)
+		},
+		"malformed body": func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, `not json`)
+		},
+		"missing value": func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, `{}`)
+		},
+	}
+
+	for name, handler := range testCases {
+		server := httptest.NewServer(handler)
+		if _, err := NewHTTPSource(server.URL).GetMetric("default", "frontend", "requestsPerSecond"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		server.Close()
+	}
+}
+
+func TestHTTPSourceGetMetricTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	source := NewHTTPSource(server.URL)
+	source.Client.Timeout = 10 * time.Millisecond
+	if _, err := source.GetMetric("default", "frontend", "requestsPerSecond"); err == nil {
+		t.Errorf("Expected an error for an endpoint which doesn't respond")
+	}
+}
@@ -0,0 +1,3 @@
+// Package autoscaler provides Registry interface and its RESTStorage
+// implementation for storing DeploymentConfigAutoscaler api objects.
+package autoscaler
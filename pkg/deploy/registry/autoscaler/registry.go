@@ -0,0 +1,18 @@
+package autoscaler
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	api "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// Registry is an interface for things that know how to store DeploymentConfigAutoscalers.
+type Registry interface {
+	ListDeploymentConfigAutoscalers(ctx kapi.Context, selector labels.Selector) (*api.DeploymentConfigAutoscalerList, error)
+	WatchDeploymentConfigAutoscalers(ctx kapi.Context, resourceVersion string, filter func(autoscaler *api.DeploymentConfigAutoscaler) bool) (watch.Interface, error)
+	GetDeploymentConfigAutoscaler(ctx kapi.Context, id string) (*api.DeploymentConfigAutoscaler, error)
+	CreateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *api.DeploymentConfigAutoscaler) error
+	UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *api.DeploymentConfigAutoscaler) error
+	DeleteDeploymentConfigAutoscaler(ctx kapi.Context, id string) error
+}
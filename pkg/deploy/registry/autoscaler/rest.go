@@ -0,0 +1,129 @@
+package autoscaler
+
+import (
+	"fmt"
+
+	"code.google.com/p/go-uuid/uuid"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	validation "github.com/openshift/origin/pkg/deploy/api/validation"
+)
+
+// REST is an implementation of RESTStorage for the api server.
+type REST struct {
+	registry Registry
+}
+
+// NewREST creates a new REST backed by the given registry.
+func NewREST(registry Registry) apiserver.RESTStorage {
+	return &REST{
+		registry: registry,
+	}
+}
+
+// New creates a new DeploymentConfigAutoscaler for use with Create and Update.
+func (s *REST) New() runtime.Object {
+	return &deployapi.DeploymentConfigAutoscaler{}
+}
+
+// List obtains a list of DeploymentConfigAutoscalers that match selector.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	autoscalers, err := s.registry.ListDeploymentConfigAutoscalers(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return autoscalers, nil
+}
+
+// Watch begins watching for new, changed, or deleted DeploymentConfigAutoscalers.
+func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.registry.WatchDeploymentConfigAutoscalers(ctx, resourceVersion, func(autoscaler *deployapi.DeploymentConfigAutoscaler) bool {
+		fields := labels.Set{
+			"ID":                 autoscaler.ID,
+			"deploymentConfigID": autoscaler.DeploymentConfigID,
+		}
+		return label.Matches(labels.Set(autoscaler.Labels)) && field.Matches(fields)
+	})
+}
+
+// Get obtains the DeploymentConfigAutoscaler specified by its id.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	autoscaler, err := s.registry.GetDeploymentConfigAutoscaler(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return autoscaler, err
+}
+
+// Delete asynchronously deletes the DeploymentConfigAutoscaler specified by its id.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return &kapi.Status{Status: kapi.StatusSuccess}, s.registry.DeleteDeploymentConfigAutoscaler(ctx, id)
+	}), nil
+}
+
+// Create registers a given new DeploymentConfigAutoscaler instance to s.registry.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	autoscaler, ok := obj.(*deployapi.DeploymentConfigAutoscaler)
+	if !ok {
+		return nil, fmt.Errorf("not a deploymentConfigAutoscaler: %#v", obj)
+	}
+
+	autoscaler.CreationTimestamp = util.Now()
+
+	if len(autoscaler.ID) == 0 {
+		autoscaler.ID = uuid.NewUUID().String()
+	}
+	if !kapi.ValidNamespace(ctx, &autoscaler.TypeMeta) {
+		return nil, kerrors.NewConflict("deploymentConfigAutoscaler", autoscaler.Namespace, fmt.Errorf("DeploymentConfigAutoscaler.Namespace does not match the provided context"))
+	}
+
+	glog.Infof("Creating deploymentConfigAutoscaler with namespace::ID: %v::%v", autoscaler.Namespace, autoscaler.ID)
+
+	if errs := validation.ValidateDeploymentConfigAutoscaler(autoscaler); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("deploymentConfigAutoscaler", autoscaler.ID, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		err := s.registry.CreateDeploymentConfigAutoscaler(ctx, autoscaler)
+		if err != nil {
+			return nil, err
+		}
+		return autoscaler, nil
+	}), nil
+}
+
+// Update replaces a given DeploymentConfigAutoscaler instance with an existing instance in s.registry.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	autoscaler, ok := obj.(*deployapi.DeploymentConfigAutoscaler)
+	if !ok {
+		return nil, fmt.Errorf("not a deploymentConfigAutoscaler: %#v", obj)
+	}
+	if len(autoscaler.ID) == 0 {
+		return nil, fmt.Errorf("id is unspecified: %#v", autoscaler)
+	}
+	if !kapi.ValidNamespace(ctx, &autoscaler.TypeMeta) {
+		return nil, kerrors.NewConflict("deploymentConfigAutoscaler", autoscaler.Namespace, fmt.Errorf("DeploymentConfigAutoscaler.Namespace does not match the provided context"))
+	}
+	if errs := validation.ValidateDeploymentConfigAutoscaler(autoscaler); len(errs) > 0 {
+		return nil, kerrors.NewInvalid("deploymentConfigAutoscaler", autoscaler.ID, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		err := s.registry.UpdateDeploymentConfigAutoscaler(ctx, autoscaler)
+		if err != nil {
+			return nil, err
+		}
+		return autoscaler, nil
+	}), nil
+}
@@ -0,0 +1,121 @@
+package autoscaler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+func okAutoscaler() *api.DeploymentConfigAutoscaler {
+	return &api.DeploymentConfigAutoscaler{
+		TypeMeta:           kapi.TypeMeta{ID: "foo"},
+		DeploymentConfigID: "config",
+		MinReplicas:        1,
+		MaxReplicas:        5,
+		Metric:             "requestsPerSecond",
+		TargetValue:        100,
+	}
+}
+
+func TestListDeploymentConfigAutoscalersError(t *testing.T) {
+	mockRegistry := test.NewDeploymentConfigAutoscalerRegistry()
+	mockRegistry.Err = fmt.Errorf("test error")
+	storage := REST{registry: mockRegistry}
+
+	autoscalers, err := storage.List(kapi.NewDefaultContext(), nil, nil)
+	if err != mockRegistry.Err {
+		t.Errorf("Expected %#v, Got %#v", mockRegistry.Err, err)
+	}
+	if autoscalers != nil {
+		t.Errorf("Unexpected non-nil autoscalers list: %#v", autoscalers)
+	}
+}
+
+func TestListDeploymentConfigAutoscalersPopulatedList(t *testing.T) {
+	mockRegistry := test.NewDeploymentConfigAutoscalerRegistry()
+	mockRegistry.Autoscalers = &api.DeploymentConfigAutoscalerList{
+		Items: []api.DeploymentConfigAutoscaler{*okAutoscaler()},
+	}
+	storage := REST{registry: mockRegistry}
+
+	list, err := storage.List(kapi.NewDefaultContext(), labels.Everything(), labels.Everything())
+	if err != nil {
+		t.Errorf("Unexpected non-nil error: %#v", err)
+	}
+	if e, a := 1, len(list.(*api.DeploymentConfigAutoscalerList).Items); e != a {
+		t.Errorf("Expected %d autoscalers, got %d", e, a)
+	}
+}
+
+func TestCreateDeploymentConfigAutoscalerOK(t *testing.T) {
+	mockRegistry := test.NewDeploymentConfigAutoscalerRegistry()
+	storage := REST{registry: mockRegistry}
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), okAutoscaler())
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+
+	select {
+	case result := <-channel:
+		autoscaler, ok := result.(*api.DeploymentConfigAutoscaler)
+		if !ok {
+			t.Fatalf("Expected deploymentConfigAutoscaler type, got: %#v", result)
+		}
+		if autoscaler.ID != "foo" || autoscaler.CreationTimestamp.IsZero() {
+			t.Errorf("Unexpected deploymentConfigAutoscaler: %#v", autoscaler)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Errorf("Timed out waiting for result")
+	}
+	if mockRegistry.Autoscaler == nil {
+		t.Errorf("Expected the autoscaler to be saved")
+	}
+}
+
+func TestCreateDeploymentConfigAutoscalerInvalid(t *testing.T) {
+	storage := REST{registry: test.NewDeploymentConfigAutoscalerRegistry()}
+	autoscaler := okAutoscaler()
+	autoscaler.MaxReplicas = 0
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), autoscaler)
+	if channel != nil {
+		t.Errorf("Expected a nil channel, got %v", channel)
+	}
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != 422 {
+		t.Errorf("Expected an invalid error, got %#v", err)
+	}
+}
+
+func TestUpdateDeploymentConfigAutoscalerMissingID(t *testing.T) {
+	storage := REST{registry: test.NewDeploymentConfigAutoscalerRegistry()}
+	autoscaler := okAutoscaler()
+	autoscaler.ID = ""
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), autoscaler)
+	if channel != nil {
+		t.Errorf("Expected nil channel, got %v", channel)
+	}
+	if err == nil || !strings.Contains(err.Error(), "id is unspecified") {
+		t.Errorf("Expected 'id is unspecified' error, got %v", err)
+	}
+}
+
+func TestUpdateDeploymentConfigAutoscalerConflictingNamespace(t *testing.T) {
+	storage := REST{registry: test.NewDeploymentConfigAutoscalerRegistry()}
+	autoscaler := okAutoscaler()
+	autoscaler.Namespace = "some-value"
+
+	_, err := storage.Update(kapi.WithNamespace(kapi.NewContext(), "legal-name"), autoscaler)
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != http.StatusConflict {
+		t.Errorf("Expected a conflict error, got %#v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// CanaryREST is a RESTStorage implementation which promotes or aborts deployments in the
+// DeploymentStatusCanary status. It supports only the Create operation.
+type CanaryREST struct {
+	registry                    Registry
+	replicationControllerClient kclient.ReplicationControllerInterface
+}
+
+// NewCanaryREST creates a new CanaryREST which updates deployments in the registry and scales their
+// replication controllers with the given client.
+func NewCanaryREST(registry Registry, replicationControllerClient kclient.ReplicationControllerInterface) apiserver.RESTStorage {
+	return &CanaryREST{
+		registry:                    registry,
+		replicationControllerClient: replicationControllerClient,
+	}
+}
+
+// New creates a new DeploymentCanaryDecision for use with Create.
+func (s *CanaryREST) New() runtime.Object {
+	return &deployapi.DeploymentCanaryDecision{}
+}
+
+func (s *CanaryREST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deploy.CanaryREST.List() is not implemented.")
+}
+
+func (s *CanaryREST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deploy.CanaryREST.Get() is not implemented.")
+}
+
+func (s *CanaryREST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deploy.CanaryREST.Delete() is not implemented.")
+}
+
+func (s *CanaryREST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deploy.CanaryREST.Update() is not implemented.")
+}
+
+// Create applies the decision to the deployment with the ID of the decision and returns the updated
+// deployment.
+func (s *CanaryREST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	decision, ok := obj.(*deployapi.DeploymentCanaryDecision)
+	if !ok {
+		return nil, fmt.Errorf("not a deploymentCanaryDecision: %#v", obj)
+	}
+	if len(decision.ID) == 0 {
+		return nil, fmt.Errorf("id is unspecified: %#v", decision)
+	}
+	if decision.Action != deployapi.CanaryActionPromote && decision.Action != deployapi.CanaryActionAbort {
+		return nil, kerrors.NewInvalid("deploymentCanaryDecision", decision.ID, kerrors.ErrorList{kerrors.NewFieldNotSupported("action", decision.Action)})
+	}
+
+	deployment, err := s.registry.GetDeployment(ctx, decision.ID)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.Status != deployapi.DeploymentStatusCanary {
+		return nil, kerrors.NewConflict("deployment", deployment.ID, fmt.Errorf("deployment is not a canary awaiting a decision; its status is %s", deployment.Status))
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		controllers, err := s.listControllers(ctx, deployment)
+		if err != nil {
+			return nil, err
+		}
+
+		if decision.Action == deployapi.CanaryActionPromote {
+			err = s.promote(ctx, deployment, controllers)
+		} else {
+			err = s.abort(ctx, deployment, controllers)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.registry.UpdateDeployment(ctx, deployment); err != nil {
+			return nil, err
+		}
+		return deployment, nil
+	}), nil
+}
+
+// promote scales the replication controller of the deployment to all of its replicas, removes the
+// replication controllers of previous deployments and completes the deployment.
+func (s *CanaryREST) promote(ctx kapi.Context, deployment *deployapi.Deployment, controllers []kapi.ReplicationController) error {
+	glog.Infof("Promoting canary deployment with namespace::ID: %v::%v", deployment.Namespace, deployment.ID)
+	for i := range controllers {
+		rc := &controllers[i]
+		if rc.Labels[deployapi.DeploymentLabel] != deployment.ID {
+			continue
+		}
+		rc.DesiredState.Replicas = deployment.ControllerTemplate.Replicas
+		if _, err := s.replicationControllerClient.UpdateReplicationController(ctx, rc); err != nil {
+			return fmt.Errorf("unable to scale replication controller %s: %v", rc.ID, err)
+		}
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerScaled,
+			"Scaled replication controller %s to %d replicas", rc.ID, rc.DesiredState.Replicas)
+	}
+
+	for i := range controllers {
+		rc := &controllers[i]
+		if rc.Labels[deployapi.DeploymentLabel] == deployment.ID {
+			continue
+		}
+		rc.DesiredState.Replicas = 0
+		if _, err := s.replicationControllerClient.UpdateReplicationController(ctx, rc); err != nil {
+			return fmt.Errorf("unable to stop replication controller %s: %v", rc.ID, err)
+		}
+		if err := s.replicationControllerClient.DeleteReplicationController(ctx, rc.ID); err != nil {
+			return fmt.Errorf("unable to remove replication controller %s: %v", rc.ID, err)
+		}
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerDeleted,
+			"Deleted replication controller %s", rc.ID)
+	}
+
+	deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventCanaryPromoted, "Canary promoted")
+	deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusComplete, "")
+	return nil
+}
+
+// abort scales the replication controller of the deployment to zero, returns its replicas to the
+// baseline replication controller and cancels the deployment.
+func (s *CanaryREST) abort(ctx kapi.Context, deployment *deployapi.Deployment, controllers []kapi.ReplicationController) error {
+	glog.Infof("Aborting canary deployment with namespace::ID: %v::%v", deployment.Namespace, deployment.ID)
+	baseline := deployutil.CanaryBaselineController(deployment, controllers)
+
+	for i := range controllers {
+		rc := &controllers[i]
+		if rc.Labels[deployapi.DeploymentLabel] != deployment.ID {
+			continue
+		}
+		replicas := rc.DesiredState.Replicas
+		rc.DesiredState.Replicas = 0
+		if _, err := s.replicationControllerClient.UpdateReplicationController(ctx, rc); err != nil {
+			return fmt.Errorf("unable to stop replication controller %s: %v", rc.ID, err)
+		}
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerScaled,
+			"Scaled replication controller %s to 0 replicas", rc.ID)
+
+		if baseline == nil {
+			continue
+		}
+		baseline.DesiredState.Replicas += replicas
+		if _, err := s.replicationControllerClient.UpdateReplicationController(ctx, baseline); err != nil {
+			return fmt.Errorf("unable to restore replication controller %s: %v", baseline.ID, err)
+		}
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventReplicationControllerScaled,
+			"Restored replication controller %s to %d replicas", baseline.ID, baseline.DesiredState.Replicas)
+	}
+
+	message := "Canary aborted"
+	deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventCanaryAborted, "%s", message)
+	deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusCancelled, message)
+	return nil
+}
+
+// listControllers returns the replication controllers of the deployment's DeploymentConfig, or of the
+// deployment alone if it doesn't belong to a config.
+func (s *CanaryREST) listControllers(ctx kapi.Context, deployment *deployapi.Deployment) ([]kapi.ReplicationController, error) {
+	selector, _ := labels.ParseSelector(deployapi.DeploymentLabel + "=" + deployment.ID)
+	if configID, hasConfigID := deployment.Labels[deployapi.DeploymentConfigLabel]; hasConfigID {
+		selector, _ = labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + configID)
+	}
+
+	controllers, err := s.replicationControllerClient.ListReplicationControllers(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list replication controllers for deployment %s: %v", deployment.ID, err)
+	}
+	return controllers.Items, nil
+}
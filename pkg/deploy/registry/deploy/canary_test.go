@@ -0,0 +1,112 @@
+package deploy
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+func canaryDeployment() *api.Deployment {
+	strategy := deploytest.OkStrategy()
+	strategy.Canary = &api.CanaryDeploymentParams{Percentage: 25}
+	template := deploytest.OkControllerTemplate()
+	template.Replicas = 4
+	return &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-2"},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusCanary,
+		Strategy:           strategy,
+		ControllerTemplate: template,
+	}
+}
+
+func decide(t *testing.T, storage *CanaryREST, action api.CanaryAction) *api.Deployment {
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentCanaryDecision{
+		TypeMeta: kapi.TypeMeta{ID: "config-2"},
+		Action:   action,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case result := <-channel:
+		deployment, ok := result.(*api.Deployment)
+		if !ok {
+			t.Fatalf("Expected deployment type, got: %#v", result)
+		}
+		return deployment
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+	return nil
+}
+
+func TestCanaryPromote(t *testing.T) {
+	registry := test.NewDeploymentRegistry()
+	registry.Deployment = canaryDeployment()
+	rcClient := &testControllerClient{
+		Controllers: []kapi.ReplicationController{controllerFor("config-1", 3), controllerFor("config-2", 1)},
+		Updated:     map[string]int{},
+	}
+	storage := &CanaryREST{registry: registry, replicationControllerClient: rcClient}
+
+	deployment := decide(t, storage, api.CanaryActionPromote)
+
+	if e, a := api.DeploymentStatusComplete, deployment.Status; e != a {
+		t.Fatalf("Expected status %s, got %s", e, a)
+	}
+	if e, a := 4, rcClient.Updated["config-2"]; e != a {
+		t.Errorf("Expected the canary to be scaled to %d, got %d", e, a)
+	}
+	if len(rcClient.Deleted) != 1 || rcClient.Deleted[0] != "config-1" {
+		t.Errorf("Expected the previous replication controller to be removed, got %v", rcClient.Deleted)
+	}
+}
+
+func TestCanaryAbort(t *testing.T) {
+	registry := test.NewDeploymentRegistry()
+	registry.Deployment = canaryDeployment()
+	rcClient := &testControllerClient{
+		Controllers: []kapi.ReplicationController{controllerFor("config-1", 3), controllerFor("config-2", 1)},
+		Updated:     map[string]int{},
+	}
+	storage := &CanaryREST{registry: registry, replicationControllerClient: rcClient}
+
+	deployment := decide(t, storage, api.CanaryActionAbort)
+
+	if e, a := api.DeploymentStatusCancelled, deployment.Status; e != a {
+		t.Fatalf("Expected status %s, got %s", e, a)
+	}
+	if e, a := 0, rcClient.Updated["config-2"]; e != a {
+		t.Errorf("Expected the canary to be scaled to %d, got %d", e, a)
+	}
+	if e, a := 4, rcClient.Updated["config-1"]; e != a {
+		t.Errorf("Expected the previous replication controller to be restored to %d, got %d", e, a)
+	}
+	if len(rcClient.Deleted) != 0 {
+		t.Errorf("Unexpected removal of replication controllers %v", rcClient.Deleted)
+	}
+}
+
+func TestCanaryDecisionNotCanary(t *testing.T) {
+	registry := test.NewDeploymentRegistry()
+	registry.Deployment = canaryDeployment()
+	registry.Deployment.Status = api.DeploymentStatusComplete
+	storage := &CanaryREST{registry: registry, replicationControllerClient: &testControllerClient{Updated: map[string]int{}}}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentCanaryDecision{
+		TypeMeta: kapi.TypeMeta{ID: "config-2"},
+		Action:   api.CanaryActionPromote,
+	})
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != http.StatusConflict {
+		t.Errorf("Expected a conflict error, got %#v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package deploy
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// Canceller stops the work being performed on behalf of a Deployment.
+type Canceller interface {
+	// Cancel stops the given deployment, returning a message describing what was done.
+	Cancel(ctx kapi.Context, deployment *deployapi.Deployment) (string, error)
+}
+
+// DeploymentCanceller cancels deployments by deleting the deployer pod of a CustomPod deployment
+// and then applying the CancelPolicy of the deployment strategy to the replication controllers of
+// the deployment's DeploymentConfig.
+type DeploymentCanceller struct {
+	Registry                    Registry
+	PodClient                   kclient.PodInterface
+	ReplicationControllerClient kclient.ReplicationControllerInterface
+}
+
+// Cancel stops the deployer pod of the deployment, if any, and applies the cancel policy.
+func (c *DeploymentCanceller) Cancel(ctx kapi.Context, deployment *deployapi.Deployment) (string, error) {
+	if deployment.Strategy.Type == deployapi.DeploymentStrategyTypeCustomPod {
+		podID := deployutil.DeployerPodIDForDeployment(deployment)
+		glog.V(2).Infof("Deleting deployer pod %s for cancelled deployment %s", podID, deployment.ID)
+		if err := c.PodClient.DeletePod(ctx, podID); err != nil && !kerrors.IsNotFound(err) {
+			return "", fmt.Errorf("unable to stop deployer pod %s: %v", podID, err)
+		}
+	}
+
+	if deployment.Strategy.CancelPolicy != deployapi.DeploymentCancelPolicyRestore {
+		return "Deployment cancelled; replication controllers were left in place", nil
+	}
+
+	configID, hasConfigID := deployment.Labels[deployapi.DeploymentConfigLabel]
+	if !hasConfigID {
+		return "Deployment cancelled; no previous deployment to restore", c.removeControllers(ctx, deployment, nil)
+	}
+
+	selector, _ := labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + configID)
+	controllers, err := c.ReplicationControllerClient.ListReplicationControllers(ctx, selector)
+	if err != nil {
+		return "", fmt.Errorf("unable to list replication controllers for deploymentConfig %s: %v", configID, err)
+	}
+	if err := c.removeControllers(ctx, deployment, controllers); err != nil {
+		return "", err
+	}
+
+	previous, err := c.previousDeployment(ctx, deployment, selector)
+	if err != nil {
+		return "", err
+	}
+	if previous == nil {
+		return "Deployment cancelled; no previous deployment to restore", nil
+	}
+
+	if err := c.restoreControllers(ctx, previous, controllers); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Deployment cancelled; restored previous deployment %s", previous.ID), nil
+}
+
+// removeControllers scales down and deletes any replication controllers created for the deployment.
+func (c *DeploymentCanceller) removeControllers(ctx kapi.Context, deployment *deployapi.Deployment, controllers *kapi.ReplicationControllerList) error {
+	if controllers == nil {
+		selector, _ := labels.ParseSelector(deployapi.DeploymentLabel + "=" + deployment.ID)
+		list, err := c.ReplicationControllerClient.ListReplicationControllers(ctx, selector)
+		if err != nil {
+			return fmt.Errorf("unable to list replication controllers for deployment %s: %v", deployment.ID, err)
+		}
+		controllers = list
+	}
+
+	for i := range controllers.Items {
+		rc := &controllers.Items[i]
+		if rc.Labels[deployapi.DeploymentLabel] != deployment.ID {
+			continue
+		}
+
+		glog.V(2).Infof("Removing replication controller %s of cancelled deployment %s", rc.ID, deployment.ID)
+		rc.DesiredState.Replicas = 0
+		if _, err := c.ReplicationControllerClient.UpdateReplicationController(ctx, rc); err != nil {
+			return fmt.Errorf("unable to stop replication controller %s: %v", rc.ID, err)
+		}
+		if err := c.ReplicationControllerClient.DeleteReplicationController(ctx, rc.ID); err != nil {
+			return fmt.Errorf("unable to remove replication controller %s: %v", rc.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// previousDeployment returns the most recently created completed deployment sharing the selector
+// of the given deployment, or nil if there is none.
+func (c *DeploymentCanceller) previousDeployment(ctx kapi.Context, deployment *deployapi.Deployment, selector labels.Selector) (*deployapi.Deployment, error) {
+	deployments, err := c.Registry.ListDeployments(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list deployments: %v", err)
+	}
+
+	var previous *deployapi.Deployment
+	for i := range deployments.Items {
+		candidate := &deployments.Items[i]
+		if candidate.ID == deployment.ID || candidate.Status != deployapi.DeploymentStatusComplete {
+			continue
+		}
+		if previous == nil || previous.CreationTimestamp.Before(candidate.CreationTimestamp.Time) {
+			previous = candidate
+		}
+	}
+
+	return previous, nil
+}
+
+// restoreControllers scales the replication controller of the previous deployment back to its
+// desired replica count, recreating it if it has already been removed.
+func (c *DeploymentCanceller) restoreControllers(ctx kapi.Context, previous *deployapi.Deployment, controllers *kapi.ReplicationControllerList) error {
+	for i := range controllers.Items {
+		rc := &controllers.Items[i]
+		if rc.Labels[deployapi.DeploymentLabel] != previous.ID {
+			continue
+		}
+
+		glog.V(2).Infof("Restoring replication controller %s of deployment %s", rc.ID, previous.ID)
+		rc.DesiredState.Replicas = previous.ControllerTemplate.Replicas
+		if _, err := c.ReplicationControllerClient.UpdateReplicationController(ctx, rc); err != nil {
+			return fmt.Errorf("unable to restore replication controller %s: %v", rc.ID, err)
+		}
+		return nil
+	}
+
+	glog.V(2).Infof("Recreating replication controller for deployment %s", previous.ID)
+	if _, err := c.ReplicationControllerClient.CreateReplicationController(ctx, deployutil.ReplicationControllerForDeployment(previous)); err != nil {
+		return fmt.Errorf("unable to recreate replication controller for deployment %s: %v", previous.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package deploy
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+// testControllerClient is a fake ReplicationControllerInterface which lists a fixed set of
+// controllers and records updates and deletions.
+type testControllerClient struct {
+	kclient.Fake
+	Controllers []kapi.ReplicationController
+	Updated     map[string]int
+	Deleted     []string
+	Created     []*kapi.ReplicationController
+}
+
+func (c *testControllerClient) ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+	list := &kapi.ReplicationControllerList{}
+	for _, rc := range c.Controllers {
+		if selector.Matches(labels.Set(rc.Labels)) {
+			list.Items = append(list.Items, rc)
+		}
+	}
+	return list, nil
+}
+
+func (c *testControllerClient) UpdateReplicationController(ctx kapi.Context, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	c.Updated[ctrl.ID] = ctrl.DesiredState.Replicas
+	return ctrl, nil
+}
+
+func (c *testControllerClient) CreateReplicationController(ctx kapi.Context, ctrl *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	c.Created = append(c.Created, ctrl)
+	return ctrl, nil
+}
+
+func (c *testControllerClient) DeleteReplicationController(ctx kapi.Context, id string) error {
+	c.Deleted = append(c.Deleted, id)
+	return nil
+}
+
+func cancelledDeployment(policy api.DeploymentCancelPolicy) *api.Deployment {
+	strategy := deploytest.OkCustomPodStrategy()
+	strategy.CancelPolicy = policy
+	return &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-2", CreationTimestamp: util.Now()},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusRunning,
+		Strategy:           strategy,
+		ControllerTemplate: deploytest.OkControllerTemplate(),
+	}
+}
+
+func previousDeployment() *api.Deployment {
+	template := deploytest.OkControllerTemplate()
+	template.Replicas = 3
+	return &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-1"},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusComplete,
+		Strategy:           deploytest.OkStrategy(),
+		ControllerTemplate: template,
+	}
+}
+
+func controllerFor(deploymentID string, replicas int) kapi.ReplicationController {
+	return kapi.ReplicationController{
+		TypeMeta:     kapi.TypeMeta{ID: deploymentID},
+		Labels:       map[string]string{api.DeploymentConfigLabel: "config", api.DeploymentLabel: deploymentID},
+		DesiredState: kapi.ReplicationControllerState{Replicas: replicas},
+	}
+}
+
+func TestCancelLeave(t *testing.T) {
+	podClient := &kclient.Fake{}
+	rcClient := &testControllerClient{Updated: map[string]int{}}
+	canceller := &DeploymentCanceller{
+		Registry:                    test.NewDeploymentRegistry(),
+		PodClient:                   podClient,
+		ReplicationControllerClient: rcClient,
+	}
+
+	if _, err := canceller.Cancel(kapi.NewDefaultContext(), cancelledDeployment(api.DeploymentCancelPolicyLeave)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(podClient.Actions) != 1 || podClient.Actions[0].Action != "delete-pod" || podClient.Actions[0].Value != "deploy-config-2" {
+		t.Fatalf("expected the deployer pod to be deleted, got %#v", podClient.Actions)
+	}
+	if len(rcClient.Updated) != 0 || len(rcClient.Deleted) != 0 || len(rcClient.Created) != 0 {
+		t.Fatalf("expected replication controllers to be left alone, got %#v", rcClient)
+	}
+}
+
+func TestCancelRestore(t *testing.T) {
+	registry := test.NewDeploymentRegistry()
+	registry.Deployments = &api.DeploymentList{
+		Items: []api.Deployment{*previousDeployment(), *cancelledDeployment(api.DeploymentCancelPolicyRestore)},
+	}
+	rcClient := &testControllerClient{
+		Controllers: []kapi.ReplicationController{controllerFor("config-1", 0), controllerFor("config-2", 3)},
+		Updated:     map[string]int{},
+	}
+	canceller := &DeploymentCanceller{
+		Registry:                    registry,
+		PodClient:                   &kclient.Fake{},
+		ReplicationControllerClient: rcClient,
+	}
+
+	if _, err := canceller.Cancel(kapi.NewDefaultContext(), cancelledDeployment(api.DeploymentCancelPolicyRestore)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rcClient.Deleted) != 1 || rcClient.Deleted[0] != "config-2" {
+		t.Fatalf("expected the controller of the cancelled deployment to be deleted, got %v", rcClient.Deleted)
+	}
+	if e, a := 3, rcClient.Updated["config-1"]; e != a {
+		t.Fatalf("expected the previous controller to be restored to %d replicas, got %d", e, a)
+	}
+}
+
+func TestCancelRestoreRecreatesPreviousController(t *testing.T) {
+	registry := test.NewDeploymentRegistry()
+	registry.Deployments = &api.DeploymentList{Items: []api.Deployment{*previousDeployment()}}
+	rcClient := &testControllerClient{
+		Controllers: []kapi.ReplicationController{controllerFor("config-2", 3)},
+		Updated:     map[string]int{},
+	}
+	canceller := &DeploymentCanceller{
+		Registry:                    registry,
+		PodClient:                   &kclient.Fake{},
+		ReplicationControllerClient: rcClient,
+	}
+
+	if _, err := canceller.Cancel(kapi.NewDefaultContext(), cancelledDeployment(api.DeploymentCancelPolicyRestore)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rcClient.Created) != 1 {
+		t.Fatalf("expected the previous controller to be recreated, got %#v", rcClient.Created)
+	}
+	if e, a := "config-1", rcClient.Created[0].Labels[api.DeploymentLabel]; e != a {
+		t.Fatalf("expected recreated controller for deployment %s, got %s", e, a)
+	}
+}
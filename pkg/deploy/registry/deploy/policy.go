@@ -0,0 +1,111 @@
+package deploy
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// ImagePolicy decides whether the images of a Deployment may be deployed.
+type ImagePolicy interface {
+	// Admit returns an error if the deployment may not be created.
+	Admit(ctx kapi.Context, deployment *deployapi.Deployment) error
+}
+
+// SignedImagePolicy admits a Deployment only if every container of its pod template runs an Image
+// with a verified signature. The pull spec of each container is resolved to an image ID by its
+// digest or image ID, or by the tag of the ImageRepository in the Deployment's namespace, and must
+// resolve to the image recorded on the Deployment by an image change trigger. Containers whose pull
+// spec can't be resolved, or which aren't recorded, run an image of unknown origin and are refused.
+type SignedImagePolicy struct {
+	Images       imageGetter
+	Repositories imageRepositoryFinder
+}
+
+type imageGetter interface {
+	GetImage(ctx kapi.Context, id string) (*imageapi.Image, error)
+}
+
+type imageRepositoryFinder interface {
+	FindImageRepository(ctx kapi.Context, dockerRepo string) (*imageapi.ImageRepository, error)
+}
+
+// Admit implements ImagePolicy.
+func (p *SignedImagePolicy) Admit(ctx kapi.Context, deployment *deployapi.Deployment) error {
+	images := make(map[string]string)
+	for _, image := range deployment.Images {
+		images[image.ContainerName] = image.ImageID
+	}
+
+	errs := kerrors.ErrorList{}
+	for i, container := range deployment.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers {
+		field := fmt.Sprintf("controllerTemplate.podTemplate.desiredState.manifest.containers[%d].image", i)
+		imageID, ok := images[container.Name]
+		if !ok {
+			errs = append(errs, kerrors.NewFieldForbidden(field, container.Image))
+			continue
+		}
+		resolvedID, err := p.resolveImageID(ctx, container.Image)
+		if err != nil {
+			return err
+		}
+		if len(resolvedID) == 0 || resolvedID != imageID {
+			errs = append(errs, kerrors.NewFieldForbidden(field, container.Image))
+			continue
+		}
+		image, err := p.Images.GetImage(ctx, imageID)
+		if kerrors.IsNotFound(err) {
+			errs = append(errs, kerrors.NewFieldForbidden(field, container.Image))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !hasVerifiedSignature(image) {
+			errs = append(errs, kerrors.NewFieldForbidden(field, container.Image))
+		}
+	}
+
+	if len(errs) > 0 {
+		return kerrors.NewInvalid("deployment", deployment.ID, errs)
+	}
+	return nil
+}
+
+// resolveImageID returns the ID of the image the pull spec refers to, or an empty string if it can't
+// be resolved. A pull spec without a tag refers to the latest tag, as it does for Docker.
+func (p *SignedImagePolicy) resolveImageID(ctx kapi.Context, pullSpec string) (string, error) {
+	ref, err := imageapi.ParseDockerImageReference(pullSpec)
+	if err != nil {
+		return "", nil
+	}
+	if len(ref.ID) > 0 {
+		return ref.ID, nil
+	}
+
+	repo, err := p.Repositories.FindImageRepository(ctx, ref.RepositoryName())
+	if kerrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	tag := ref.Tag
+	if len(tag) == 0 {
+		tag = "latest"
+	}
+	return repo.Tags[tag], nil
+}
+
+func hasVerifiedSignature(image *imageapi.Image) bool {
+	for _, signature := range image.Signatures {
+		if signature.Status == imageapi.ImageSignatureVerified {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package deploy
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+type fakeImages map[string]*imageapi.Image
+
+func (f fakeImages) GetImage(ctx kapi.Context, id string) (*imageapi.Image, error) {
+	image, ok := f[id]
+	if !ok {
+		return nil, kerrors.NewNotFound("image", id)
+	}
+	return image, nil
+}
+
+type fakeImageRepositories []imageapi.ImageRepository
+
+func (f fakeImageRepositories) FindImageRepository(ctx kapi.Context, dockerRepo string) (*imageapi.ImageRepository, error) {
+	for i := range f {
+		if f[i].DockerImageRepository == dockerRepo {
+			return &f[i], nil
+		}
+	}
+	return nil, kerrors.NewNotFound("imageRepository", dockerRepo)
+}
+
+func imageWithSignature(id string, status imageapi.ImageSignatureStatus) *imageapi.Image {
+	return &imageapi.Image{
+		TypeMeta: kapi.TypeMeta{ID: id},
+		Signatures: []imageapi.ImageSignature{
+			{
+				TypeMeta:  kapi.TypeMeta{ID: "security"},
+				Image:     id,
+				Signer:    "security@example.com",
+				Signature: []byte("signature"),
+				Status:    status,
+			},
+		},
+	}
+}
+
+func deploymentRunning(images ...api.DeploymentImage) *api.Deployment {
+	return deploymentRunningImage("localhost:5000/app/web@image1", images...)
+}
+
+func deploymentRunningImage(pullSpec string, images ...api.DeploymentImage) *api.Deployment {
+	deployment := &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "foo"},
+		Strategy:           deploytest.OkStrategy(),
+		ControllerTemplate: deploytest.OkControllerTemplate(),
+		Images:             images,
+	}
+	manifest := &deployment.ControllerTemplate.PodTemplate.DesiredState.Manifest
+	manifest.Containers = []kapi.Container{
+		{Name: "web", Image: pullSpec},
+	}
+	return deployment
+}
+
+func testSignedImagePolicy() *SignedImagePolicy {
+	return &SignedImagePolicy{
+		Images: fakeImages{
+			"image1": imageWithSignature("image1", imageapi.ImageSignatureVerified),
+			"image2": imageWithSignature("image2", imageapi.ImageSignatureUnverified),
+			"image3": imageWithSignature("image3", imageapi.ImageSignatureRejected),
+			"image4": {TypeMeta: kapi.TypeMeta{ID: "image4"}},
+		},
+		Repositories: fakeImageRepositories{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "web", Namespace: kapi.NamespaceDefault},
+				DockerImageRepository: "localhost:5000/app/web",
+				Tags:                  map[string]string{"latest": "image1", "stable": "image1", "unsigned": "image4"},
+			},
+		},
+	}
+}
+
+func TestSignedImagePolicy(t *testing.T) {
+	policy := testSignedImagePolicy()
+	verified := api.DeploymentImage{ContainerName: "web", ImageID: "image1"}
+
+	testCases := map[string]struct {
+		deployment *api.Deployment
+		admit      bool
+	}{
+		"verified":           {deploymentRunning(verified), true},
+		"verified by tag":    {deploymentRunningImage("localhost:5000/app/web:stable", verified), true},
+		"verified latest":    {deploymentRunningImage("localhost:5000/app/web", verified), true},
+		"unverified":         {deploymentRunningImage("localhost:5000/app/web@image2", api.DeploymentImage{ContainerName: "web", ImageID: "image2"}), false},
+		"rejected":           {deploymentRunningImage("localhost:5000/app/web@image3", api.DeploymentImage{ContainerName: "web", ImageID: "image3"}), false},
+		"unsigned":           {deploymentRunningImage("localhost:5000/app/web@image4", api.DeploymentImage{ContainerName: "web", ImageID: "image4"}), false},
+		"unknown image":      {deploymentRunningImage("localhost:5000/app/web@image5", api.DeploymentImage{ContainerName: "web", ImageID: "image5"}), false},
+		"unrecorded":         {deploymentRunning(), false},
+		"other container":    {deploymentRunning(api.DeploymentImage{ContainerName: "db", ImageID: "image1"}), false},
+		"other image by ID":  {deploymentRunningImage("localhost:5000/app/web@image4", verified), false},
+		"other image by tag": {deploymentRunningImage("localhost:5000/app/web:unsigned", verified), false},
+		"unknown tag":        {deploymentRunningImage("localhost:5000/app/web:image1", verified), false},
+		"unknown repository": {deploymentRunningImage("localhost:5000/app/db:latest", verified), false},
+		"invalid pull spec":  {deploymentRunningImage("localhost:5000/app/web@", verified), false},
+	}
+
+	for name, testCase := range testCases {
+		err := policy.Admit(kapi.NewDefaultContext(), testCase.deployment)
+		if testCase.admit && err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if !testCase.admit && !kerrors.IsInvalid(err) {
+			t.Errorf("%s: expected an invalid error, got %v", name, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"fmt"
+	"reflect"
 
 	"code.google.com/p/go-uuid/uuid"
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -15,17 +16,24 @@ import (
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	"github.com/openshift/origin/pkg/deploy/api/validation"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
 )
 
 // REST is an implementation of RESTStorage for the api server.
 type REST struct {
-	registry Registry
+	registry    Registry
+	canceller   Canceller
+	imagePolicy ImagePolicy
 }
 
-// NewREST creates a new REST backed by the given registry.
-func NewREST(registry Registry) apiserver.RESTStorage {
+// NewREST creates a new REST backed by the given registry. The canceller is used to stop the work of
+// deployments which are updated to DeploymentStatusCancelled. The image policy, if not nil, must
+// admit the images of new deployments and of updates which change the controller template.
+func NewREST(registry Registry, canceller Canceller, imagePolicy ImagePolicy) apiserver.RESTStorage {
 	return &REST{
-		registry: registry,
+		registry:    registry,
+		canceller:   canceller,
+		imagePolicy: imagePolicy,
 	}
 }
 
@@ -82,6 +90,11 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	if errs := validation.ValidateDeployment(deployment); len(errs) > 0 {
 		return nil, kerrors.NewInvalid("deployment", deployment.ID, errs)
 	}
+	if s.imagePolicy != nil {
+		if err := s.imagePolicy.Admit(ctx, deployment); err != nil {
+			return nil, err
+		}
+	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		err := s.registry.CreateDeployment(ctx, deployment)
@@ -105,6 +118,21 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 		return nil, kerrors.NewConflict("deployment", deployment.Namespace, fmt.Errorf("Deployment.Namespace does not match the provided context"))
 	}
 
+	if deployment.Status == deployapi.DeploymentStatusCancelled || s.imagePolicy != nil {
+		current, err := s.registry.GetDeployment(ctx, deployment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if deployment.Status == deployapi.DeploymentStatusCancelled && current.Status != deployapi.DeploymentStatusCancelled {
+			return s.cancel(ctx, current)
+		}
+		if s.imagePolicy != nil && !reflect.DeepEqual(current.ControllerTemplate, deployment.ControllerTemplate) {
+			if err := s.imagePolicy.Admit(ctx, deployment); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
 		err := s.registry.UpdateDeployment(ctx, deployment)
 		if err != nil {
@@ -114,6 +142,32 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	}), nil
 }
 
+// cancel stops an unfinished Deployment and moves it to DeploymentStatusCancelled. Any other
+// changes made by the client are discarded.
+func (s *REST) cancel(ctx kapi.Context, deployment *deployapi.Deployment) (<-chan runtime.Object, error) {
+	if deployutil.IsTerminatedDeployment(deployment) {
+		return nil, kerrors.NewConflict("deployment", deployment.ID, fmt.Errorf("deployment has already finished with status %s", deployment.Status))
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		message := "Deployment cancelled"
+		if s.canceller != nil {
+			var err error
+			if message, err = s.canceller.Cancel(ctx, deployment); err != nil {
+				return nil, err
+			}
+		}
+
+		glog.Infof("Cancelling deployment with namespace::ID: %v::%v", deployment.Namespace, deployment.ID)
+		deployutil.RecordDeploymentEvent(deployment, deployapi.DeploymentEventCancelled, "%s", message)
+		deployutil.SetDeploymentStatus(deployment, deployapi.DeploymentStatusCancelled, message)
+		if err := s.registry.UpdateDeployment(ctx, deployment); err != nil {
+			return nil, err
+		}
+		return deployment, nil
+	}), nil
+}
+
 // Watch begins watching for new, changed, or deleted Deployments.
 func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
 	return s.registry.WatchDeployments(ctx, resourceVersion, func(deployment *deployapi.Deployment) bool {
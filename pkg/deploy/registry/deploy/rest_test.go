@@ -8,6 +8,7 @@ import (
 	"time"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/openshift/origin/pkg/deploy/api"
@@ -159,6 +160,22 @@ func TestCreateDeploymentOk(t *testing.T) {
 	}
 }
 
+func TestCreateDeploymentRefusedByImagePolicy(t *testing.T) {
+	mockRegistry := test.NewDeploymentRegistry()
+	storage := REST{
+		registry:    mockRegistry,
+		imagePolicy: &SignedImagePolicy{Images: fakeImages{}},
+	}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), deploymentRunning(api.DeploymentImage{ContainerName: "web", ImageID: "image1"}))
+	if !kerrors.IsInvalid(err) {
+		t.Errorf("Expected an invalid error, got %v", err)
+	}
+	if mockRegistry.Deployment != nil {
+		t.Errorf("Unexpected deployment created: %#v", mockRegistry.Deployment)
+	}
+}
+
 func TestGetDeploymentError(t *testing.T) {
 	mockRegistry := test.NewDeploymentRegistry()
 	mockRegistry.Err = fmt.Errorf("bad")
@@ -257,6 +274,104 @@ func TestUpdateDeploymentOk(t *testing.T) {
 	}
 }
 
+func TestUpdateDeploymentRefusedByImagePolicy(t *testing.T) {
+	mockRegistry := test.NewDeploymentRegistry()
+	mockRegistry.Deployment = deploymentRunning(api.DeploymentImage{ContainerName: "web", ImageID: "image1"})
+	storage := REST{registry: mockRegistry, imagePolicy: testSignedImagePolicy()}
+
+	deployment := deploymentRunningImage("localhost:5000/app/web@image4", api.DeploymentImage{ContainerName: "web", ImageID: "image1"})
+	_, err := storage.Update(kapi.NewDefaultContext(), deployment)
+	if !kerrors.IsInvalid(err) {
+		t.Errorf("Expected an invalid error, got %v", err)
+	}
+	if mockRegistry.Deployment.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers[0].Image != "localhost:5000/app/web@image1" {
+		t.Errorf("Unexpected deployment updated: %#v", mockRegistry.Deployment)
+	}
+}
+
+func TestUpdateDeploymentUnchangedTemplateNotAdmitted(t *testing.T) {
+	mockRegistry := test.NewDeploymentRegistry()
+	mockRegistry.Deployment = deploymentRunningImage("localhost:5000/app/web@image4")
+	storage := REST{registry: mockRegistry, imagePolicy: testSignedImagePolicy()}
+
+	deployment := deploymentRunningImage("localhost:5000/app/web@image4")
+	deployment.Status = api.DeploymentStatusComplete
+	channel, err := storage.Update(kapi.NewDefaultContext(), deployment)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := (<-channel).(*api.Deployment); !ok {
+		t.Errorf("Expected the deployment to be updated")
+	}
+}
+
+type testCanceller struct {
+	Cancelled *api.Deployment
+}
+
+func (c *testCanceller) Cancel(ctx kapi.Context, deployment *api.Deployment) (string, error) {
+	c.Cancelled = deployment
+	return "cancelled", nil
+}
+
+func TestUpdateDeploymentCancel(t *testing.T) {
+	mockRegistry := test.NewDeploymentRegistry()
+	mockRegistry.Deployment = &api.Deployment{
+		TypeMeta: kapi.TypeMeta{ID: "bar"},
+		Status:   api.DeploymentStatusRunning,
+	}
+	canceller := &testCanceller{}
+	storage := REST{registry: mockRegistry, canceller: canceller}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Deployment{
+		TypeMeta: kapi.TypeMeta{ID: "bar"},
+		Status:   api.DeploymentStatusCancelled,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	result := <-channel
+	deployment, ok := result.(*api.Deployment)
+	if !ok {
+		t.Fatalf("Expected Deployment, got %#v", result)
+	}
+	if canceller.Cancelled == nil || canceller.Cancelled.ID != "bar" {
+		t.Errorf("Expected deployment to be cancelled, got %#v", canceller.Cancelled)
+	}
+	if e, a := api.DeploymentStatusCancelled, deployment.Status; e != a {
+		t.Errorf("Expected status %s, got %s", e, a)
+	}
+	if deployment.CompletionTimestamp.IsZero() {
+		t.Errorf("Expected a completion timestamp")
+	}
+	if e, a := api.DeploymentEventCancelled, deployment.Events[len(deployment.Events)-1].Type; e != a {
+		t.Errorf("Expected event %s, got %s", e, a)
+	}
+}
+
+func TestUpdateDeploymentCancelFinished(t *testing.T) {
+	mockRegistry := test.NewDeploymentRegistry()
+	mockRegistry.Deployment = &api.Deployment{
+		TypeMeta: kapi.TypeMeta{ID: "bar"},
+		Status:   api.DeploymentStatusComplete,
+	}
+	storage := REST{registry: mockRegistry, canceller: &testCanceller{}}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.Deployment{
+		TypeMeta: kapi.TypeMeta{ID: "bar"},
+		Status:   api.DeploymentStatusCancelled,
+	})
+	if channel != nil {
+		t.Errorf("Expected a nil channel, got %v", channel)
+	}
+	if err == nil {
+		t.Fatalf("Expected an error cancelling a finished deployment")
+	}
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != http.StatusConflict {
+		t.Errorf("Expected a conflict error, got %#v", err)
+	}
+}
+
 func TestDeleteDeployment(t *testing.T) {
 	mockRegistry := test.NewDeploymentRegistry()
 	storage := REST{registry: mockRegistry}
@@ -0,0 +1,107 @@
+package deployconfig
+
+import (
+	"errors"
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// ApproveREST is a RESTStorage implementation which approves the pending image updates of a
+// DeploymentConfig. It supports only the Create operation.
+type ApproveREST struct {
+	registry  Registry
+	generator configGenerator
+}
+
+type configGenerator interface {
+	GenerateApproved(ctx kapi.Context, id string, approved []deployapi.PendingImageUpdate) (*deployapi.DeploymentConfig, error)
+}
+
+// NewApproveREST creates a new ApproveREST which regenerates DeploymentConfigs with the given
+// generator and saves them to the registry.
+func NewApproveREST(registry Registry, generator configGenerator) apiserver.RESTStorage {
+	return &ApproveREST{
+		registry:  registry,
+		generator: generator,
+	}
+}
+
+// New creates a new DeploymentConfigApproval for use with Create.
+func (s *ApproveREST) New() runtime.Object {
+	return &deployapi.DeploymentConfigApproval{}
+}
+
+func (s *ApproveREST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ApproveREST.List() is not implemented.")
+}
+
+func (s *ApproveREST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ApproveREST.Get() is not implemented.")
+}
+
+func (s *ApproveREST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ApproveREST.Delete() is not implemented.")
+}
+
+func (s *ApproveREST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ApproveREST.Update() is not implemented.")
+}
+
+// Create regenerates the DeploymentConfig with the ID of the approval, rolling out the approved images
+// of its pending image updates, and saves it, recording the approved image updates as the cause of
+// the new deployment. The updated DeploymentConfig is returned.
+func (s *ApproveREST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	approval, ok := obj.(*deployapi.DeploymentConfigApproval)
+	if !ok {
+		return nil, fmt.Errorf("not a deploymentConfigApproval: %#v", obj)
+	}
+	if len(approval.ID) == 0 {
+		return nil, fmt.Errorf("id is unspecified: %#v", approval)
+	}
+
+	config, err := s.registry.GetDeploymentConfig(ctx, approval.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.PendingImageUpdates) == 0 {
+		return nil, kerrors.NewConflict("deploymentConfig", config.ID, fmt.Errorf("there are no pending image updates to approve"))
+	}
+
+	approved := approval.Updates
+	if len(approved) == 0 {
+		approved = config.PendingImageUpdates
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		newConfig, err := s.generator.GenerateApproved(ctx, config.ID, approved)
+		if err != nil {
+			return nil, err
+		}
+
+		causes := []*deployapi.DeploymentCause{}
+		for _, update := range approved {
+			causes = append(causes, &deployapi.DeploymentCause{
+				Type: deployapi.DeploymentTriggerOnImageChange,
+				ImageTrigger: &deployapi.DeploymentCauseImageTrigger{
+					RepositoryName: update.RepositoryName,
+					Tag:            update.Tag,
+				},
+			})
+		}
+		newConfig.Details = &deployapi.DeploymentDetails{Causes: causes}
+
+		glog.V(2).Infof("Approving %d pending image updates for deploymentConfig %s", len(approved), config.ID)
+		if err := s.registry.UpdateDeploymentConfig(ctx, newConfig); err != nil {
+			return nil, err
+		}
+		return newConfig, nil
+	}), nil
+}
@@ -0,0 +1,140 @@
+package deployconfig
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+type testGenerator struct {
+	GenerateApprovedFunc func(id string, approved []api.PendingImageUpdate) (*api.DeploymentConfig, error)
+}
+
+func (g *testGenerator) GenerateApproved(ctx kapi.Context, id string, approved []api.PendingImageUpdate) (*api.DeploymentConfig, error) {
+	return g.GenerateApprovedFunc(id, approved)
+}
+
+func TestApproveDeploymentConfig(t *testing.T) {
+	mockRegistry := test.NewDeploymentConfigRegistry()
+	mockRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "foo"},
+		LatestVersion: 1,
+		PendingImageUpdates: []api.PendingImageUpdate{
+			{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref2"},
+		},
+	}
+	storage := ApproveREST{
+		registry: mockRegistry,
+		generator: &testGenerator{
+			GenerateApprovedFunc: func(id string, approved []api.PendingImageUpdate) (*api.DeploymentConfig, error) {
+				if len(approved) != 1 || approved[0].ImageID != "ref2" {
+					t.Errorf("Expected the pending image update to be approved, got %#v", approved)
+				}
+				return &api.DeploymentConfig{TypeMeta: kapi.TypeMeta{ID: id}, LatestVersion: 2}, nil
+			},
+		},
+	}
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentConfigApproval{TypeMeta: kapi.TypeMeta{ID: "foo"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case result := <-channel:
+		config, ok := result.(*api.DeploymentConfig)
+		if !ok {
+			t.Fatalf("Expected deploymentConfig type, got: %#v", result)
+		}
+		if config.LatestVersion != 2 {
+			t.Errorf("Expected LatestVersion=2, got %d", config.LatestVersion)
+		}
+		if config.Details == nil || len(config.Details.Causes) != 1 {
+			t.Fatalf("Expected a single cause, got %#v", config.Details)
+		}
+		cause := config.Details.Causes[0]
+		if cause.Type != api.DeploymentTriggerOnImageChange || cause.ImageTrigger.RepositoryName != "registry:8080/repo1" || cause.ImageTrigger.Tag != "tag1" {
+			t.Errorf("Unexpected cause: %#v", cause)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+
+	if mockRegistry.DeploymentConfig.LatestVersion != 2 {
+		t.Errorf("Expected the regenerated config to be saved, got %#v", mockRegistry.DeploymentConfig)
+	}
+}
+
+func TestApproveDeploymentConfigNothingPending(t *testing.T) {
+	mockRegistry := test.NewDeploymentConfigRegistry()
+	mockRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "foo"},
+		LatestVersion: 1,
+	}
+	storage := ApproveREST{
+		registry: mockRegistry,
+		generator: &testGenerator{
+			GenerateApprovedFunc: func(id string, approved []api.PendingImageUpdate) (*api.DeploymentConfig, error) {
+				t.Fatalf("Unexpected generator call")
+				return nil, nil
+			},
+		},
+	}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentConfigApproval{TypeMeta: kapi.TypeMeta{ID: "foo"}})
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != http.StatusConflict {
+		t.Errorf("Expected a conflict error, got %#v", err)
+	}
+}
+
+func TestApproveDeploymentConfigSelectedUpdates(t *testing.T) {
+	mockRegistry := test.NewDeploymentConfigRegistry()
+	mockRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "foo"},
+		LatestVersion: 1,
+		PendingImageUpdates: []api.PendingImageUpdate{
+			{RepositoryName: "registry:8080/repo1", Tag: "tag1", ImageID: "ref2"},
+			{RepositoryName: "registry:8080/repo2", Tag: "tag1", ImageID: "ref3"},
+		},
+	}
+	selected := api.PendingImageUpdate{RepositoryName: "registry:8080/repo2", Tag: "tag1", ImageID: "ref3"}
+	storage := ApproveREST{
+		registry: mockRegistry,
+		generator: &testGenerator{
+			GenerateApprovedFunc: func(id string, approved []api.PendingImageUpdate) (*api.DeploymentConfig, error) {
+				if len(approved) != 1 || approved[0] != selected {
+					t.Errorf("Expected only the selected image update to be approved, got %#v", approved)
+				}
+				return &api.DeploymentConfig{TypeMeta: kapi.TypeMeta{ID: id}, LatestVersion: 2}, nil
+			},
+		},
+	}
+
+	approval := &api.DeploymentConfigApproval{
+		TypeMeta: kapi.TypeMeta{ID: "foo"},
+		Updates:  []api.PendingImageUpdate{selected},
+	}
+	channel, err := storage.Create(kapi.NewDefaultContext(), approval)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case result := <-channel:
+		config, ok := result.(*api.DeploymentConfig)
+		if !ok {
+			t.Fatalf("Expected deploymentConfig type, got: %#v", result)
+		}
+		if config.Details == nil || len(config.Details.Causes) != 1 || config.Details.Causes[0].ImageTrigger.RepositoryName != "registry:8080/repo2" {
+			t.Errorf("Expected the selected update as the only cause, got %#v", config.Details)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+}
@@ -0,0 +1,102 @@
+package deployconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// HistoryREST is a RESTStorage implementation which returns the deployment history of a
+// DeploymentConfig. It supports only the Get operation.
+type HistoryREST struct {
+	registry           Registry
+	deploymentRegistry deploymentLister
+}
+
+type deploymentLister interface {
+	ListDeployments(ctx kapi.Context, selector labels.Selector) (*deployapi.DeploymentList, error)
+}
+
+// NewHistoryREST creates a new HistoryREST which reads DeploymentConfigs from registry and their
+// deployments from deploymentRegistry.
+func NewHistoryREST(registry Registry, deploymentRegistry deploymentLister) apiserver.RESTStorage {
+	return &HistoryREST{
+		registry:           registry,
+		deploymentRegistry: deploymentRegistry,
+	}
+}
+
+// New creates a new DeploymentConfigHistory.
+func (s *HistoryREST) New() runtime.Object {
+	return &deployapi.DeploymentConfigHistory{}
+}
+
+func (s *HistoryREST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.HistoryREST.List() is not implemented.")
+}
+
+// Get returns the revisions of the DeploymentConfig with the given ID, newest first.
+func (s *HistoryREST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	config, err := s.registry.GetDeploymentConfig(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, _ := labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + config.ID)
+	deployments, err := s.deploymentRegistry.ListDeployments(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list deployments of deploymentConfig %s: %v", config.ID, err)
+	}
+
+	revisions := revisionsByVersion{}
+	for _, deployment := range deployments.Items {
+		version, ok := deployutil.DeploymentVersion(config, &deployment)
+		if !ok {
+			continue
+		}
+		revisions = append(revisions, deployapi.DeploymentRevision{Version: version, Deployment: deployment})
+	}
+	sort.Sort(revisions)
+
+	previous := kapi.PodTemplate{}
+	for i := range revisions {
+		template := revisions[i].Deployment.ControllerTemplate.PodTemplate
+		revisions[i].Changes = deployutil.DiffPodTemplates(previous, template)
+		previous = template
+	}
+
+	history := &deployapi.DeploymentConfigHistory{
+		TypeMeta: kapi.TypeMeta{ID: config.ID, Namespace: config.Namespace},
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		history.Revisions = append(history.Revisions, revisions[i])
+	}
+	return history, nil
+}
+
+func (s *HistoryREST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.HistoryREST.Delete() is not implemented.")
+}
+
+func (s *HistoryREST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.HistoryREST.Update() is not implemented.")
+}
+
+func (s *HistoryREST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.HistoryREST.Create() is not implemented.")
+}
+
+// revisionsByVersion sorts revisions from the oldest version to the newest.
+type revisionsByVersion []deployapi.DeploymentRevision
+
+func (r revisionsByVersion) Len() int           { return len(r) }
+func (r revisionsByVersion) Less(i, j int) bool { return r[i].Version < r[j].Version }
+func (r revisionsByVersion) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
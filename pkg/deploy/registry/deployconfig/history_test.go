@@ -0,0 +1,65 @@
+package deployconfig
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+func historyDeployment(id, image string) api.Deployment {
+	template := deploytest.OkControllerTemplate()
+	template.PodTemplate.DesiredState.Manifest.Containers = []kapi.Container{{Name: "container1", Image: image}}
+	return api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: id},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusComplete,
+		ControllerTemplate: template,
+	}
+}
+
+func TestGetDeploymentConfigHistory(t *testing.T) {
+	configRegistry := test.NewDeploymentConfigRegistry()
+	configRegistry.DeploymentConfig = &api.DeploymentConfig{TypeMeta: kapi.TypeMeta{ID: "config"}, LatestVersion: 10}
+	deploymentRegistry := test.NewDeploymentRegistry()
+	deploymentRegistry.Deployments = &api.DeploymentList{
+		Items: []api.Deployment{
+			historyDeployment("config-10", "registry:8080/repo1:ref3"),
+			historyDeployment("config-2", "registry:8080/repo1:ref2"),
+			historyDeployment("config-1", "registry:8080/repo1:ref1"),
+			historyDeployment("other-config-1", "registry:8080/repo1:ref1"),
+		},
+	}
+	storage := NewHistoryREST(configRegistry, deploymentRegistry)
+
+	obj, err := storage.Get(kapi.NewDefaultContext(), "config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	history, ok := obj.(*api.DeploymentConfigHistory)
+	if !ok {
+		t.Fatalf("Expected deploymentConfigHistory type, got: %#v", obj)
+	}
+
+	if len(history.Revisions) != 3 {
+		t.Fatalf("Expected 3 revisions, got %#v", history.Revisions)
+	}
+	for i, version := range []int{10, 2, 1} {
+		if e, a := version, history.Revisions[i].Version; e != a {
+			t.Errorf("Expected revision %d to have version %d, got %d", i, e, a)
+		}
+	}
+
+	changes := history.Revisions[0].Changes
+	if len(changes) != 1 || changes[0].Type != api.DeploymentChangeImage || changes[0].From != "registry:8080/repo1:ref2" || changes[0].To != "registry:8080/repo1:ref3" {
+		t.Errorf("Expected an image change from the previous revision, got %#v", changes)
+	}
+	for _, change := range history.Revisions[2].Changes {
+		if change.Type != api.DeploymentChangeContainerAdded && change.Type != api.DeploymentChangeEnv && change.Type != api.DeploymentChangePort {
+			t.Errorf("Expected only additions in the oldest revision, got %#v", change)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package deployconfig
+
+import (
+	"errors"
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/golang/glog"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+)
+
+// ScaleREST is a RESTStorage implementation which sets the number of replicas of a DeploymentConfig
+// and of the replication controller of its active deployment. It supports only the Create operation.
+type ScaleREST struct {
+	registry                    Registry
+	deploymentRegistry          deploymentInterface
+	replicationControllerClient scaleControllerClient
+}
+
+type deploymentInterface interface {
+	GetDeployment(ctx kapi.Context, id string) (*deployapi.Deployment, error)
+	UpdateDeployment(ctx kapi.Context, deployment *deployapi.Deployment) error
+}
+
+type scaleControllerClient interface {
+	ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error)
+	UpdateReplicationController(ctx kapi.Context, controller *kapi.ReplicationController) (*kapi.ReplicationController, error)
+}
+
+// NewScaleREST creates a new ScaleREST which updates DeploymentConfigs in registry, their deployments
+// in deploymentRegistry and their replication controllers with the given client.
+func NewScaleREST(registry Registry, deploymentRegistry deploymentInterface, replicationControllerClient scaleControllerClient) apiserver.RESTStorage {
+	return &ScaleREST{
+		registry:                    registry,
+		deploymentRegistry:          deploymentRegistry,
+		replicationControllerClient: replicationControllerClient,
+	}
+}
+
+// New creates a new DeploymentConfigScale for use with Create.
+func (s *ScaleREST) New() runtime.Object {
+	return &deployapi.DeploymentConfigScale{}
+}
+
+func (s *ScaleREST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ScaleREST.List() is not implemented.")
+}
+
+func (s *ScaleREST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ScaleREST.Get() is not implemented.")
+}
+
+func (s *ScaleREST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ScaleREST.Delete() is not implemented.")
+}
+
+func (s *ScaleREST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, errors.New("deploy/registry/deployconfig.ScaleREST.Update() is not implemented.")
+}
+
+// Create sets the replicas of the DeploymentConfig with the ID of the scale and of the replication
+// controller serving the config, recording them on the latest deployment if it completed. Since only
+// the replica count of the template changes, no new deployment is caused. Scaling is refused while a
+// deployment is in progress, as the deployment strategy would override the new replica count. The
+// updated DeploymentConfig is returned.
+func (s *ScaleREST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	scale, ok := obj.(*deployapi.DeploymentConfigScale)
+	if !ok {
+		return nil, fmt.Errorf("not a deploymentConfigScale: %#v", obj)
+	}
+	if len(scale.ID) == 0 {
+		return nil, fmt.Errorf("id is unspecified: %#v", scale)
+	}
+	if scale.Replicas < 0 {
+		return nil, kerrors.NewInvalid("deploymentConfigScale", scale.ID, kerrors.ErrorList{kerrors.NewFieldInvalid("replicas", scale.Replicas)})
+	}
+
+	config, err := s.registry.GetDeploymentConfig(ctx, scale.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployment *deployapi.Deployment
+	if config.LatestVersion > 0 {
+		if deployment, err = s.deploymentRegistry.GetDeployment(ctx, deployutil.LatestDeploymentIDForConfig(config)); err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		if deployment != nil && !deployutil.IsTerminatedDeployment(deployment) {
+			return nil, kerrors.NewConflict("deploymentConfig", config.ID, fmt.Errorf("deployment %s is %s; scale once it has finished", deployment.ID, deployment.Status))
+		}
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		if deployment != nil {
+			if err := s.scaleActiveController(ctx, config, deployment, scale.Replicas); err != nil {
+				return nil, err
+			}
+			if deployment.Status == deployapi.DeploymentStatusComplete {
+				deployment.ControllerTemplate.Replicas = scale.Replicas
+				if err := s.deploymentRegistry.UpdateDeployment(ctx, deployment); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		glog.V(2).Infof("Scaling deploymentConfig %s from %d to %d replicas", config.ID, config.Template.ControllerTemplate.Replicas, scale.Replicas)
+		config.Template.ControllerTemplate.Replicas = scale.Replicas
+		if err := s.registry.UpdateDeploymentConfig(ctx, config); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}), nil
+}
+
+// scaleActiveController sets the replicas of the replication controller serving the config: the
+// controller of its latest deployment if that completed, otherwise the controller of a previous
+// deployment which runs the most replicas.
+func (s *ScaleREST) scaleActiveController(ctx kapi.Context, config *deployapi.DeploymentConfig, deployment *deployapi.Deployment, replicas int) error {
+	selector, _ := labels.ParseSelector(deployapi.DeploymentConfigLabel + "=" + config.ID)
+	controllers, err := s.replicationControllerClient.ListReplicationControllers(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("unable to list replication controllers for deploymentConfig %s: %v", config.ID, err)
+	}
+
+	var active *kapi.ReplicationController
+	if deployment.Status == deployapi.DeploymentStatusComplete {
+		for i := range controllers.Items {
+			if controllers.Items[i].Labels[deployapi.DeploymentLabel] == deployment.ID {
+				active = &controllers.Items[i]
+				break
+			}
+		}
+	} else {
+		active = deployutil.CanaryBaselineController(deployment, controllers.Items)
+	}
+	if active == nil {
+		glog.V(2).Infof("No replication controller is serving deploymentConfig %s", config.ID)
+		return nil
+	}
+
+	active.DesiredState.Replicas = replicas
+	if _, err := s.replicationControllerClient.UpdateReplicationController(ctx, active); err != nil {
+		return fmt.Errorf("unable to scale replication controller %s: %v", active.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package deployconfig
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/openshift/origin/pkg/deploy/api"
+	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
+	"github.com/openshift/origin/pkg/deploy/registry/test"
+)
+
+type testControllerClient struct {
+	Controllers []kapi.ReplicationController
+	Updated     map[string]int
+}
+
+func (c *testControllerClient) ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+	return &kapi.ReplicationControllerList{Items: c.Controllers}, nil
+}
+
+func (c *testControllerClient) UpdateReplicationController(ctx kapi.Context, controller *kapi.ReplicationController) (*kapi.ReplicationController, error) {
+	c.Updated[controller.ID] = controller.DesiredState.Replicas
+	return controller, nil
+}
+
+func controllerFor(deploymentID string, replicas int) kapi.ReplicationController {
+	return kapi.ReplicationController{
+		TypeMeta:     kapi.TypeMeta{ID: deploymentID + "-rc"},
+		Labels:       map[string]string{api.DeploymentConfigLabel: "config", api.DeploymentLabel: deploymentID},
+		DesiredState: kapi.ReplicationControllerState{Replicas: replicas},
+	}
+}
+
+func scale(t *testing.T, storage *ScaleREST, replicas int) *api.DeploymentConfig {
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentConfigScale{
+		TypeMeta: kapi.TypeMeta{ID: "config"},
+		Replicas: replicas,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case result := <-channel:
+		config, ok := result.(*api.DeploymentConfig)
+		if !ok {
+			t.Fatalf("Expected deploymentConfig type, got: %#v", result)
+		}
+		return config
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("Timed out waiting for result")
+	}
+	return nil
+}
+
+func TestScaleDeploymentConfig(t *testing.T) {
+	configRegistry := test.NewDeploymentConfigRegistry()
+	configRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "config"},
+		LatestVersion: 2,
+		Template:      api.DeploymentTemplate{ControllerTemplate: deploytest.OkControllerTemplate()},
+	}
+	configRegistry.DeploymentConfig.Template.ControllerTemplate.Replicas = 2
+	deploymentRegistry := test.NewDeploymentRegistry()
+	deploymentRegistry.Deployment = &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-2"},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusComplete,
+		ControllerTemplate: configRegistry.DeploymentConfig.Template.ControllerTemplate,
+	}
+	rcClient := &testControllerClient{
+		Controllers: []kapi.ReplicationController{controllerFor("config-2", 2)},
+		Updated:     map[string]int{},
+	}
+	storage := &ScaleREST{registry: configRegistry, deploymentRegistry: deploymentRegistry, replicationControllerClient: rcClient}
+
+	config := scale(t, storage, 5)
+
+	if e, a := 5, config.Template.ControllerTemplate.Replicas; e != a {
+		t.Errorf("Expected the config to be scaled to %d, got %d", e, a)
+	}
+	if e, a := 2, config.LatestVersion; e != a {
+		t.Errorf("Expected LatestVersion to remain %d, got %d", e, a)
+	}
+	if e, a := 5, rcClient.Updated["config-2-rc"]; e != a {
+		t.Errorf("Expected the replication controller to be scaled to %d, got %d", e, a)
+	}
+	if e, a := 5, deploymentRegistry.Deployment.ControllerTemplate.Replicas; e != a {
+		t.Errorf("Expected the deployment to record %d replicas, got %d", e, a)
+	}
+}
+
+func TestScaleDeploymentConfigAfterFailedDeployment(t *testing.T) {
+	configRegistry := test.NewDeploymentConfigRegistry()
+	configRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "config"},
+		LatestVersion: 2,
+		Template:      api.DeploymentTemplate{ControllerTemplate: deploytest.OkControllerTemplate()},
+	}
+	configRegistry.DeploymentConfig.Template.ControllerTemplate.Replicas = 2
+	deploymentRegistry := test.NewDeploymentRegistry()
+	deploymentRegistry.Deployment = &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-2"},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusFailed,
+		ControllerTemplate: configRegistry.DeploymentConfig.Template.ControllerTemplate,
+	}
+	rcClient := &testControllerClient{
+		Controllers: []kapi.ReplicationController{controllerFor("config-1", 2), controllerFor("config-2", 0)},
+		Updated:     map[string]int{},
+	}
+	storage := &ScaleREST{registry: configRegistry, deploymentRegistry: deploymentRegistry, replicationControllerClient: rcClient}
+
+	scale(t, storage, 5)
+
+	if e, a := 5, rcClient.Updated["config-1-rc"]; e != a {
+		t.Errorf("Expected the previous replication controller to be scaled to %d, got %d", e, a)
+	}
+	if _, updated := rcClient.Updated["config-2-rc"]; updated {
+		t.Errorf("Unexpected update of the failed deployment's replication controller")
+	}
+	if e, a := 5, configRegistry.DeploymentConfig.Template.ControllerTemplate.Replicas; e != a {
+		t.Errorf("Expected the config to be scaled to %d, got %d", e, a)
+	}
+}
+
+func TestScaleDeploymentConfigDeploymentInProgress(t *testing.T) {
+	configRegistry := test.NewDeploymentConfigRegistry()
+	configRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "config"},
+		LatestVersion: 2,
+		Template:      api.DeploymentTemplate{ControllerTemplate: deploytest.OkControllerTemplate()},
+	}
+	configRegistry.DeploymentConfig.Template.ControllerTemplate.Replicas = 2
+	deploymentRegistry := test.NewDeploymentRegistry()
+	deploymentRegistry.Deployment = &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-2"},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusRunning,
+		ControllerTemplate: configRegistry.DeploymentConfig.Template.ControllerTemplate,
+	}
+	storage := &ScaleREST{registry: configRegistry, deploymentRegistry: deploymentRegistry, replicationControllerClient: &testControllerClient{Updated: map[string]int{}}}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentConfigScale{
+		TypeMeta: kapi.TypeMeta{ID: "config"},
+		Replicas: 5,
+	})
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != http.StatusConflict {
+		t.Errorf("Expected a conflict error, got %#v", err)
+	}
+}
+
+func TestScaleDeploymentConfigNegativeReplicas(t *testing.T) {
+	configRegistry := test.NewDeploymentConfigRegistry()
+	configRegistry.DeploymentConfig = &api.DeploymentConfig{
+		TypeMeta:      kapi.TypeMeta{ID: "config"},
+		LatestVersion: 2,
+		Template:      api.DeploymentTemplate{ControllerTemplate: deploytest.OkControllerTemplate()},
+	}
+	configRegistry.DeploymentConfig.Template.ControllerTemplate.Replicas = 2
+	deploymentRegistry := test.NewDeploymentRegistry()
+	deploymentRegistry.Deployment = &api.Deployment{
+		TypeMeta:           kapi.TypeMeta{ID: "config-2"},
+		Labels:             map[string]string{api.DeploymentConfigLabel: "config"},
+		Status:             api.DeploymentStatusComplete,
+		ControllerTemplate: configRegistry.DeploymentConfig.Template.ControllerTemplate,
+	}
+	storage := &ScaleREST{registry: configRegistry, deploymentRegistry: deploymentRegistry, replicationControllerClient: &testControllerClient{Updated: map[string]int{}}}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.DeploymentConfigScale{
+		TypeMeta: kapi.TypeMeta{ID: "config"},
+		Replicas: -1,
+	})
+	if e, ok := err.(kclient.APIStatus); !ok || e.Status().Code != 422 {
+		t.Errorf("Expected an invalid error, got %#v", err)
+	}
+}
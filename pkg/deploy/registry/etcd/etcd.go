@@ -20,9 +20,11 @@ const (
 	DeploymentPath string = "/deployments"
 	// DeploymentConfigPath is the path to deploymentConfig resources in etcd
 	DeploymentConfigPath string = "/deploymentConfigs"
+	// DeploymentConfigAutoscalerPath is the path to deploymentConfigAutoscaler resources in etcd
+	DeploymentConfigAutoscalerPath string = "/deploymentConfigAutoscalers"
 )
 
-// Etcd implements deployment.Registry and deploymentconfig.Registry interfaces.
+// Etcd implements deployment.Registry, deploymentconfig.Registry and autoscaler.Registry interfaces.
 type Etcd struct {
 	tools.EtcdHelper
 }
@@ -227,3 +229,94 @@ func (r *Etcd) DeleteDeploymentConfig(ctx kapi.Context, id string) error {
 	err = r.Delete(key, false)
 	return etcderr.InterpretDeleteError(err, "deploymentConfig", id)
 }
+
+// ListDeploymentConfigAutoscalers obtains a list of DeploymentConfigAutoscalers.
+func (r *Etcd) ListDeploymentConfigAutoscalers(ctx kapi.Context, selector labels.Selector) (*api.DeploymentConfigAutoscalerList, error) {
+	autoscalers := api.DeploymentConfigAutoscalerList{}
+	err := r.ExtractToList(makeDeploymentConfigAutoscalerListKey(ctx), &autoscalers)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []api.DeploymentConfigAutoscaler{}
+	for _, item := range autoscalers.Items {
+		if selector.Matches(labels.Set(item.Labels)) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	autoscalers.Items = filtered
+	return &autoscalers, err
+}
+
+// WatchDeploymentConfigAutoscalers begins watching for new, changed, or deleted DeploymentConfigAutoscalers.
+func (r *Etcd) WatchDeploymentConfigAutoscalers(ctx kapi.Context, resourceVersion string, filter func(autoscaler *api.DeploymentConfigAutoscaler) bool) (watch.Interface, error) {
+	version, err := parseWatchResourceVersion(resourceVersion, "deploymentConfigAutoscaler")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WatchList(makeDeploymentConfigAutoscalerListKey(ctx), version, func(obj runtime.Object) bool {
+		autoscaler, ok := obj.(*api.DeploymentConfigAutoscaler)
+		if !ok {
+			glog.Errorf("Unexpected object during deploymentConfigAutoscaler watch: %#v", obj)
+			return false
+		}
+		return filter(autoscaler)
+	})
+}
+
+func makeDeploymentConfigAutoscalerListKey(ctx kapi.Context) string {
+	return kubeetcd.MakeEtcdListKey(ctx, DeploymentConfigAutoscalerPath)
+}
+
+func makeDeploymentConfigAutoscalerKey(ctx kapi.Context, id string) (string, error) {
+	return kubeetcd.MakeEtcdItemKey(ctx, DeploymentConfigAutoscalerPath, id)
+}
+
+// GetDeploymentConfigAutoscaler gets a specific DeploymentConfigAutoscaler specified by its ID.
+func (r *Etcd) GetDeploymentConfigAutoscaler(ctx kapi.Context, id string) (*api.DeploymentConfigAutoscaler, error) {
+	var autoscaler api.DeploymentConfigAutoscaler
+	key, err := makeDeploymentConfigAutoscalerKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.ExtractObj(key, &autoscaler, false)
+	if err != nil {
+		return nil, etcderr.InterpretGetError(err, "deploymentConfigAutoscaler", id)
+	}
+	return &autoscaler, nil
+}
+
+// CreateDeploymentConfigAutoscaler creates a new DeploymentConfigAutoscaler.
+func (r *Etcd) CreateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *api.DeploymentConfigAutoscaler) error {
+	key, err := makeDeploymentConfigAutoscalerKey(ctx, autoscaler.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.CreateObj(key, autoscaler, 0)
+	return etcderr.InterpretCreateError(err, "deploymentConfigAutoscaler", autoscaler.ID)
+}
+
+// UpdateDeploymentConfigAutoscaler replaces an existing DeploymentConfigAutoscaler.
+func (r *Etcd) UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *api.DeploymentConfigAutoscaler) error {
+	key, err := makeDeploymentConfigAutoscalerKey(ctx, autoscaler.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.SetObj(key, autoscaler)
+	return etcderr.InterpretUpdateError(err, "deploymentConfigAutoscaler", autoscaler.ID)
+}
+
+// DeleteDeploymentConfigAutoscaler deletes a DeploymentConfigAutoscaler specified by its ID.
+func (r *Etcd) DeleteDeploymentConfigAutoscaler(ctx kapi.Context, id string) error {
+	key, err := makeDeploymentConfigAutoscalerKey(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.Delete(key, false)
+	return etcderr.InterpretDeleteError(err, "deploymentConfigAutoscaler", id)
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"sync"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/openshift/origin/pkg/deploy/api"
+)
+
+type DeploymentConfigAutoscalerRegistry struct {
+	Err         error
+	Autoscaler  *api.DeploymentConfigAutoscaler
+	Autoscalers *api.DeploymentConfigAutoscalerList
+	sync.Mutex
+}
+
+func NewDeploymentConfigAutoscalerRegistry() *DeploymentConfigAutoscalerRegistry {
+	return &DeploymentConfigAutoscalerRegistry{}
+}
+
+func (r *DeploymentConfigAutoscalerRegistry) ListDeploymentConfigAutoscalers(ctx kapi.Context, selector labels.Selector) (*api.DeploymentConfigAutoscalerList, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Autoscalers, r.Err
+}
+
+func (r *DeploymentConfigAutoscalerRegistry) GetDeploymentConfigAutoscaler(ctx kapi.Context, id string) (*api.DeploymentConfigAutoscaler, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Autoscaler, r.Err
+}
+
+func (r *DeploymentConfigAutoscalerRegistry) CreateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *api.DeploymentConfigAutoscaler) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.Autoscaler = autoscaler
+	return r.Err
+}
+
+func (r *DeploymentConfigAutoscalerRegistry) UpdateDeploymentConfigAutoscaler(ctx kapi.Context, autoscaler *api.DeploymentConfigAutoscaler) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.Autoscaler = autoscaler
+	return r.Err
+}
+
+func (r *DeploymentConfigAutoscalerRegistry) DeleteDeploymentConfigAutoscaler(ctx kapi.Context, id string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Err
+}
+
+func (r *DeploymentConfigAutoscalerRegistry) WatchDeploymentConfigAutoscalers(ctx kapi.Context, resourceVersion string, filter func(autoscaler *api.DeploymentConfigAutoscaler) bool) (watch.Interface, error) {
+	return nil, r.Err
+}
@@ -10,12 +10,27 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
 )
 
 func LatestDeploymentIDForConfig(config *deployapi.DeploymentConfig) string {
 	return config.ID + "-" + strconv.Itoa(config.LatestVersion)
 }
 
+// DeploymentVersion returns the version of the config a deployment was created from, which is encoded
+// in the deployment's ID. It returns false if the deployment wasn't created from the config.
+func DeploymentVersion(config *deployapi.DeploymentConfig, deployment *deployapi.Deployment) (int, bool) {
+	prefix := config.ID + "-"
+	if !strings.HasPrefix(deployment.ID, prefix) {
+		return 0, false
+	}
+	version, err := strconv.Atoi(deployment.ID[len(prefix):])
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
 func ParamsForImageChangeTrigger(config *deployapi.DeploymentConfig, repoName string) *deployapi.DeploymentTriggerImageChangeParams {
 	if config == nil || config.Triggers == nil {
 		return nil
@@ -63,9 +78,147 @@ func ReferencedImages(deployment *deployapi.Deployment) map[string]string {
 	return result
 }
 
+// ParseContainerImage splits a Docker pull spec into its repository and its tag, digest or image ID.
+// The second value is empty if the pull spec names none of them. A pull spec which can't be parsed
+// is returned as the repository.
 func ParseContainerImage(image string) (string, string) {
-	tokens := strings.Split(image, ":")
-	return tokens[0], tokens[1]
+	ref, err := imageapi.ParseDockerImageReference(image)
+	if err != nil {
+		return image, ""
+	}
+	if len(ref.ID) > 0 {
+		return ref.RepositoryName(), ref.ID
+	}
+	return ref.RepositoryName(), ref.Tag
+}
+
+// ImageChangeTriggerResolved returns true if the containers of the trigger already run the image with
+// the given ID. Triggers which have never been resolved by ID fall back to the tag, digest or image ID
+// in the pull specs of their containers.
+func ImageChangeTriggerResolved(config *deployapi.DeploymentConfig, params *deployapi.DeploymentTriggerImageChangeParams, imageID string) bool {
+	if len(params.LastTriggeredImageID) > 0 {
+		return params.LastTriggeredImageID == imageID
+	}
+
+	names := util.NewStringSet(params.ContainerNames...)
+	for _, container := range config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers {
+		if !names.Has(container.Name) {
+			continue
+		}
+		if _, ref := ParseContainerImage(container.Image); ref != imageID {
+			return false
+		}
+	}
+	return true
+}
+
+// DeploymentImagesForConfig returns the images given to the containers of the config's pod template by
+// its resolved image change triggers.
+func DeploymentImagesForConfig(config *deployapi.DeploymentConfig) []deployapi.DeploymentImage {
+	images := []deployapi.DeploymentImage{}
+	for _, trigger := range config.Triggers {
+		if trigger.Type != deployapi.DeploymentTriggerOnImageChange || len(trigger.ImageChangeParams.LastTriggeredImageID) == 0 {
+			continue
+		}
+		names := util.NewStringSet(trigger.ImageChangeParams.ContainerNames...)
+		for _, container := range config.Template.ControllerTemplate.PodTemplate.DesiredState.Manifest.Containers {
+			if !names.Has(container.Name) {
+				continue
+			}
+			images = append(images, deployapi.DeploymentImage{
+				ContainerName:        container.Name,
+				ImageID:              trigger.ImageChangeParams.LastTriggeredImageID,
+				DockerImageReference: container.Image,
+			})
+		}
+	}
+	return images
+}
+
+// RecordDeploymentEvent appends a DeploymentEvent of the given type to the deployment's event list.
+func RecordDeploymentEvent(deployment *deployapi.Deployment, eventType deployapi.DeploymentEventType, format string, args ...interface{}) {
+	deployment.Events = append(deployment.Events, deployapi.DeploymentEvent{
+		Type:      eventType,
+		Message:   fmt.Sprintf(format, args...),
+		Timestamp: util.Now(),
+	})
+}
+
+// IsTerminatedDeployment returns true if the deployment has reached a status from which it will not
+// transition any further.
+func IsTerminatedDeployment(deployment *deployapi.Deployment) bool {
+	switch deployment.Status {
+	case deployapi.DeploymentStatusComplete, deployapi.DeploymentStatusFailed, deployapi.DeploymentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// DeployerPodIDForDeployment returns the ID of the pod which carries out a CustomPod deployment.
+func DeployerPodIDForDeployment(deployment *deployapi.Deployment) string {
+	return "deploy-" + deployment.ID
+}
+
+// ReplicationControllerForDeployment returns the replication controller which runs the pods
+// described by the deployment. The controller and its pod template are labeled with the ID of the
+// deployment and of the DeploymentConfig it belongs to.
+func ReplicationControllerForDeployment(deployment *deployapi.Deployment) *api.ReplicationController {
+	configID := deployment.Labels[deployapi.DeploymentConfigLabel]
+	controller := &api.ReplicationController{
+		DesiredState: deployment.ControllerTemplate,
+		Labels:       map[string]string{deployapi.DeploymentConfigLabel: configID, deployapi.DeploymentLabel: deployment.ID},
+	}
+
+	podLabels := make(map[string]string)
+	for k, v := range controller.DesiredState.PodTemplate.Labels {
+		podLabels[k] = v
+	}
+	podLabels[deployapi.DeploymentConfigLabel] = configID
+	podLabels[deployapi.DeploymentLabel] = deployment.ID
+	controller.DesiredState.PodTemplate.Labels = podLabels
+
+	return controller
+}
+
+// SetDeploymentStatus transitions the deployment to the given status, maintaining the start and
+// completion timestamps. A non-empty message replaces the deployment's StatusMessage.
+func SetDeploymentStatus(deployment *deployapi.Deployment, status deployapi.DeploymentStatus, message string) {
+	if deployment.Status == deployapi.DeploymentStatusNew && status != deployapi.DeploymentStatusNew && deployment.StartTimestamp.IsZero() {
+		deployment.StartTimestamp = util.Now()
+	}
+	deployment.Status = status
+	if len(message) > 0 {
+		deployment.StatusMessage = message
+	}
+	if IsTerminatedDeployment(deployment) && deployment.CompletionTimestamp.IsZero() {
+		deployment.CompletionTimestamp = util.Now()
+	}
+}
+
+// CanaryReplicas returns the number of replicas the replication controller of a canary deployment runs
+// while the deployment awaits promotion: the canary percentage of the deployment's replicas, rounded up.
+func CanaryReplicas(deployment *deployapi.Deployment) int {
+	if deployment.Strategy.Canary == nil {
+		return deployment.ControllerTemplate.Replicas
+	}
+	return (deployment.ControllerTemplate.Replicas*deployment.Strategy.Canary.Percentage + 99) / 100
+}
+
+// CanaryBaselineController returns the replication controller a canary deployment runs alongside: the
+// controller not belonging to the deployment which runs the most replicas. It returns nil if there is
+// no such controller.
+func CanaryBaselineController(deployment *deployapi.Deployment, controllers []api.ReplicationController) *api.ReplicationController {
+	var baseline *api.ReplicationController
+	for i := range controllers {
+		rc := &controllers[i]
+		if rc.Labels[deployapi.DeploymentLabel] == deployment.ID {
+			continue
+		}
+		if baseline == nil || rc.DesiredState.Replicas > baseline.DesiredState.Replicas {
+			baseline = rc
+		}
+	}
+	return baseline
 }
 
 func HashPodTemplate(t api.PodState) uint64 {
@@ -77,3 +230,127 @@ func HashPodTemplate(t api.PodState) uint64 {
 func PodTemplatesEqual(a, b api.PodTemplate) bool {
 	return HashPodTemplate(a.DesiredState) == HashPodTemplate(b.DesiredState)
 }
+
+// DiffPodTemplates returns the container, image, environment and port changes which replacing the
+// pod template from with the pod template to would make. Containers are matched by name.
+func DiffPodTemplates(from, to api.PodTemplate) []deployapi.DeploymentChange {
+	changes := []deployapi.DeploymentChange{}
+
+	fromContainers := from.DesiredState.Manifest.Containers
+	toContainers := to.DesiredState.Manifest.Containers
+
+	for _, toContainer := range toContainers {
+		fromContainer, found := containerByName(fromContainers, toContainer.Name)
+		if !found {
+			changes = append(changes, deployapi.DeploymentChange{
+				Type:      deployapi.DeploymentChangeContainerAdded,
+				Container: toContainer.Name,
+				To:        toContainer.Image,
+			})
+			fromContainer = &api.Container{}
+		} else if fromContainer.Image != toContainer.Image {
+			changes = append(changes, deployapi.DeploymentChange{
+				Type:      deployapi.DeploymentChangeImage,
+				Container: toContainer.Name,
+				From:      fromContainer.Image,
+				To:        toContainer.Image,
+			})
+		}
+
+		changes = append(changes, diffValues(deployapi.DeploymentChangeEnv, toContainer.Name, envValues(fromContainer.Env), envValues(toContainer.Env))...)
+		changes = append(changes, diffValues(deployapi.DeploymentChangePort, toContainer.Name, portValues(fromContainer.Ports), portValues(toContainer.Ports))...)
+	}
+
+	for _, fromContainer := range fromContainers {
+		if _, found := containerByName(toContainers, fromContainer.Name); !found {
+			changes = append(changes, deployapi.DeploymentChange{
+				Type:      deployapi.DeploymentChangeContainerRemoved,
+				Container: fromContainer.Name,
+				From:      fromContainer.Image,
+			})
+		}
+	}
+
+	return changes
+}
+
+func containerByName(containers []api.Container, name string) (*api.Container, bool) {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i], true
+		}
+	}
+	return nil, false
+}
+
+// namedValues is an ordered list of names and the value associated with each name.
+type namedValues struct {
+	names  []string
+	values map[string]string
+}
+
+func (v *namedValues) add(name, value string) {
+	if v.values == nil {
+		v.values = map[string]string{}
+	}
+	if _, exists := v.values[name]; !exists {
+		v.names = append(v.names, name)
+	}
+	v.values[name] = value
+}
+
+func envValues(env []api.EnvVar) namedValues {
+	values := namedValues{}
+	for _, e := range env {
+		values.add(e.Name, e.Value)
+	}
+	return values
+}
+
+// portValues identifies ports by name, or by container port and protocol if the port is unnamed.
+func portValues(ports []api.Port) namedValues {
+	values := namedValues{}
+	for _, p := range ports {
+		protocol := p.Protocol
+		if len(protocol) == 0 {
+			protocol = api.ProtocolTCP
+		}
+		value := fmt.Sprintf("%d/%s", p.ContainerPort, protocol)
+		if p.HostPort != 0 {
+			value = fmt.Sprintf("%s->%d", value, p.HostPort)
+		}
+		name := p.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("%d/%s", p.ContainerPort, protocol)
+		}
+		values.add(name, value)
+	}
+	return values
+}
+
+func diffValues(changeType deployapi.DeploymentChangeType, container string, from, to namedValues) []deployapi.DeploymentChange {
+	changes := []deployapi.DeploymentChange{}
+	for _, name := range to.names {
+		fromValue, exists := from.values[name]
+		if toValue := to.values[name]; !exists || fromValue != toValue {
+			changes = append(changes, deployapi.DeploymentChange{
+				Type:      changeType,
+				Container: container,
+				Name:      name,
+				From:      fromValue,
+				To:        toValue,
+			})
+		}
+	}
+	for _, name := range from.names {
+		if _, exists := to.values[name]; !exists {
+			changes = append(changes, deployapi.DeploymentChange{
+				Type:      changeType,
+				Container: container,
+				Name:      name,
+				From:      from.values[name],
+			})
+		}
+	}
+	return changes
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
 	deploytest "github.com/openshift/origin/pkg/deploy/api/test"
 )
 
@@ -73,3 +74,79 @@ func TestPodTemplatesEqualAdditionalContainerInManifest(t *testing.T) {
 		t.Fatalf("Unexpected true result for PodTemplatesEqual")
 	}
 }
+
+func TestDiffPodTemplatesNoChange(t *testing.T) {
+	if changes := DiffPodTemplates(podTemplateA(), podTemplateB()); len(changes) != 0 {
+		t.Fatalf("Unexpected changes: %#v", changes)
+	}
+}
+
+func TestDiffPodTemplates(t *testing.T) {
+	from := kapi.PodTemplate{}
+	from.DesiredState.Manifest.Containers = []kapi.Container{
+		{
+			Name:  "container1",
+			Image: "registry:8080/repo1:ref1",
+			Env:   []kapi.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+			Ports: []kapi.Port{{ContainerPort: 8080}},
+		},
+		{
+			Name:  "container2",
+			Image: "registry:8080/repo2:ref1",
+		},
+	}
+	to := kapi.PodTemplate{}
+	to.DesiredState.Manifest.Containers = []kapi.Container{
+		{
+			Name:  "container1",
+			Image: "registry:8080/repo1:ref2",
+			Env:   []kapi.EnvVar{{Name: "A", Value: "3"}, {Name: "C", Value: "4"}},
+			Ports: []kapi.Port{{ContainerPort: 8080, HostPort: 80}},
+		},
+		{
+			Name:  "container3",
+			Image: "registry:8080/repo3:ref1",
+		},
+	}
+
+	expected := []deployapi.DeploymentChange{
+		{Type: deployapi.DeploymentChangeImage, Container: "container1", From: "registry:8080/repo1:ref1", To: "registry:8080/repo1:ref2"},
+		{Type: deployapi.DeploymentChangeEnv, Container: "container1", Name: "A", From: "1", To: "3"},
+		{Type: deployapi.DeploymentChangeEnv, Container: "container1", Name: "C", To: "4"},
+		{Type: deployapi.DeploymentChangeEnv, Container: "container1", Name: "B", From: "2"},
+		{Type: deployapi.DeploymentChangePort, Container: "container1", Name: "8080/TCP", From: "8080/TCP", To: "8080/TCP->80"},
+		{Type: deployapi.DeploymentChangeContainerAdded, Container: "container3", To: "registry:8080/repo3:ref1"},
+		{Type: deployapi.DeploymentChangeContainerRemoved, Container: "container2", From: "registry:8080/repo2:ref1"},
+	}
+
+	changes := DiffPodTemplates(from, to)
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected changes %#v, got %#v", expected, changes)
+	}
+	for i := range expected {
+		if expected[i] != changes[i] {
+			t.Errorf("Expected change %d to be %#v, got %#v", i, expected[i], changes[i])
+		}
+	}
+}
+
+func TestParseContainerImage(t *testing.T) {
+	tests := []struct {
+		image string
+		repo  string
+		ref   string
+	}{
+		{"repo1", "repo1", ""},
+		{"repo1:ref1", "repo1", "ref1"},
+		{"registry:8080/repo1", "registry:8080/repo1", ""},
+		{"registry:8080/repo1:ref1", "registry:8080/repo1", "ref1"},
+		{"registry:8080/repo1@sha256:1111", "registry:8080/repo1", "sha256:1111"},
+	}
+
+	for _, test := range tests {
+		repo, ref := ParseContainerImage(test.image)
+		if repo != test.repo || ref != test.ref {
+			t.Errorf("Expected %s to parse to %s and %s, got %s and %s", test.image, test.repo, test.ref, repo, ref)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package dockerregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// DefaultTimeout is the time a registry has to respond to a request of a Client created by NewClient.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultRegistry is the registry hosting Docker repositories whose name doesn't include one.
+const DefaultRegistry = imageapi.DockerDefaultRegistry
+
+// Client reads tags and images from Docker registries with the v1 registry API. Registries are
+// contacted over HTTPS unless they have been explicitly marked insecure. Registries which require a
+// token, like the Docker Hub, are not supported yet.
+type Client struct {
+	HTTPClient *http.Client
+	// InsecureRegistries are contacted over plain HTTP rather than HTTPS.
+	InsecureRegistries util.StringSet
+}
+
+// NewClient creates a Client which gives up on registries which take longer than DefaultTimeout to
+// respond and contacts the given registries, and only those, over plain HTTP.
+func NewClient(insecureRegistries ...string) *Client {
+	return &Client{
+		HTTPClient:         &http.Client{Timeout: DefaultTimeout},
+		InsecureRegistries: util.NewStringSet(insecureRegistries...),
+	}
+}
+
+// ImageTags returns the image ID of every tag of the Docker repository dockerRepo, which has the
+// form [registry/][namespace/]name.
+func (c *Client) ImageTags(dockerRepo string) (map[string]string, error) {
+	registry, namespace, name, err := SplitDockerImageRepository(dockerRepo)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string)
+	if err := c.get(registry, fmt.Sprintf("/v1/repositories/%s/%s/tags", namespace, name), &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Image returns the metadata of the image with the given ID from the registry hosting the Docker
+// repository dockerRepo.
+func (c *Client) Image(dockerRepo, id string) (*docker.Image, error) {
+	registry, _, _, err := SplitDockerImageRepository(dockerRepo)
+	if err != nil {
+		return nil, err
+	}
+	image := &docker.Image{}
+	if err := c.get(registry, fmt.Sprintf("/v1/images/%s/json", id), image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// get decodes the JSON response to a GET of path from registry into obj.
+func (c *Client) get(registry, path string, obj interface{}) error {
+	scheme := "https"
+	if c.InsecureRegistries.Has(registry) {
+		scheme = "http"
+	}
+	resp, err := c.HTTPClient.Get(scheme + "://" + registry + path)
+	if err != nil {
+		return fmt.Errorf("unable to reach registry %s: %v", registry, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s was not found in registry %s", path, registry)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("registry %s responded to %s with %s", registry, path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(obj); err != nil {
+		return fmt.Errorf("unable to decode the response of registry %s to %s: %v", registry, path, err)
+	}
+	return nil
+}
+
+// SplitDockerImageRepository splits a Docker repository name of the form [registry/][namespace/]name
+// into its registry, namespace and name, defaulting the registry to DefaultRegistry and the namespace
+// to "library".
+func SplitDockerImageRepository(dockerRepo string) (registry, namespace, name string, err error) {
+	ref, err := imageapi.ParseDockerImageReference(dockerRepo)
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(ref.Tag) > 0 || len(ref.ID) > 0 {
+		return "", "", "", fmt.Errorf("invalid Docker repository %q", dockerRepo)
+	}
+	ref = ref.DockerClientDefaults()
+	return ref.Registry, ref.Namespace, ref.Name, nil
+}
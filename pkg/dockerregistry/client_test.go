@@ -0,0 +1,132 @@
+package dockerregistry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+// testRegistry starts a stand-in for a Docker registry serving the v1 tag and image API for the
+// repository test/repo.
+func testRegistry(t *testing.T, tags map[string]string, images map[string]*docker.Image) (*httptest.Server, string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var obj interface{}
+		switch {
+		case req.URL.Path == "/v1/repositories/test/repo/tags":
+			obj = tags
+		case strings.HasPrefix(req.URL.Path, "/v1/images/") && strings.HasSuffix(req.URL.Path, "/json"):
+			image, ok := images[strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v1/images/"), "/json")]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			obj = image
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(obj); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}))
+	return server, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestImageTags(t *testing.T) {
+	server, registry := testRegistry(t, map[string]string{"latest": "abc", "v1": "def"}, nil)
+	defer server.Close()
+
+	tags, err := NewClient(registry).ImageTags(registry + "/test/repo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tags) != 2 || tags["latest"] != "abc" || tags["v1"] != "def" {
+		t.Errorf("Unexpected tags: %#v", tags)
+	}
+
+	if _, err := NewClient(registry).ImageTags(registry + "/test/other"); err == nil {
+		t.Errorf("Expected an error for an unknown repository")
+	}
+}
+
+func TestImage(t *testing.T) {
+	server, registry := testRegistry(t, nil, map[string]*docker.Image{
+		"abc": {ID: "abc", Config: &docker.Config{Cmd: []string{"ls"}}},
+	})
+	defer server.Close()
+
+	image, err := NewClient(registry).Image(registry+"/test/repo", "abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if image.ID != "abc" || image.Config == nil || len(image.Config.Cmd) != 1 {
+		t.Errorf("Unexpected image: %#v", image)
+	}
+
+	if _, err := NewClient(registry).Image(registry+"/test/repo", "def"); err == nil {
+		t.Errorf("Expected an error for an unknown image")
+	}
+}
+
+func TestSecureRegistry(t *testing.T) {
+	server, registry := testRegistry(t, map[string]string{"latest": "abc"}, nil)
+	defer server.Close()
+
+	// a registry which hasn't been marked insecure is never contacted over plain HTTP
+	if _, err := NewClient().ImageTags(registry + "/test/repo"); err == nil {
+		t.Errorf("Expected an error for a registry which doesn't support HTTPS")
+	}
+}
+
+func TestUnresponsiveRegistry(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	registry := strings.TrimPrefix(server.URL, "http://")
+
+	client := NewClient(registry)
+	client.HTTPClient.Timeout = 10 * time.Millisecond
+	if _, err := client.ImageTags(registry + "/test/repo"); err == nil {
+		t.Errorf("Expected an error for a registry which doesn't respond")
+	}
+}
+
+func TestSplitDockerImageRepository(t *testing.T) {
+	testCases := []struct {
+		dockerRepo, registry, namespace, name string
+		err                                   bool
+	}{
+		{dockerRepo: "ruby", registry: DefaultRegistry, namespace: "library", name: "ruby"},
+		{dockerRepo: "openshift/ruby", registry: DefaultRegistry, namespace: "openshift", name: "ruby"},
+		{dockerRepo: "localhost/ruby", registry: "localhost", namespace: "library", name: "ruby"},
+		{dockerRepo: "registry:5000/openshift/ruby", registry: "registry:5000", namespace: "openshift", name: "ruby"},
+		{dockerRepo: "docker.example.com/ruby", registry: "docker.example.com", namespace: "library", name: "ruby"},
+		{dockerRepo: "a/b/c", err: true},
+		{dockerRepo: "", err: true},
+	}
+
+	for _, test := range testCases {
+		registry, namespace, name, err := SplitDockerImageRepository(test.dockerRepo)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected an error for %q", test.dockerRepo)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", test.dockerRepo, err)
+			continue
+		}
+		if registry != test.registry || namespace != test.namespace || name != test.name {
+			t.Errorf("Unexpected split of %q: %s, %s, %s", test.dockerRepo, registry, namespace, name)
+		}
+	}
+}
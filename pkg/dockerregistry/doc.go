@@ -0,0 +1,3 @@
+// Package dockerregistry provides a client for the read only parts of the Docker Registry v1 API,
+// used to import tags and images from external registries.
+package dockerregistry
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DockerDefaultRegistry is the registry of Docker repositories which don't name one.
+	DockerDefaultRegistry = "index.docker.io"
+	// DockerDefaultNamespace is the namespace of Docker repositories which don't name one.
+	DockerDefaultNamespace = "library"
+)
+
+// DockerImageReference is a Docker pull spec of the form [registry/][namespace/]name[:tag|@id],
+// split into its components. The components the pull spec omits are empty.
+type DockerImageReference struct {
+	Registry  string
+	Namespace string
+	Name      string
+	// Tag is the tag the pull spec names after a ':'.
+	Tag string
+	// ID is the image ID or digest the pull spec names after an '@'.
+	ID string
+}
+
+// ParseDockerImageReference parses a Docker pull spec. The first component of the repository is a
+// registry if it contains a '.' or a ':', or is localhost, so the port of a registry host isn't
+// mistaken for a tag. Everything after an '@' is an ID, so the ':' of a digest isn't either.
+func ParseDockerImageReference(spec string) (DockerImageReference, error) {
+	ref := DockerImageReference{}
+
+	repo := spec
+	if i := strings.Index(spec, "@"); i != -1 {
+		repo, ref.ID = spec[:i], spec[i+1:]
+		if len(ref.ID) == 0 {
+			return ref, fmt.Errorf("the image ID of %q is empty", spec)
+		}
+	} else if i := strings.LastIndex(spec, ":"); i != -1 && !strings.Contains(spec[i+1:], "/") {
+		repo, ref.Tag = spec[:i], spec[i+1:]
+		if len(ref.Tag) == 0 {
+			return ref, fmt.Errorf("the tag of %q is empty", spec)
+		}
+	}
+
+	parts := strings.Split(repo, "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry, parts = parts[0], parts[1:]
+	}
+	switch len(parts) {
+	case 1:
+		ref.Name = parts[0]
+	case 2:
+		ref.Namespace, ref.Name = parts[0], parts[1]
+		if len(ref.Namespace) == 0 {
+			return ref, fmt.Errorf("the namespace of %q is empty", spec)
+		}
+	default:
+		return ref, fmt.Errorf("invalid Docker pull spec %q", spec)
+	}
+	if len(ref.Name) == 0 {
+		return ref, fmt.Errorf("the name of %q is empty", spec)
+	}
+	return ref, nil
+}
+
+// DockerClientDefaults returns the reference with the registry and namespace Docker assumes when
+// they are omitted filled in.
+func (r DockerImageReference) DockerClientDefaults() DockerImageReference {
+	if len(r.Registry) == 0 {
+		r.Registry = DockerDefaultRegistry
+	}
+	if len(r.Namespace) == 0 {
+		r.Namespace = DockerDefaultNamespace
+	}
+	return r
+}
+
+// RepositoryName returns the Docker repository of the reference, without tag or ID.
+func (r DockerImageReference) RepositoryName() string {
+	name := r.Name
+	if len(r.Namespace) > 0 {
+		name = r.Namespace + "/" + name
+	}
+	if len(r.Registry) > 0 {
+		name = r.Registry + "/" + name
+	}
+	return name
+}
+
+// String returns the pull spec of the reference.
+func (r DockerImageReference) String() string {
+	switch {
+	case len(r.ID) > 0:
+		return r.RepositoryName() + "@" + r.ID
+	case len(r.Tag) > 0:
+		return r.RepositoryName() + ":" + r.Tag
+	}
+	return r.RepositoryName()
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestParseDockerImageReference(t *testing.T) {
+	testCases := []struct {
+		spec string
+		ref  DockerImageReference
+		err  bool
+	}{
+		{spec: "ruby", ref: DockerImageReference{Name: "ruby"}},
+		{spec: "ruby:2.0", ref: DockerImageReference{Name: "ruby", Tag: "2.0"}},
+		{spec: "openshift/ruby", ref: DockerImageReference{Namespace: "openshift", Name: "ruby"}},
+		{spec: "localhost/ruby", ref: DockerImageReference{Registry: "localhost", Name: "ruby"}},
+		{spec: "docker.example.com/ruby:2.0", ref: DockerImageReference{Registry: "docker.example.com", Name: "ruby", Tag: "2.0"}},
+		{spec: "registry:5000/openshift/ruby", ref: DockerImageReference{Registry: "registry:5000", Namespace: "openshift", Name: "ruby"}},
+		{spec: "registry:5000/openshift/ruby:2.0", ref: DockerImageReference{Registry: "registry:5000", Namespace: "openshift", Name: "ruby", Tag: "2.0"}},
+		{spec: "registry:5000/openshift/ruby@abc123", ref: DockerImageReference{Registry: "registry:5000", Namespace: "openshift", Name: "ruby", ID: "abc123"}},
+		{spec: "registry:5000/openshift/ruby@sha256:1111", ref: DockerImageReference{Registry: "registry:5000", Namespace: "openshift", Name: "ruby", ID: "sha256:1111"}},
+		{spec: "", err: true},
+		{spec: "ruby:", err: true},
+		{spec: "ruby@", err: true},
+		{spec: "/ruby", err: true},
+		{spec: "openshift/", err: true},
+		{spec: "a/b/c", err: true},
+		{spec: "registry:5000/a/b/c", err: true},
+	}
+
+	for _, testCase := range testCases {
+		ref, err := ParseDockerImageReference(testCase.spec)
+		if testCase.err {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %#v", testCase.spec, ref)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", testCase.spec, err)
+			continue
+		}
+		if ref != testCase.ref {
+			t.Errorf("%q: expected %#v, got %#v", testCase.spec, testCase.ref, ref)
+		}
+		if e, a := testCase.spec, ref.String(); e != a {
+			t.Errorf("%q: expected the pull spec to round trip, got %q", e, a)
+		}
+	}
+}
+
+func TestDockerClientDefaults(t *testing.T) {
+	testCases := map[string]string{
+		"ruby":                         "index.docker.io/library/ruby",
+		"openshift/ruby:2.0":           "index.docker.io/openshift/ruby:2.0",
+		"registry:5000/ruby@abc123":    "registry:5000/library/ruby@abc123",
+		"registry:5000/openshift/ruby": "registry:5000/openshift/ruby",
+	}
+
+	for spec, expected := range testCases {
+		ref, err := ParseDockerImageReference(spec)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", spec, err)
+			continue
+		}
+		if a := ref.DockerClientDefaults().String(); expected != a {
+			t.Errorf("%q: expected %q, got %q", spec, expected, a)
+		}
+	}
+}
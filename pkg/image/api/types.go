@@ -2,6 +2,7 @@ package api
 
 import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/fsouza/go-dockerclient"
 )
 
@@ -17,6 +18,55 @@ type Image struct {
 	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	DockerImageReference string            `json:"dockerImageReference,omitempty" yaml:"dockerImageReference,omitempty"`
 	Metadata             docker.Image      `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// Signatures are the signatures and provenance records attached to the image. They are
+	// maintained through ImageSignatures.
+	Signatures []ImageSignature `json:"signatures,omitempty" yaml:"signatures,omitempty"`
+}
+
+// ImageSignatureStatus is the result of the verification of an ImageSignature.
+type ImageSignatureStatus string
+
+const (
+	// ImageSignatureUnverified means the signature hasn't been verified yet.
+	ImageSignatureUnverified ImageSignatureStatus = "Unverified"
+	// ImageSignatureVerified means the signature was verified, which approves the image.
+	ImageSignatureVerified ImageSignatureStatus = "Verified"
+	// ImageSignatureRejected means the verification of the signature failed.
+	ImageSignatureRejected ImageSignatureStatus = "Rejected"
+)
+
+// ImageSignature attaches a signature or an attestation to the Image it names. The ID of the
+// signature distinguishes it from the other signatures of the image; attaching a signature with
+// the ID of an existing one replaces it, which is how verifiers record the verification status.
+type ImageSignature struct {
+	kapi.TypeMeta `json:",inline" yaml:",inline"`
+	// Image is the ID of the signed Image.
+	Image string `json:"image" yaml:"image"`
+	// Signer identifies who signed the image.
+	Signer string `json:"signer" yaml:"signer"`
+	// Signature is the signature blob.
+	Signature []byte `json:"signature,omitempty" yaml:"signature,omitempty"`
+	// Status is the verification status of the signature. It is set by the server, which verifies
+	// the signature against the trusted key of the signer.
+	Status ImageSignatureStatus `json:"status,omitempty" yaml:"status,omitempty"`
+	// Provenance records how the image was produced. Builds attach it automatically; clients can't.
+	Provenance *ImageProvenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+}
+
+// ImageProvenanceSigner is the signer of the ImageSignatures which record the provenance of built
+// images. Only the build controller signs as it.
+const ImageProvenanceSigner = "openshift-build-controller"
+
+// ImageProvenance records the Build which produced an image.
+type ImageProvenance struct {
+	// Build is the ID of the Build.
+	Build string `json:"build" yaml:"build"`
+	// BuildConfig is the ID of the BuildConfig the Build was created from, if any.
+	BuildConfig string `json:"buildConfig,omitempty" yaml:"buildConfig,omitempty"`
+	// SourceURI is the location of the source the image was built from.
+	SourceURI string `json:"sourceURI,omitempty" yaml:"sourceURI,omitempty"`
+	// Commit is the commit of the source which was built, when known.
+	Commit string `json:"commit,omitempty" yaml:"commit,omitempty"`
 }
 
 // ImageRepositoryList is a list of ImageRepository objects.
@@ -33,9 +83,85 @@ type ImageRepository struct {
 	Labels                map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	DockerImageRepository string            `json:"dockerImageRepository,omitempty" yaml:"dockerImageRepository,omitempty"`
 	Tags                  map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Import, if true, makes the server periodically import the tags and images of
+	// DockerImageRepository from the registry hosting it.
+	Import bool `json:"import,omitempty" yaml:"import,omitempty"`
+	// TagAliases makes tags of this repository track tags of other repositories: whenever the
+	// referenced tag changes, the aliased tag is set to the same image.
+	TagAliases map[string]TagReference `json:"tagAliases,omitempty" yaml:"tagAliases,omitempty"`
+	// TagHistory holds, for every tag which was ever set, the images the tag pointed to, newest
+	// first. It is maintained by the server.
+	TagHistory map[string][]TagEvent `json:"tagHistory,omitempty" yaml:"tagHistory,omitempty"`
+	// Status holds the pull specs of the repository and its tags. It is computed by the server.
+	Status ImageRepositoryStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// ImageRepositoryStatus holds the fully qualified pull specs of an ImageRepository.
+type ImageRepositoryStatus struct {
+	// DockerImageRepository is the DockerImageRepository of the repository including the registry
+	// and namespace Docker assumes when they are omitted.
+	DockerImageRepository string `json:"dockerImageRepository,omitempty" yaml:"dockerImageRepository,omitempty"`
+	// Tags holds the pull specs of every tag.
+	Tags map[string]TagStatus `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// TagStatus holds the pull specs of a tag of an ImageRepository.
+type TagStatus struct {
+	// ImageID is the ID of the image the tag points to.
+	ImageID string `json:"imageID" yaml:"imageID"`
+	// PullSpec pulls the tag, like "registry/namespace/name:tag". The image it pulls changes with
+	// the tag.
+	PullSpec string `json:"pullSpec,omitempty" yaml:"pullSpec,omitempty"`
+	// ImagePullSpec pulls the image the tag points to by its ID, even after the tag moved on.
+	ImagePullSpec string `json:"imagePullSpec,omitempty" yaml:"imagePullSpec,omitempty"`
 }
 
-// TODO add metadata overrides
+// TagEventSource identifies what set a tag of an ImageRepository.
+type TagEventSource string
+
+const (
+	// TagEventSourceMapping means the tag was set by an ImageRepositoryMapping, typically when an
+	// image was pushed to the Docker image repository.
+	TagEventSourceMapping TagEventSource = "ImageRepositoryMapping"
+	// TagEventSourceUpdate means the tag was set by an update of the ImageRepository's tags.
+	TagEventSourceUpdate TagEventSource = "ImageRepositoryUpdate"
+	// TagEventSourceAlias means the tag was set because the tag it is an alias of changed.
+	TagEventSourceAlias TagEventSource = "TagAlias"
+	// TagEventSourceTag means the tag was copied from another tag by an ImageRepositoryTag.
+	TagEventSourceTag TagEventSource = "ImageRepositoryTag"
+)
+
+// TagReference identifies a tag of an ImageRepository.
+type TagReference struct {
+	// Namespace is the namespace of the ImageRepository. It defaults to the namespace of the
+	// repository holding the reference.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// ImageRepository is the ID of the ImageRepository.
+	ImageRepository string `json:"imageRepository" yaml:"imageRepository"`
+	// Tag is the name of the tag.
+	Tag string `json:"tag" yaml:"tag"`
+}
+
+// TagEvent records that a tag of an ImageRepository was set to an image.
+type TagEvent struct {
+	// Created is the time the tag was set.
+	Created util.Time `json:"created" yaml:"created"`
+	// ImageID is the ID of the image the tag was set to.
+	ImageID string `json:"imageID" yaml:"imageID"`
+	// DockerImageReference is the pull spec of the image, when known.
+	DockerImageReference string `json:"dockerImageReference,omitempty" yaml:"dockerImageReference,omitempty"`
+	// SetBy identifies what set the tag.
+	SetBy TagEventSource `json:"setBy,omitempty" yaml:"setBy,omitempty"`
+}
+
+// ImageRepositoryTag sets the tag Tag of the ImageRepository with the ID of the ImageRepositoryTag
+// to the image the tag From currently points to. Unlike an alias, the tag doesn't follow later
+// changes of From.
+type ImageRepositoryTag struct {
+	kapi.TypeMeta `json:",inline" yaml:",inline"`
+	Tag           string       `json:"tag" yaml:"tag"`
+	From          TagReference `json:"from" yaml:"from"`
+}
 
 // ImageRepositoryMapping represents a mapping from a single tag to a Docker image as
 // well as the reference to the Docker image repository the image came from.
@@ -45,3 +171,19 @@ type ImageRepositoryMapping struct {
 	Image                 Image  `json:"image" yaml:"image"`
 	Tag                   string `json:"tag" yaml:"tag"`
 }
+
+// ImagePrune finds the Images of a namespace which are no longer referenced and, if confirmed,
+// deletes them. An Image is referenced if a tag of an ImageRepository in any namespace points to it,
+// pointed to it within the tag history retention, or if a container of the pod template of a
+// replication controller runs it.
+type ImagePrune struct {
+	kapi.TypeMeta `json:",inline" yaml:",inline"`
+	// Confirm, if true, deletes the unreferenced Images. Otherwise they are only reported.
+	Confirm bool `json:"confirm,omitempty" yaml:"confirm,omitempty"`
+	// TagHistoryRetentionSeconds is how long an Image stays referenced after the last tag pointing
+	// to it moved on. It defaults to a week.
+	TagHistoryRetentionSeconds int64 `json:"tagHistoryRetentionSeconds,omitempty" yaml:"tagHistoryRetentionSeconds,omitempty"`
+	// Images are the IDs of the unreferenced Images, which were deleted if Confirm is true. It is
+	// set by the server.
+	Images []string `json:"images,omitempty" yaml:"images,omitempty"`
+}
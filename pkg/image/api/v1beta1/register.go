@@ -11,6 +11,9 @@ func init() {
 		&ImageRepository{},
 		&ImageRepositoryList{},
 		&ImageRepositoryMapping{},
+		&ImageRepositoryTag{},
+		&ImagePrune{},
+		&ImageSignature{},
 	)
 }
 
@@ -19,3 +22,6 @@ func (*ImageList) IsAnAPIObject()              {}
 func (*ImageRepository) IsAnAPIObject()        {}
 func (*ImageRepositoryList) IsAnAPIObject()    {}
 func (*ImageRepositoryMapping) IsAnAPIObject() {}
+func (*ImagePrune) IsAnAPIObject()             {}
+func (*ImageRepositoryTag) IsAnAPIObject()     {}
+func (*ImageSignature) IsAnAPIObject()         {}
@@ -38,3 +38,82 @@ func ValidateImageRepositoryMapping(mapping *api.ImageRepositoryMapping) errors.
 
 	return result
 }
+
+// ValidateImageRepository tests required fields for an ImageRepository.
+func ValidateImageRepository(repo *api.ImageRepository) errors.ErrorList {
+	result := errors.ErrorList{}
+
+	for tag, ref := range repo.TagAliases {
+		for _, err := range validateTagReference(&ref).Prefix("TagAliases[" + tag + "]") {
+			result = append(result, err)
+		}
+	}
+
+	return result
+}
+
+// ValidateImageRepositoryTag tests required fields for an ImageRepositoryTag.
+func ValidateImageRepositoryTag(tag *api.ImageRepositoryTag) errors.ErrorList {
+	result := errors.ErrorList{}
+
+	if len(tag.ID) == 0 {
+		result = append(result, errors.NewFieldRequired("ID", tag.ID))
+	}
+
+	if len(tag.Tag) == 0 {
+		result = append(result, errors.NewFieldRequired("Tag", tag.Tag))
+	}
+
+	for _, err := range validateTagReference(&tag.From).Prefix("From") {
+		result = append(result, err)
+	}
+
+	return result
+}
+
+func validateTagReference(ref *api.TagReference) errors.ErrorList {
+	result := errors.ErrorList{}
+
+	if len(ref.ImageRepository) == 0 {
+		result = append(result, errors.NewFieldRequired("ImageRepository", ref.ImageRepository))
+	}
+
+	if len(ref.Tag) == 0 {
+		result = append(result, errors.NewFieldRequired("Tag", ref.Tag))
+	}
+
+	return result
+}
+
+// ValidateImageSignature tests required fields for an ImageSignature.
+func ValidateImageSignature(signature *api.ImageSignature) errors.ErrorList {
+	result := errors.ErrorList{}
+
+	if len(signature.ID) == 0 {
+		result = append(result, errors.NewFieldRequired("ID", signature.ID))
+	}
+
+	if len(signature.Image) == 0 {
+		result = append(result, errors.NewFieldRequired("Image", signature.Image))
+	}
+
+	if len(signature.Signer) == 0 {
+		result = append(result, errors.NewFieldRequired("Signer", signature.Signer))
+	}
+
+	if len(signature.Signature) == 0 && signature.Provenance == nil {
+		result = append(result, errors.NewFieldRequired("Signature", signature.Signature))
+	}
+
+	switch signature.Status {
+	case "", api.ImageSignatureUnverified, api.ImageSignatureVerified, api.ImageSignatureRejected:
+	default:
+		result = append(result, errors.NewFieldNotSupported("Status", signature.Status))
+	}
+
+	if signature.Provenance != nil && len(signature.Provenance.Build) == 0 {
+		result = append(result, errors.NewFieldRequired("Provenance.Build", signature.Provenance.Build))
+	}
+
+	return result
+}
@@ -106,3 +106,102 @@ func TestValidateImageRepositoryMappingNotOK(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateImageRepositoryTagAliases(t *testing.T) {
+	repo := &api.ImageRepository{
+		TypeMeta: kapi.TypeMeta{ID: "prod"},
+		TagAliases: map[string]api.TagReference{
+			"latest": {ImageRepository: "dev", Tag: "v2.3"},
+		},
+	}
+	if errs := ValidateImageRepository(repo); len(errs) > 0 {
+		t.Errorf("Unexpected non-empty error list: %#v", errs)
+	}
+
+	repo.TagAliases["stable"] = api.TagReference{ImageRepository: "dev"}
+	errs := ValidateImageRepository(repo)
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got %#v", errs)
+	}
+	if e, a := "TagAliases[stable].Tag", errs[0].(errors.ValidationError).Field; e != a {
+		t.Errorf("Expected error for field %s, got %s", e, a)
+	}
+}
+
+func TestValidateImageRepositoryTag(t *testing.T) {
+	tag := &api.ImageRepositoryTag{
+		TypeMeta: kapi.TypeMeta{ID: "prod"},
+		Tag:      "latest",
+		From:     api.TagReference{ImageRepository: "dev", Tag: "v2.3"},
+	}
+	if errs := ValidateImageRepositoryTag(tag); len(errs) > 0 {
+		t.Errorf("Unexpected non-empty error list: %#v", errs)
+	}
+
+	errorCases := map[string]struct {
+		I api.ImageRepositoryTag
+		F string
+	}{
+		"missing ID":              {api.ImageRepositoryTag{Tag: "latest", From: tag.From}, "ID"},
+		"missing Tag":             {api.ImageRepositoryTag{TypeMeta: tag.TypeMeta, From: tag.From}, "Tag"},
+		"missing From repository": {api.ImageRepositoryTag{TypeMeta: tag.TypeMeta, Tag: "latest", From: api.TagReference{Tag: "v2.3"}}, "From.ImageRepository"},
+		"missing From tag":        {api.ImageRepositoryTag{TypeMeta: tag.TypeMeta, Tag: "latest", From: api.TagReference{ImageRepository: "dev"}}, "From.Tag"},
+	}
+
+	for k, v := range errorCases {
+		errs := ValidateImageRepositoryTag(&v.I)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected one error, got %#v", k, errs)
+			continue
+		}
+		if errs[0].(errors.ValidationError).Type != errors.ValidationErrorTypeRequired || errs[0].(errors.ValidationError).Field != v.F {
+			t.Errorf("%s: expected a required error for field %s: %v", k, v.F, errs[0])
+		}
+	}
+}
+
+func TestValidateImageSignature(t *testing.T) {
+	signature := &api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "security"},
+		Image:     "abc123",
+		Signer:    "security@example.com",
+		Signature: []byte("signature"),
+	}
+	if errs := ValidateImageSignature(signature); len(errs) > 0 {
+		t.Errorf("Unexpected non-empty error list: %#v", errs)
+	}
+
+	provenance := &api.ImageSignature{
+		TypeMeta:   kapi.TypeMeta{ID: "build"},
+		Image:      "abc123",
+		Signer:     "build-controller",
+		Provenance: &api.ImageProvenance{Build: "build1"},
+	}
+	if errs := ValidateImageSignature(provenance); len(errs) > 0 {
+		t.Errorf("Unexpected non-empty error list: %#v", errs)
+	}
+
+	errorCases := map[string]struct {
+		S api.ImageSignature
+		T errors.ValidationErrorType
+		F string
+	}{
+		"missing ID":               {api.ImageSignature{Image: "abc123", Signer: "s", Signature: []byte("x")}, errors.ValidationErrorTypeRequired, "ID"},
+		"missing Image":            {api.ImageSignature{TypeMeta: signature.TypeMeta, Signer: "s", Signature: []byte("x")}, errors.ValidationErrorTypeRequired, "Image"},
+		"missing Signer":           {api.ImageSignature{TypeMeta: signature.TypeMeta, Image: "abc123", Signature: []byte("x")}, errors.ValidationErrorTypeRequired, "Signer"},
+		"missing Signature":        {api.ImageSignature{TypeMeta: signature.TypeMeta, Image: "abc123", Signer: "s"}, errors.ValidationErrorTypeRequired, "Signature"},
+		"missing Provenance build": {api.ImageSignature{TypeMeta: signature.TypeMeta, Image: "abc123", Signer: "s", Provenance: &api.ImageProvenance{}}, errors.ValidationErrorTypeRequired, "Provenance.Build"},
+		"unknown Status":           {api.ImageSignature{TypeMeta: signature.TypeMeta, Image: "abc123", Signer: "s", Signature: []byte("x"), Status: "Approved"}, errors.ValidationErrorTypeNotSupported, "Status"},
+	}
+
+	for k, v := range errorCases {
+		errs := ValidateImageSignature(&v.S)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected one error, got %#v", k, errs)
+			continue
+		}
+		if errs[0].(errors.ValidationError).Type != v.T || errs[0].(errors.ValidationError).Field != v.F {
+			t.Errorf("%s: expected a %s error for field %s: %v", k, v.T, v.F, errs[0])
+		}
+	}
+}
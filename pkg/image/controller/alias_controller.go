@@ -0,0 +1,128 @@
+package controller
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	oscache "github.com/openshift/origin/pkg/client/cache"
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// TagAliasController watches for changes to ImageRepositories and sets every tag which is an alias of
+// a tag of a changed repository to the image the aliased tag points to. The tags of a new or changed
+// repository which are aliases are resolved as well. Tags are set by updating the repository, so
+// ImageChange triggers of DeploymentConfigs fire on aliased tags as on any other tag. The aliased tags
+// are applied to the current state of a repository, and re-applied if another change of the repository
+// wins the race, so concurrent changes of its other tags are kept.
+type TagAliasController struct {
+	RepositoryInterface aliasRepositoryInterface
+	NextImageRepository func() *api.ImageRepository
+	// RepositoryStore holds all ImageRepositories, indexed by RepositoryIndex and TagAliasIndex.
+	RepositoryStore oscache.Indexer
+}
+
+// maxAliasUpdateAttempts is the number of times the aliased tags of a repository are applied before
+// giving up when its update keeps conflicting with other changes.
+const maxAliasUpdateAttempts = 3
+
+type aliasRepositoryInterface interface {
+	GetImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error)
+	UpdateImageRepository(ctx kapi.Context, repo *api.ImageRepository) (*api.ImageRepository, error)
+}
+
+// Run processes ImageRepository events one by one.
+func (c *TagAliasController) Run() {
+	go util.Forever(c.HandleImageRepository, 0)
+}
+
+// HandleImageRepository processes the next ImageRepository event.
+func (c *TagAliasController) HandleImageRepository() {
+	repo := c.NextImageRepository()
+	c.resolveAliases(repo)
+
+	for _, obj := range c.RepositoryStore.Index(TagAliasIndex, repositoryKey(repo.Namespace, repo.ID)) {
+		c.resolveAliases(obj.(*api.ImageRepository))
+	}
+}
+
+// resolveAliases updates repo if any of its aliased tags points to another image than the tag it
+// is an alias of.
+func (c *TagAliasController) resolveAliases(repo *api.ImageRepository) {
+	if _, changed := c.aliasedTags(repo); !changed {
+		return
+	}
+
+	ctx := kapi.WithNamespace(kapi.NewContext(), repo.Namespace)
+	for attempt := 1; ; attempt++ {
+		err := c.updateAliasedTags(ctx, repo.ID)
+		if err == nil {
+			return
+		}
+		if !errors.IsConflict(err) || attempt == maxAliasUpdateAttempts {
+			glog.V(2).Infof("Error updating the aliased tags of imageRepository %s: %v", repo.ID, err)
+			return
+		}
+		glog.V(4).Infof("Retrying the update of the aliased tags of imageRepository %s: %v", repo.ID, err)
+	}
+}
+
+// updateAliasedTags sets the aliased tags of the current state of the repository with the given ID, so
+// that only those tags are changed.
+func (c *TagAliasController) updateAliasedTags(ctx kapi.Context, id string) error {
+	repo, err := c.RepositoryInterface.GetImageRepository(ctx, id)
+	if err != nil {
+		return err
+	}
+	tags, changed := c.aliasedTags(repo)
+	if !changed {
+		return nil
+	}
+	repo.Tags = tags
+	_, err = c.RepositoryInterface.UpdateImageRepository(ctx, repo)
+	return err
+}
+
+// aliasedTags returns the tags of repo with every aliased tag set to the image of the tag it is an
+// alias of, and whether any of them changed.
+func (c *TagAliasController) aliasedTags(repo *api.ImageRepository) (map[string]string, bool) {
+	tags := make(map[string]string)
+	for tag, imageID := range repo.Tags {
+		tags[tag] = imageID
+	}
+
+	changed := false
+	for tag, ref := range repo.TagAliases {
+		source := c.findRepository(referenceNamespace(repo, ref), ref.ImageRepository)
+		if source == nil {
+			glog.V(4).Infof("Tag %s of imageRepository %s is an alias of missing imageRepository %s/%s", tag, repo.ID, referenceNamespace(repo, ref), ref.ImageRepository)
+			continue
+		}
+		imageID := source.Tags[ref.Tag]
+		if len(imageID) == 0 || tags[tag] == imageID {
+			continue
+		}
+		glog.V(2).Infof("Setting tag %s of imageRepository %s to image %s of tag %s of imageRepository %s", tag, repo.ID, imageID, ref.Tag, source.ID)
+		tags[tag] = imageID
+		changed = true
+	}
+	return tags, changed
+}
+
+// findRepository returns the ImageRepository with the given namespace and ID from the store, or nil.
+func (c *TagAliasController) findRepository(namespace, id string) *api.ImageRepository {
+	objs := c.RepositoryStore.Index(RepositoryIndex, repositoryKey(namespace, id))
+	if len(objs) == 0 {
+		return nil
+	}
+	return objs[0].(*api.ImageRepository)
+}
+
+// referenceNamespace returns the namespace of the repository referenced by a tag of repo.
+func referenceNamespace(repo *api.ImageRepository, ref api.TagReference) string {
+	if len(ref.Namespace) == 0 {
+		return repo.Namespace
+	}
+	return ref.Namespace
+}
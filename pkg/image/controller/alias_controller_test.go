@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	oscache "github.com/openshift/origin/pkg/client/cache"
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// testAliasRepositoryInterface serves the current state of repositories from Current, and fails the
+// first Conflicts updates with a conflict.
+type testAliasRepositoryInterface struct {
+	Current   map[string]*api.ImageRepository
+	Conflicts int
+	Updated   []*api.ImageRepository
+}
+
+func (i *testAliasRepositoryInterface) GetImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error) {
+	repo, ok := i.Current[kapi.Namespace(ctx)+"/"+id]
+	if !ok {
+		return nil, kerrors.NewNotFound("imageRepository", id)
+	}
+	current := *repo
+	current.Tags = make(map[string]string)
+	for tag, imageID := range repo.Tags {
+		current.Tags[tag] = imageID
+	}
+	return &current, nil
+}
+
+func (i *testAliasRepositoryInterface) UpdateImageRepository(ctx kapi.Context, repo *api.ImageRepository) (*api.ImageRepository, error) {
+	if i.Conflicts > 0 {
+		i.Conflicts--
+		return nil, kerrors.NewConflict("imageRepository", repo.ID, errors.New("modified"))
+	}
+	i.Updated = append(i.Updated, repo)
+	return repo, nil
+}
+
+func aliasController(next *api.ImageRepository, repos ...*api.ImageRepository) (*TagAliasController, *testAliasRepositoryInterface) {
+	updates := &testAliasRepositoryInterface{Current: map[string]*api.ImageRepository{}}
+	store := oscache.NewIndexer(oscache.Indexers{
+		RepositoryIndex: IndexImageRepository,
+		TagAliasIndex:   IndexImageRepositoryByTagAlias,
+	})
+	for _, repo := range repos {
+		store.Add(repo.Namespace+"/"+repo.ID, repo)
+		updates.Current[repo.Namespace+"/"+repo.ID] = repo
+	}
+	return &TagAliasController{
+		RepositoryInterface: updates,
+		NextImageRepository: func() *api.ImageRepository { return next },
+		RepositoryStore:     store,
+	}, updates
+}
+
+func aliasRepositories() (dev, prod *api.ImageRepository) {
+	dev = &api.ImageRepository{
+		TypeMeta: kapi.TypeMeta{ID: "dev", Namespace: "dev"},
+		Tags:     map[string]string{"v2.3": "image2"},
+	}
+	prod = &api.ImageRepository{
+		TypeMeta:   kapi.TypeMeta{ID: "prod", Namespace: "prod"},
+		Tags:       map[string]string{"latest": "image1", "stable": "image1"},
+		TagAliases: map[string]api.TagReference{"latest": {Namespace: "dev", ImageRepository: "dev", Tag: "v2.3"}},
+	}
+	return dev, prod
+}
+
+func TestAliasFollowsChangedTag(t *testing.T) {
+	dev, prod := aliasRepositories()
+	controller, updates := aliasController(dev, dev, prod)
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 1 {
+		t.Fatalf("Expected one update, got %#v", updates.Updated)
+	}
+	updated := updates.Updated[0]
+	if updated.ID != "prod" || updated.Tags["latest"] != "image2" || updated.Tags["stable"] != "image1" {
+		t.Errorf("Unexpected update: %#v", updated)
+	}
+	if prod.Tags["latest"] != "image1" {
+		t.Errorf("Unexpected change of the cached repository: %#v", prod)
+	}
+}
+
+func TestAliasResolvedOnNewAlias(t *testing.T) {
+	dev, prod := aliasRepositories()
+	controller, updates := aliasController(prod, dev, prod)
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 1 || updates.Updated[0].Tags["latest"] != "image2" {
+		t.Errorf("Expected the new alias to be resolved, got %#v", updates.Updated)
+	}
+}
+
+func TestAliasUpToDate(t *testing.T) {
+	dev, prod := aliasRepositories()
+	prod.Tags["latest"] = "image2"
+	controller, updates := aliasController(dev, dev, prod)
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 0 {
+		t.Errorf("Unexpected updates: %#v", updates.Updated)
+	}
+}
+
+func TestAliasDefaultNamespace(t *testing.T) {
+	dev, prod := aliasRepositories()
+	dev.Namespace = "prod"
+	prod.TagAliases["latest"] = api.TagReference{ImageRepository: "dev", Tag: "v2.3"}
+	controller, updates := aliasController(dev, dev, prod)
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 1 || updates.Updated[0].Tags["latest"] != "image2" {
+		t.Errorf("Expected the alias in the namespace of the repository to be resolved, got %#v", updates.Updated)
+	}
+}
+
+func TestAliasKeepsConcurrentTagChanges(t *testing.T) {
+	dev, prod := aliasRepositories()
+	controller, updates := aliasController(dev, dev, prod)
+	// a tag pushed to prod after the cached copy was observed
+	current := *prod
+	current.Tags = map[string]string{"latest": "image1", "stable": "image1", "v1": "image3"}
+	updates.Current["prod/prod"] = &current
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 1 {
+		t.Fatalf("Expected one update, got %#v", updates.Updated)
+	}
+	if tags := updates.Updated[0].Tags; tags["latest"] != "image2" || tags["v1"] != "image3" {
+		t.Errorf("Expected the aliased tag to be set on the current repository, got %#v", tags)
+	}
+}
+
+func TestAliasRetriedOnConflict(t *testing.T) {
+	dev, prod := aliasRepositories()
+	controller, updates := aliasController(dev, dev, prod)
+	updates.Conflicts = maxAliasUpdateAttempts - 1
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 1 || updates.Updated[0].Tags["latest"] != "image2" {
+		t.Errorf("Expected the update to be retried, got %#v", updates.Updated)
+	}
+
+	dev, prod = aliasRepositories()
+	controller, updates = aliasController(dev, dev, prod)
+	updates.Conflicts = maxAliasUpdateAttempts
+
+	controller.HandleImageRepository()
+
+	if len(updates.Updated) != 0 {
+		t.Errorf("Expected the update to be given up, got %#v", updates.Updated)
+	}
+}
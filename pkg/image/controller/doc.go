@@ -0,0 +1,2 @@
+// Package controller contains the controllers which keep images and image repositories up to date.
+package controller
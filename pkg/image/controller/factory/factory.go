@@ -0,0 +1,78 @@
+package factory
+
+import (
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	oscache "github.com/openshift/origin/pkg/client/cache"
+	"github.com/openshift/origin/pkg/dockerregistry"
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/controller"
+)
+
+// ImportControllerFactory can create an ImportController which imports the ImageRepositories held by
+// a store populated from a watch of all repositories.
+type ImportControllerFactory struct {
+	Client   *osclient.Client
+	Interval time.Duration
+	// InsecureRegistries are contacted over plain HTTP rather than HTTPS.
+	InsecureRegistries []string
+}
+
+func (factory *ImportControllerFactory) Create() *controller.ImportController {
+	store := cache.NewStore()
+	cache.NewReflector(&imageRepositoryLW{factory.Client}, &api.ImageRepository{}, store).Run()
+
+	return &controller.ImportController{
+		MappingInterface: factory.Client,
+		RepositoryStore:  store,
+		Registry:         dockerregistry.NewClient(factory.InsecureRegistries...),
+		Interval:         factory.Interval,
+	}
+}
+
+// TagAliasControllerFactory can create a TagAliasController which obtains ImageRepositories from a
+// queue and finds the repositories referenced by aliases in an indexed store, both populated from a
+// single watch of all repositories.
+type TagAliasControllerFactory struct {
+	Client *osclient.Client
+}
+
+func (factory *TagAliasControllerFactory) Create() *controller.TagAliasController {
+	informer := oscache.NewInformer(&imageRepositoryLW{factory.Client}, &api.ImageRepository{}, oscache.Indexers{
+		controller.RepositoryIndex: controller.IndexImageRepository,
+		controller.TagAliasIndex:   controller.IndexImageRepositoryByTagAlias,
+	})
+	queue := cache.NewFIFO()
+	informer.AddQueue(queue, nil)
+	informer.Run()
+
+	return &controller.TagAliasController{
+		RepositoryInterface: factory.Client,
+		NextImageRepository: func() *api.ImageRepository {
+			return queue.Pop().(*api.ImageRepository)
+		},
+		RepositoryStore: informer.Store(),
+	}
+}
+
+// imageRepositoryLW is a ListWatcher for ImageRepositories.
+type imageRepositoryLW struct {
+	client osclient.Interface
+}
+
+// List lists all ImageRepositories.
+func (lw *imageRepositoryLW) List() (runtime.Object, error) {
+	return lw.client.ListImageRepositories(kapi.NewContext(), labels.Everything(), labels.Everything())
+}
+
+// Watch watches all ImageRepositories.
+func (lw *imageRepositoryLW) Watch(resourceVersion string) (watch.Interface, error) {
+	return lw.client.WatchImageRepositories(kapi.NewContext(), labels.Everything(), labels.Everything(), resourceVersion)
+}
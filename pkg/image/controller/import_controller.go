@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// ImportController periodically imports the tags of the ImageRepositories marked for import from the
+// Docker registry hosting their DockerImageRepository. For every tag which points to a different image
+// than the ImageRepository records, the metadata of the image is read from the registry and an
+// ImageRepositoryMapping is created, which registers the image and updates the tag just like a push
+// to an integrated registry does. Tags removed upstream are kept.
+type ImportController struct {
+	MappingInterface mappingInterface
+	// RepositoryStore holds the ImageRepositories to import.
+	RepositoryStore cache.Store
+	// Registry reads tags and images from Docker registries.
+	Registry dockerRegistry
+	// Interval is the time between imports.
+	Interval time.Duration
+}
+
+type mappingInterface interface {
+	CreateImageRepositoryMapping(ctx kapi.Context, mapping *api.ImageRepositoryMapping) error
+}
+
+type dockerRegistry interface {
+	ImageTags(dockerRepo string) (map[string]string, error)
+	Image(dockerRepo, id string) (*docker.Image, error)
+}
+
+// Run imports the tags of the repositories every Interval.
+func (c *ImportController) Run() {
+	go util.Forever(c.HandleRepositories, c.Interval)
+}
+
+// HandleRepositories imports the tags of every repository in the store marked for import once.
+func (c *ImportController) HandleRepositories() {
+	for _, obj := range c.RepositoryStore.List() {
+		repo := obj.(*api.ImageRepository)
+		if !repo.Import || len(repo.DockerImageRepository) == 0 {
+			continue
+		}
+		c.importTags(repo)
+	}
+}
+
+// importTags creates a mapping for every tag of the upstream repository of repo which points to an
+// image the tag of repo doesn't.
+func (c *ImportController) importTags(repo *api.ImageRepository) {
+	tags, err := c.Registry.ImageTags(repo.DockerImageRepository)
+	if err != nil {
+		glog.V(2).Infof("Error getting the tags of imageRepository %s from %s: %v", repo.ID, repo.DockerImageRepository, err)
+		return
+	}
+
+	ctx := kapi.WithNamespace(kapi.NewContext(), repo.Namespace)
+	for tag, id := range tags {
+		if repo.Tags[tag] == id {
+			continue
+		}
+		image, err := c.Registry.Image(repo.DockerImageRepository, id)
+		if err != nil {
+			glog.V(2).Infof("Error getting image %s of imageRepository %s from %s: %v", id, repo.ID, repo.DockerImageRepository, err)
+			continue
+		}
+
+		glog.V(2).Infof("Importing tag %s of imageRepository %s as image %s", tag, repo.ID, id)
+		mapping := &api.ImageRepositoryMapping{
+			TypeMeta:              kapi.TypeMeta{Namespace: repo.Namespace},
+			DockerImageRepository: repo.DockerImageRepository,
+			Image: api.Image{
+				TypeMeta:             kapi.TypeMeta{ID: id},
+				DockerImageReference: repo.DockerImageRepository + ":" + tag,
+				Metadata:             *image,
+			},
+			Tag: tag,
+		}
+		if err := c.MappingInterface.CreateImageRepositoryMapping(ctx, mapping); err != nil {
+			glog.V(2).Infof("Error importing tag %s of imageRepository %s: %v", tag, repo.ID, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/fsouza/go-dockerclient"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+type testMappingInterface struct {
+	Mappings []*api.ImageRepositoryMapping
+}
+
+func (i *testMappingInterface) CreateImageRepositoryMapping(ctx kapi.Context, mapping *api.ImageRepositoryMapping) error {
+	i.Mappings = append(i.Mappings, mapping)
+	return nil
+}
+
+type testDockerRegistry struct {
+	Tags   map[string]string
+	Images map[string]*docker.Image
+}
+
+func (r *testDockerRegistry) ImageTags(dockerRepo string) (map[string]string, error) {
+	if r.Tags == nil {
+		return nil, fmt.Errorf("repository %s not found", dockerRepo)
+	}
+	return r.Tags, nil
+}
+
+func (r *testDockerRegistry) Image(dockerRepo, id string) (*docker.Image, error) {
+	image, ok := r.Images[id]
+	if !ok {
+		return nil, fmt.Errorf("image %s not found", id)
+	}
+	return image, nil
+}
+
+func importController(repo *api.ImageRepository, registry *testDockerRegistry) (*ImportController, *testMappingInterface) {
+	mappings := &testMappingInterface{}
+	store := cache.NewStore()
+	store.Add(repo.ID, repo)
+	return &ImportController{
+		MappingInterface: mappings,
+		RepositoryStore:  store,
+		Registry:         registry,
+	}, mappings
+}
+
+func importedRepository() *api.ImageRepository {
+	return &api.ImageRepository{
+		TypeMeta:              kapi.TypeMeta{ID: "repo", Namespace: "test"},
+		DockerImageRepository: "registry:5000/test/repo",
+		Import:                true,
+		Tags:                  map[string]string{"stable": "abc"},
+	}
+}
+
+func TestImportChangedTags(t *testing.T) {
+	registry := &testDockerRegistry{
+		Tags: map[string]string{"stable": "abc", "latest": "def"},
+		Images: map[string]*docker.Image{
+			"abc": {ID: "abc"},
+			"def": {ID: "def", Config: &docker.Config{Cmd: []string{"ls"}}},
+		},
+	}
+	controller, mappings := importController(importedRepository(), registry)
+
+	controller.HandleRepositories()
+
+	if len(mappings.Mappings) != 1 {
+		t.Fatalf("Expected one mapping, got %#v", mappings.Mappings)
+	}
+	mapping := mappings.Mappings[0]
+	if mapping.Tag != "latest" || mapping.Image.ID != "def" || mapping.Namespace != "test" {
+		t.Errorf("Unexpected mapping: %#v", mapping)
+	}
+	if e, a := "registry:5000/test/repo", mapping.DockerImageRepository; e != a {
+		t.Errorf("Expected repository %s, got %s", e, a)
+	}
+	if mapping.Image.Metadata.Config == nil || mapping.Image.Metadata.Config.Cmd[0] != "ls" {
+		t.Errorf("Expected the image metadata to be imported, got %#v", mapping.Image.Metadata)
+	}
+}
+
+func TestImportSkipsUnmarkedRepositories(t *testing.T) {
+	repo := importedRepository()
+	repo.Import = false
+	registry := &testDockerRegistry{
+		Tags:   map[string]string{"latest": "def"},
+		Images: map[string]*docker.Image{"def": {ID: "def"}},
+	}
+	controller, mappings := importController(repo, registry)
+
+	controller.HandleRepositories()
+
+	if len(mappings.Mappings) != 0 {
+		t.Errorf("Unexpected mappings: %#v", mappings.Mappings)
+	}
+}
+
+func TestImportMissingImage(t *testing.T) {
+	registry := &testDockerRegistry{
+		Tags:   map[string]string{"latest": "def", "v2": "ghi"},
+		Images: map[string]*docker.Image{"ghi": {ID: "ghi"}},
+	}
+	controller, mappings := importController(importedRepository(), registry)
+
+	controller.HandleRepositories()
+
+	if len(mappings.Mappings) != 1 || mappings.Mappings[0].Tag != "v2" {
+		t.Errorf("Expected only the tag with an available image to be imported, got %#v", mappings.Mappings)
+	}
+}
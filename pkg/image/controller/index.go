@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+const (
+	// RepositoryIndex is the name of the index of ImageRepositories by their namespace and ID.
+	RepositoryIndex = "repository"
+	// TagAliasIndex is the name of the index of ImageRepositories by the namespace and ID of the
+	// repositories their tag aliases reference.
+	TagAliasIndex = "tagAlias"
+)
+
+// IndexImageRepository returns the namespace and ID of an ImageRepository.
+func IndexImageRepository(obj interface{}) []string {
+	repo := obj.(*api.ImageRepository)
+	return []string{repositoryKey(repo.Namespace, repo.ID)}
+}
+
+// IndexImageRepositoryByTagAlias returns the namespaces and IDs of the ImageRepositories referenced by
+// the tag aliases of an ImageRepository.
+func IndexImageRepositoryByTagAlias(obj interface{}) []string {
+	repo := obj.(*api.ImageRepository)
+	keys := []string{}
+	for _, ref := range repo.TagAliases {
+		keys = append(keys, repositoryKey(referenceNamespace(repo, ref), ref.ImageRepository))
+	}
+	return keys
+}
+
+// repositoryKey returns the key of the ImageRepository with the given namespace and ID in the indexes.
+func repositoryKey(namespace, id string) string {
+	return namespace + "/" + id
+}
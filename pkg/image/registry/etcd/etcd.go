@@ -1,11 +1,13 @@
 package etcd
 
 import (
-	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
+	"strings"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	etcderr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	kubeetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
@@ -22,6 +24,9 @@ const (
 	ImagePath string = "/images"
 	// ImageRepositoriesPath is the path to imageRepository resources in etcd
 	ImageRepositoriesPath string = "/imageRepositories"
+	// DockerImageRepositoryIndexPath is the path to the index of imageRepository resources by their
+	// DockerImageRepository in etcd
+	DockerImageRepositoryIndexPath string = "/imageRepositoryIndex/dockerImageRepository"
 )
 
 // Etcd implements ImageRegistry and ImageRepositoryRegistry backed by etcd.
@@ -88,7 +93,13 @@ func (r *Etcd) CreateImage(ctx kapi.Context, image *api.Image) error {
 
 // UpdateImage updates an existing image
 func (r *Etcd) UpdateImage(ctx kapi.Context, image *api.Image) error {
-	return errors.New("not supported")
+	key, err := makeImageKey(ctx, image.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.SetObj(key, image)
+	return etcderr.InterpretUpdateError(err, "image", image.ID)
 }
 
 // DeleteImage deletes an existing image
@@ -193,22 +204,30 @@ func (r *Etcd) WatchImageRepositories(ctx kapi.Context, resourceVersion string,
 	})
 }
 
-// CreateImageRepository registers the given ImageRepository.
+// CreateImageRepository registers the given ImageRepository. It fails if another ImageRepository
+// already uses the DockerImageRepository of repo.
 func (r *Etcd) CreateImageRepository(ctx kapi.Context, repo *api.ImageRepository) error {
 	key, err := makeImageRepositoryKey(ctx, repo.ID)
 	if err != nil {
 		return err
 	}
+	if err := r.indexDockerImageRepository(ctx, repo); err != nil {
+		return err
+	}
 	err = r.CreateObj(key, repo, 0)
 	return etcderr.InterpretCreateError(err, "imageRepository", repo.ID)
 }
 
 // UpdateImageRepository replaces an existing ImageRepository in the registry with the given ImageRepository.
+// It fails if another ImageRepository already uses the DockerImageRepository of repo.
 func (r *Etcd) UpdateImageRepository(ctx kapi.Context, repo *api.ImageRepository) error {
 	key, err := makeImageRepositoryKey(ctx, repo.ID)
 	if err != nil {
 		return err
 	}
+	if err := r.indexDockerImageRepository(ctx, repo); err != nil {
+		return err
+	}
 	err = r.SetObj(key, repo)
 	return etcderr.InterpretUpdateError(err, "imageRepository", repo.ID)
 }
@@ -222,3 +241,112 @@ func (r *Etcd) DeleteImageRepository(ctx kapi.Context, id string) error {
 	err = r.Delete(key, false)
 	return etcderr.InterpretDeleteError(err, "imageRepository", id)
 }
+
+// FindImageRepository retrieves the ImageRepository whose DockerImageRepository is dockerRepo. If ctx
+// has a namespace, only an ImageRepository in that namespace is found. ImageRepositories which aren't
+// indexed yet, such as those created before the index existed, are found by listing all of them and
+// are indexed once found.
+func (r *Etcd) FindImageRepository(ctx kapi.Context, dockerRepo string) (*api.ImageRepository, error) {
+	repo, err := r.findIndexedImageRepository(ctx, dockerRepo)
+	if err == nil || !kerrors.IsNotFound(err) || len(dockerRepo) == 0 {
+		return repo, err
+	}
+
+	repos, err := r.ListImageRepositories(ctx, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for i := range repos.Items {
+		repo := &repos.Items[i]
+		if repo.DockerImageRepository != dockerRepo {
+			continue
+		}
+		if err := r.indexDockerImageRepository(kapi.WithNamespace(ctx, repo.Namespace), repo); err != nil {
+			glog.V(4).Infof("Unable to index imageRepository %s/%s: %v", repo.Namespace, repo.ID, err)
+		}
+		return repo, nil
+	}
+	return nil, kerrors.NewNotFound("imageRepository", dockerRepo)
+}
+
+// findIndexedImageRepository retrieves the ImageRepository the index entry for dockerRepo points to.
+func (r *Etcd) findIndexedImageRepository(ctx kapi.Context, dockerRepo string) (*api.ImageRepository, error) {
+	notFound := kerrors.NewNotFound("imageRepository", dockerRepo)
+	if len(dockerRepo) == 0 {
+		return nil, notFound
+	}
+
+	response, err := r.Client.Get(makeDockerImageRepositoryIndexKey(dockerRepo), false, false)
+	if err != nil {
+		if tools.IsEtcdNotFound(err) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	namespace, id := parseDockerImageRepositoryIndexValue(response.Node.Value)
+	if ns, ok := kapi.NamespaceFrom(ctx); ok && len(ns) > 0 && ns != namespace {
+		return nil, notFound
+	}
+
+	repo, err := r.GetImageRepository(kapi.WithNamespace(ctx, namespace), id)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, notFound
+		}
+		return nil, err
+	}
+	// the index is not updated when repositories are deleted or renamed, so verify the entry
+	if repo.DockerImageRepository != dockerRepo {
+		return nil, notFound
+	}
+	return repo, nil
+}
+
+// indexDockerImageRepository points the index entry for the DockerImageRepository of repo to repo. It
+// fails with a conflict if the entry points to another ImageRepository which still uses the name.
+// Entries left behind by deleted or renamed repositories are taken over.
+func (r *Etcd) indexDockerImageRepository(ctx kapi.Context, repo *api.ImageRepository) error {
+	if len(repo.DockerImageRepository) == 0 {
+		return nil
+	}
+	namespace, _ := kapi.NamespaceFrom(ctx)
+	key := makeDockerImageRepositoryIndexKey(repo.DockerImageRepository)
+	value := makeDockerImageRepositoryIndexValue(namespace, repo.ID)
+
+	_, err := r.Client.Create(key, value, 0)
+	if err == nil || !tools.IsEtcdNodeExist(err) {
+		return err
+	}
+
+	response, err := r.Client.Get(key, false, false)
+	if err != nil {
+		return err
+	}
+	if response.Node.Value == value {
+		return nil
+	}
+	if owner, err := r.findIndexedImageRepository(kapi.NewContext(), repo.DockerImageRepository); err == nil {
+		return kerrors.NewConflict("imageRepository", repo.ID, fmt.Errorf("dockerImageRepository %s is already used by imageRepository %s/%s", repo.DockerImageRepository, owner.Namespace, owner.ID))
+	} else if !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	// CompareAndSwap fails if another repository claimed the stale entry in the meantime
+	_, err = r.Client.CompareAndSwap(key, value, 0, "", response.Node.ModifiedIndex)
+	return err
+}
+
+func makeDockerImageRepositoryIndexKey(dockerRepo string) string {
+	return DockerImageRepositoryIndexPath + "/" + url.QueryEscape(dockerRepo)
+}
+
+func makeDockerImageRepositoryIndexValue(namespace, id string) string {
+	return namespace + "/" + id
+}
+
+func parseDockerImageRepositoryIndexValue(value string) (namespace, id string) {
+	if i := strings.Index(value, "/"); i >= 0 {
+		return value[:i], value[i+1:]
+	}
+	return "", value
+}
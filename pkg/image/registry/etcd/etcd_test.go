@@ -2,6 +2,7 @@ package etcd
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 	"strconv"
 	"testing"
@@ -54,6 +55,9 @@ func makeTestDefaultImageRepositoriesKey(id string) string {
 func makeTestDefaultImageRepositoriesListKey() string {
 	return makeTestImageRepositoriesListKey(kapi.NamespaceDefault)
 }
+func makeTestDockerImageRepositoryIndexKey(dockerRepo string) string {
+	return "/imageRepositoryIndex/dockerImageRepository/" + url.QueryEscape(dockerRepo)
+}
 
 func NewTestEtcd(client tools.EtcdClient) *Etcd {
 	return New(tools.EtcdHelper{client, latest.Codec, tools.RuntimeVersionAdapter{latest.ResourceVersioner}})
@@ -269,10 +273,24 @@ func TestEtcdCreateImageAlreadyExists(t *testing.T) {
 
 func TestEtcdUpdateImage(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+
+	resp, _ := fakeClient.Set(makeTestDefaultImageKey("foo"), runtime.EncodeOrDie(latest.Codec, &api.Image{TypeMeta: kapi.TypeMeta{ID: "foo"}}), 0)
 	registry := NewTestEtcd(fakeClient)
-	err := registry.UpdateImage(kapi.NewDefaultContext(), &api.Image{})
-	if err == nil {
-		t.Error("Unexpected non-error")
+	err := registry.UpdateImage(kapi.NewDefaultContext(), &api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "foo", ResourceVersion: strconv.FormatUint(resp.Node.ModifiedIndex, 10)},
+		DockerImageReference: "some/repo:latest",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	image, err := registry.GetImage(kapi.NewDefaultContext(), "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.DockerImageReference != "some/repo:latest" {
+		t.Errorf("Unexpected image: %#v", image)
 	}
 }
 
@@ -627,6 +645,117 @@ func TestEtcdCreateImageRepositoryAlreadyExists(t *testing.T) {
 	}
 }
 
+func TestEtcdCreateImageRepositoryIndexesDockerImageRepository(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	registry := NewTestEtcd(fakeClient)
+	err := registry.CreateImageRepository(kapi.NewDefaultContext(), &api.ImageRepository{
+		TypeMeta:              kapi.TypeMeta{ID: "foo"},
+		DockerImageRepository: "registry:5000/c/d",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo, err := registry.FindImageRepository(kapi.NewContext(), "registry:5000/c/d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.ID != "foo" {
+		t.Errorf("Unexpected repo: %#v", repo)
+	}
+
+	fakeClient.ExpectNotFoundGet(makeTestImageRepositoriesListKey("other"))
+	if _, err := registry.FindImageRepository(kapi.WithNamespace(kapi.NewContext(), "other"), "registry:5000/c/d"); !errors.IsNotFound(err) {
+		t.Errorf("Expected 'not found' error from another namespace, got %#v", err)
+	}
+	fakeClient.ExpectNotFoundGet(makeTestDockerImageRepositoryIndexKey("registry:5000/c/e"))
+	fakeClient.ExpectNotFoundGet(makeTestImageRepositoriesListKey(""))
+	if _, err := registry.FindImageRepository(kapi.NewContext(), "registry:5000/c/e"); !errors.IsNotFound(err) {
+		t.Errorf("Expected 'not found' error for an unknown repository, got %#v", err)
+	}
+}
+
+func TestEtcdFindImageRepositoryUnindexed(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	fakeClient.ExpectNotFoundGet(makeTestDockerImageRepositoryIndexKey("c/d"))
+	fakeClient.Data[makeTestDefaultImageRepositoriesListKey()] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{
+						Value: runtime.EncodeOrDie(latest.Codec, &api.ImageRepository{
+							TypeMeta:              kapi.TypeMeta{ID: "foo", Namespace: kapi.NamespaceDefault},
+							DockerImageRepository: "c/d",
+						}),
+					},
+				},
+			},
+		},
+		E: nil,
+	}
+	registry := NewTestEtcd(fakeClient)
+
+	repo, err := registry.FindImageRepository(kapi.NewDefaultContext(), "c/d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.ID != "foo" {
+		t.Errorf("Unexpected repo: %#v", repo)
+	}
+
+	response, err := fakeClient.Get(makeTestDockerImageRepositoryIndexKey("c/d"), false, false)
+	if err != nil {
+		t.Fatalf("Expected the repository to be indexed, got %v", err)
+	}
+	if e, a := kapi.NamespaceDefault+"/foo", response.Node.Value; e != a {
+		t.Errorf("Expected index entry %s, got %s", e, a)
+	}
+}
+
+func TestEtcdCreateImageRepositoryDuplicateDockerImageRepository(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	registry := NewTestEtcd(fakeClient)
+	ctx := kapi.NewDefaultContext()
+	if err := registry.CreateImageRepository(ctx, &api.ImageRepository{TypeMeta: kapi.TypeMeta{ID: "foo"}, DockerImageRepository: "c/d"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := registry.CreateImageRepository(kapi.WithNamespace(kapi.NewContext(), "other"), &api.ImageRepository{TypeMeta: kapi.TypeMeta{ID: "bar"}, DockerImageRepository: "c/d"})
+	if !errors.IsConflict(err) {
+		t.Errorf("Expected 'conflict' error, got %#v", err)
+	}
+}
+
+func TestEtcdCreateImageRepositoryStaleIndex(t *testing.T) {
+	fakeClient := tools.NewFakeEtcdClient(t)
+	fakeClient.TestIndex = true
+	// the index entry of a deleted repository
+	fakeClient.Set(makeTestDockerImageRepositoryIndexKey("c/d"), kapi.NamespaceDefault+"/foo", 0)
+	fakeClient.Data[makeTestDefaultImageRepositoriesKey("foo")] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	registry := NewTestEtcd(fakeClient)
+
+	err := registry.CreateImageRepository(kapi.NewDefaultContext(), &api.ImageRepository{TypeMeta: kapi.TypeMeta{ID: "bar"}, DockerImageRepository: "c/d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo, err := registry.FindImageRepository(kapi.NewDefaultContext(), "c/d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.ID != "bar" {
+		t.Errorf("Unexpected repo: %#v", repo)
+	}
+}
+
 func TestEtcdUpdateImageRepository(t *testing.T) {
 	fakeClient := tools.NewFakeEtcdClient(t)
 	fakeClient.TestIndex = true
@@ -60,6 +60,8 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	}
 
 	image.CreationTimestamp = util.Now()
+	// signatures are only attached through ImageSignatures
+	image.Signatures = nil
 
 	if errs := validation.ValidateImage(image); len(errs) > 0 {
 		return nil, errors.NewInvalid("image", image.ID, errs)
@@ -0,0 +1,181 @@
+package imageprune
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/image"
+	"github.com/openshift/origin/pkg/image/registry/imagerepository"
+)
+
+// DefaultTagHistoryRetention is how long an image stays referenced by the tag history of a
+// repository unless an ImagePrune says otherwise.
+const DefaultTagHistoryRetention = 7 * 24 * time.Hour
+
+// REST implements the RESTStorage interface for ImagePrunes. It only supports the Create method.
+type REST struct {
+	imageRegistry               image.Registry
+	imageRepositoryRegistry     imagerepository.Registry
+	replicationControllerClient controllerLister
+	// now returns the current time. It defaults to util.Now.
+	now func() util.Time
+}
+
+type controllerLister interface {
+	ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error)
+}
+
+// NewREST returns a new REST which prunes images of imageRegistry which are not referenced by the
+// repositories of imageRepositoryRegistry or the replication controllers listed with the client.
+func NewREST(imageRegistry image.Registry, imageRepositoryRegistry imagerepository.Registry, replicationControllerClient controllerLister) apiserver.RESTStorage {
+	return &REST{
+		imageRegistry:               imageRegistry,
+		imageRepositoryRegistry:     imageRepositoryRegistry,
+		replicationControllerClient: replicationControllerClient,
+		now:                         util.Now,
+	}
+}
+
+// New returns a new ImagePrune for use with Create.
+func (s *REST) New() runtime.Object {
+	return &api.ImagePrune{}
+}
+
+// List is not supported.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.NewNotFound("imagePrune", "list")
+}
+
+// Get is not supported.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return nil, errors.NewNotFound("imagePrune", id)
+}
+
+// Create finds the unreferenced images of the namespace of ctx, deletes them if the prune is
+// confirmed, and returns the prune with the IDs of the images.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	prune, ok := obj.(*api.ImagePrune)
+	if !ok {
+		return nil, fmt.Errorf("not an image prune: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &prune.TypeMeta) {
+		return nil, errors.NewConflict("imagePrune", prune.Namespace, fmt.Errorf("ImagePrune.Namespace does not match the provided context"))
+	}
+	if prune.TagHistoryRetentionSeconds < 0 {
+		return nil, errors.NewInvalid("imagePrune", prune.ID, errors.ErrorList{
+			errors.NewFieldInvalid("tagHistoryRetentionSeconds", prune.TagHistoryRetentionSeconds),
+		})
+	}
+	retention := DefaultTagHistoryRetention
+	if prune.TagHistoryRetentionSeconds > 0 {
+		retention = time.Duration(prune.TagHistoryRetentionSeconds) * time.Second
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		unreferenced, err := s.unreferencedImages(ctx, retention)
+		if err != nil {
+			return nil, err
+		}
+
+		prune.Images = []string{}
+		for _, image := range unreferenced {
+			if prune.Confirm {
+				glog.V(2).Infof("Pruning image %s (%s)", image.ID, image.DockerImageReference)
+				if err := s.imageRegistry.DeleteImage(ctx, image.ID); err != nil && !errors.IsNotFound(err) {
+					return nil, err
+				}
+			}
+			prune.Images = append(prune.Images, image.ID)
+		}
+		sort.Strings(prune.Images)
+		return prune, nil
+	}), nil
+}
+
+// unreferencedImages returns the images of the namespace of ctx which no tag of a repository in any
+// namespace points to or pointed to within retention, and which no replication controller runs.
+func (s *REST) unreferencedImages(ctx kapi.Context, retention time.Duration) ([]api.Image, error) {
+	images, err := s.imageRegistry.ListImages(ctx, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	// images are referenced across namespaces, as tags can be copied from other namespaces
+	allNamespaces := kapi.NewContext()
+	repos, err := s.imageRepositoryRegistry.ListImageRepositories(allNamespaces, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	controllers, err := s.replicationControllerClient.ListReplicationControllers(allNamespaces, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := util.StringSet{}
+	since := s.now().Add(-retention)
+	for _, repo := range repos.Items {
+		for _, imageID := range repo.Tags {
+			referenced.Insert(imageID)
+		}
+		for _, history := range repo.TagHistory {
+			// an event is superseded when the next newer event was created
+			superseded := time.Time{}
+			for _, event := range history {
+				if !superseded.IsZero() && superseded.Before(since) {
+					break
+				}
+				referenced.Insert(event.ImageID)
+				superseded = event.Created.Time
+			}
+		}
+	}
+
+	pullSpecs := []string{}
+	for _, controller := range controllers.Items {
+		for _, container := range controller.DesiredState.PodTemplate.DesiredState.Manifest.Containers {
+			pullSpecs = append(pullSpecs, container.Image)
+		}
+	}
+
+	unreferenced := []api.Image{}
+	for _, image := range images.Items {
+		if referenced.Has(image.ID) || runsImage(pullSpecs, &image) {
+			continue
+		}
+		unreferenced = append(unreferenced, image)
+	}
+	return unreferenced, nil
+}
+
+// runsImage returns true if any of the pull specs refers to image, by its reference or its ID.
+func runsImage(pullSpecs []string, image *api.Image) bool {
+	for _, spec := range pullSpecs {
+		if spec == image.DockerImageReference {
+			return true
+		}
+		if ref, err := api.ParseDockerImageReference(spec); err == nil && (ref.Tag == image.ID || ref.ID == image.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update is not supported.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, fmt.Errorf("ImagePrunes may not be changed.")
+}
+
+// Delete is not supported.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.NewNotFound("imagePrune", id)
+}
@@ -0,0 +1,135 @@
+package imageprune
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/test"
+)
+
+// testImageRegistry records the images deleted from the embedded registry.
+type testImageRegistry struct {
+	*test.ImageRegistry
+	deleted []string
+}
+
+func (r *testImageRegistry) DeleteImage(ctx kapi.Context, id string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type testControllerLister struct {
+	controllers []kapi.ReplicationController
+}
+
+func (l *testControllerLister) ListReplicationControllers(ctx kapi.Context, selector labels.Selector) (*kapi.ReplicationControllerList, error) {
+	return &kapi.ReplicationControllerList{Items: l.controllers}, nil
+}
+
+func controllerRunning(image string) kapi.ReplicationController {
+	controller := kapi.ReplicationController{}
+	controller.DesiredState.PodTemplate.DesiredState.Manifest.Containers = []kapi.Container{{Name: "container1", Image: image}}
+	return controller
+}
+
+func TestPruneImages(t *testing.T) {
+	now := util.Now()
+	testCases := map[string]struct {
+		prune   api.ImagePrune
+		pruned  []string
+		deleted []string
+	}{
+		"dry run": {
+			prune:  api.ImagePrune{},
+			pruned: []string{"old", "unused"},
+		},
+		"confirmed": {
+			prune:   api.ImagePrune{Confirm: true},
+			pruned:  []string{"old", "unused"},
+			deleted: []string{"old", "unused"},
+		},
+		"short retention": {
+			prune:  api.ImagePrune{TagHistoryRetentionSeconds: 60},
+			pruned: []string{"old", "recent", "unused"},
+		},
+		"long retention": {
+			prune:  api.ImagePrune{TagHistoryRetentionSeconds: 90 * 24 * 60 * 60},
+			pruned: []string{"unused"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		imageRegistry := &testImageRegistry{ImageRegistry: test.NewImageRegistry()}
+		imageRegistry.Images = &api.ImageList{
+			Items: []api.Image{
+				{TypeMeta: kapi.TypeMeta{ID: "tagged"}, DockerImageReference: "registry/ns/repo:tagged"},
+				{TypeMeta: kapi.TypeMeta{ID: "recent"}, DockerImageReference: "registry/ns/repo:recent"},
+				{TypeMeta: kapi.TypeMeta{ID: "old"}, DockerImageReference: "registry/ns/repo:old"},
+				{TypeMeta: kapi.TypeMeta{ID: "running"}, DockerImageReference: "registry/ns/repo:running"},
+				{TypeMeta: kapi.TypeMeta{ID: "runningByRef"}, DockerImageReference: "registry/ns/repo:stable"},
+				{TypeMeta: kapi.TypeMeta{ID: "unused"}, DockerImageReference: "registry/ns/repo:unused"},
+			},
+		}
+		repoRegistry := test.NewImageRepositoryRegistry()
+		repoRegistry.ImageRepositories = &api.ImageRepositoryList{
+			Items: []api.ImageRepository{
+				{
+					TypeMeta: kapi.TypeMeta{ID: "repo"},
+					Tags:     map[string]string{"latest": "tagged"},
+					TagHistory: map[string][]api.TagEvent{
+						"latest": {
+							{ImageID: "tagged", Created: util.Time{Time: now.Add(-time.Hour)}},
+							{ImageID: "recent", Created: util.Time{Time: now.Add(-30 * 24 * time.Hour)}},
+							{ImageID: "old", Created: util.Time{Time: now.Add(-60 * 24 * time.Hour)}},
+						},
+					},
+				},
+			},
+		}
+		storage := &REST{
+			imageRegistry:           imageRegistry,
+			imageRepositoryRegistry: repoRegistry,
+			replicationControllerClient: &testControllerLister{controllers: []kapi.ReplicationController{
+				controllerRunning("registry/ns/repo:running"),
+				controllerRunning("registry/ns/repo:stable"),
+			}},
+			now: func() util.Time { return now },
+		}
+
+		channel, err := storage.Create(kapi.NewDefaultContext(), &testCase.prune)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		result, ok := (<-channel).(*api.ImagePrune)
+		if !ok {
+			t.Fatalf("%s: expected image prune, got %#v", name, result)
+		}
+		if e, a := testCase.pruned, result.Images; !reflect.DeepEqual(e, a) {
+			t.Errorf("%s: expected pruned images %v, got %v", name, e, a)
+		}
+		if e, a := testCase.deleted, imageRegistry.deleted; !reflect.DeepEqual(e, a) {
+			t.Errorf("%s: expected deleted images %v, got %v", name, e, a)
+		}
+	}
+}
+
+func TestPruneImagesNegativeRetention(t *testing.T) {
+	storage := &REST{
+		imageRegistry:               &testImageRegistry{ImageRegistry: test.NewImageRegistry()},
+		imageRepositoryRegistry:     test.NewImageRepositoryRegistry(),
+		replicationControllerClient: &testControllerLister{},
+		now:                         util.Now,
+	}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.ImagePrune{TagHistoryRetentionSeconds: -1})
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected 'invalid' error, got %#v", err)
+	}
+}
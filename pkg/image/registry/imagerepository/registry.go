@@ -14,6 +14,8 @@ type Registry interface {
 	ListImageRepositories(ctx kapi.Context, selector labels.Selector) (*api.ImageRepositoryList, error)
 	// GetImageRepository retrieves a specific image repository.
 	GetImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error)
+	// FindImageRepository retrieves the image repository with the given Docker image repository.
+	FindImageRepository(ctx kapi.Context, dockerRepo string) (*api.ImageRepository, error)
 	// WatchImageRepositories watches for new/changed/deleted image repositories.
 	WatchImageRepositories(ctx kapi.Context, resourceVersion string, filter func(repo *api.ImageRepository) bool) (watch.Interface, error)
 	// CreateImageRepository creates a new image repository.
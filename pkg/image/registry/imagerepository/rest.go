@@ -14,6 +14,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/api/validation"
 )
 
 // REST implements the RESTStorage interface in terms of an Registry.
@@ -31,13 +32,22 @@ func (s *REST) New() runtime.Object {
 	return &api.ImageRepository{}
 }
 
-// List retrieves a list of ImageRepositories that match selector.
+// List retrieves a list of ImageRepositories that match selector and fields.
 func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
 	imageRepositories, err := s.registry.ListImageRepositories(ctx, selector)
 	if err != nil {
 		return nil, err
 	}
-	return imageRepositories, err
+
+	filtered := *imageRepositories
+	filtered.Items = []api.ImageRepository{}
+	for _, repo := range imageRepositories.Items {
+		if fields == nil || fields.Matches(imageRepositoryToSelectableFields(&repo)) {
+			UpdateStatus(&repo)
+			filtered.Items = append(filtered.Items, repo)
+		}
+	}
+	return &filtered, nil
 }
 
 // Get retrieves an ImageRepository by id.
@@ -46,18 +56,37 @@ func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
 	if err != nil {
 		return nil, err
 	}
+	UpdateStatus(repo)
 	return repo, nil
 }
 
 // Watch begins watching for new, changed, or deleted ImageRepositories.
 func (s *REST) Watch(ctx kapi.Context, label, field labels.Selector, resourceVersion string) (watch.Interface, error) {
-	return s.registry.WatchImageRepositories(ctx, resourceVersion, func(repo *api.ImageRepository) bool {
-		fields := labels.Set{
-			"ID": repo.ID,
-			"DockerImageRepository": repo.DockerImageRepository,
-		}
-		return label.Matches(labels.Set(repo.Labels)) && field.Matches(fields)
+	w, err := s.registry.WatchImageRepositories(ctx, resourceVersion, func(repo *api.ImageRepository) bool {
+		return label.Matches(labels.Set(repo.Labels)) && field.Matches(imageRepositoryToSelectableFields(repo))
 	})
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+		if repo, ok := event.Object.(*api.ImageRepository); ok {
+			UpdateStatus(repo)
+		}
+		return event, true
+	}), nil
+}
+
+// imageRepositoryToSelectableFields returns the fields of repo that list and watch field selectors
+// match against. The JSON field names are accepted as well as the Go field names.
+func imageRepositoryToSelectableFields(repo *api.ImageRepository) labels.Set {
+	return labels.Set{
+		"ID":                    repo.ID,
+		"id":                    repo.ID,
+		"Namespace":             repo.Namespace,
+		"namespace":             repo.Namespace,
+		"DockerImageRepository": repo.DockerImageRepository,
+		"dockerImageRepository": repo.DockerImageRepository,
+	}
 }
 
 // Create registers the given ImageRepository.
@@ -73,10 +102,16 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	if len(repo.ID) == 0 {
 		repo.ID = uuid.NewUUID().String()
 	}
+	if errs := validation.ValidateImageRepository(repo); len(errs) > 0 {
+		return nil, errors.NewInvalid("imageRepository", repo.ID, errs)
+	}
 
 	if repo.Tags == nil {
 		repo.Tags = make(map[string]string)
 	}
+	updateTagHistory(repo, nil, api.TagEventSourceUpdate)
+	// the status is computed when the repository is read
+	repo.Status = api.ImageRepositoryStatus{}
 
 	repo.CreationTimestamp = util.Now()
 
@@ -100,12 +135,23 @@ func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	if !kapi.ValidNamespace(ctx, &repo.TypeMeta) {
 		return nil, errors.NewConflict("imageRepository", repo.Namespace, fmt.Errorf("ImageRepository.Namespace does not match the provided context"))
 	}
+	if errs := validation.ValidateImageRepository(repo); len(errs) > 0 {
+		return nil, errors.NewInvalid("imageRepository", repo.ID, errs)
+	}
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err := s.registry.UpdateImageRepository(ctx, repo)
+		// the tag history is maintained by the server; record the tags changed by the update on the
+		// stored history rather than accepting the history of the client
+		previous, err := s.registry.GetImageRepository(ctx, repo.ID)
 		if err != nil {
 			return nil, err
 		}
+		updateTagHistory(repo, previous, api.TagEventSourceUpdate)
+		repo.Status = api.ImageRepositoryStatus{}
+
+		if err := s.registry.UpdateImageRepository(ctx, repo); err != nil {
+			return nil, err
+		}
 		return s.Get(ctx, repo.ID)
 	}), nil
 }
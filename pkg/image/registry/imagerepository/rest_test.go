@@ -10,6 +10,7 @@ import (
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/openshift/origin/pkg/image/api"
 	"github.com/openshift/origin/pkg/image/registry/test"
 )
@@ -119,6 +120,113 @@ func TestListImageRepositoriesPopulatedList(t *testing.T) {
 	}
 }
 
+func TestListImageRepositoriesFieldSelector(t *testing.T) {
+	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
+	mockRepositoryRegistry.ImageRepositories = &api.ImageRepositoryList{
+		Items: []api.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "foo", Namespace: "one"},
+				DockerImageRepository: "openshift/ruby-19-centos",
+			},
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "bar", Namespace: "two"},
+				DockerImageRepository: "openshift/ruby-19-centos",
+			},
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "baz", Namespace: "two"},
+				DockerImageRepository: "openshift/origin",
+			},
+		},
+	}
+
+	storage := REST{
+		registry: mockRepositoryRegistry,
+	}
+
+	testCases := map[string][]string{
+		"dockerImageRepository=openshift/ruby-19-centos":               {"foo", "bar"},
+		"DockerImageRepository=openshift/origin":                       {"baz"},
+		"namespace=two":                                                {"bar", "baz"},
+		"namespace=two,dockerImageRepository=openshift/ruby-19-centos": {"bar"},
+		"namespace=three":                                              {},
+	}
+	for selector, expected := range testCases {
+		field, err := labels.ParseSelector(selector)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", selector, err)
+		}
+		list, err := storage.List(kapi.NewContext(), labels.Everything(), field)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", selector, err)
+			continue
+		}
+		actual := []string{}
+		for _, repo := range list.(*api.ImageRepositoryList).Items {
+			actual = append(actual, repo.ID)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: expected %v, got %v", selector, expected, actual)
+		}
+	}
+
+	if e, a := 3, len(mockRepositoryRegistry.ImageRepositories.Items); e != a {
+		t.Errorf("Expected the registry list to be unchanged, got %d items", a)
+	}
+}
+
+type watchFilterRegistry struct {
+	*test.ImageRepositoryRegistry
+	filter func(repo *api.ImageRepository) bool
+}
+
+func (r *watchFilterRegistry) WatchImageRepositories(ctx kapi.Context, resourceVersion string, filter func(repo *api.ImageRepository) bool) (watch.Interface, error) {
+	r.filter = filter
+	return nil, nil
+}
+
+func TestWatchImageRepositoriesFieldSelector(t *testing.T) {
+	registry := &watchFilterRegistry{ImageRepositoryRegistry: test.NewImageRepositoryRegistry()}
+	storage := REST{registry: registry}
+
+	field, err := labels.ParseSelector("namespace=one,dockerImageRepository=openshift/ruby-19-centos")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := storage.Watch(kapi.NewContext(), labels.Everything(), field, "1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		repo    api.ImageRepository
+		matches bool
+	}{
+		{
+			repo: api.ImageRepository{
+				TypeMeta:              kapi.TypeMeta{ID: "foo", Namespace: "one"},
+				DockerImageRepository: "openshift/ruby-19-centos",
+			},
+			matches: true,
+		},
+		{
+			repo: api.ImageRepository{
+				TypeMeta:              kapi.TypeMeta{ID: "foo", Namespace: "two"},
+				DockerImageRepository: "openshift/ruby-19-centos",
+			},
+		},
+		{
+			repo: api.ImageRepository{
+				TypeMeta:              kapi.TypeMeta{ID: "foo", Namespace: "one"},
+				DockerImageRepository: "openshift/origin",
+			},
+		},
+	}
+	for i, testCase := range testCases {
+		if e, a := testCase.matches, registry.filter(&testCase.repo); e != a {
+			t.Errorf("%d: expected match %t, got %t", i, e, a)
+		}
+	}
+}
+
 func TestCreateImageRepositoryBadObject(t *testing.T) {
 	storage := REST{}
 
@@ -237,6 +345,46 @@ func TestUpdateImageRepositoryOK(t *testing.T) {
 	}
 }
 
+func TestUpdateImageRepositoryRecordsTagHistory(t *testing.T) {
+	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
+	mockRepositoryRegistry.ImageRepository = &api.ImageRepository{
+		TypeMeta: kapi.TypeMeta{ID: "bar"},
+		Tags:     map[string]string{"latest": "image1", "stable": "image1"},
+		TagHistory: map[string][]api.TagEvent{
+			"latest": {{ImageID: "image1", SetBy: api.TagEventSourceMapping}},
+			"stable": {{ImageID: "image1", SetBy: api.TagEventSourceMapping}},
+		},
+	}
+	storage := REST{registry: mockRepositoryRegistry}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.ImageRepository{
+		TypeMeta: kapi.TypeMeta{ID: "bar"},
+		Tags:     map[string]string{"latest": "image2", "stable": "image1"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected non-nil error: %#v", err)
+	}
+	result := <-channel
+	repo, ok := result.(*api.ImageRepository)
+	if !ok {
+		t.Fatalf("Expected image repository, got %#v", result)
+	}
+
+	latest := repo.TagHistory["latest"]
+	if len(latest) != 2 {
+		t.Fatalf("Expected two events for latest, got %#v", latest)
+	}
+	if latest[0].ImageID != "image2" || latest[0].SetBy != api.TagEventSourceUpdate || latest[0].Created.IsZero() {
+		t.Errorf("Unexpected newest event: %#v", latest[0])
+	}
+	if latest[1].ImageID != "image1" {
+		t.Errorf("Expected the previous event to be kept, got %#v", latest[1])
+	}
+	if e, a := 1, len(repo.TagHistory["stable"]); e != a {
+		t.Errorf("Expected %d events for the unchanged tag, got %d", e, a)
+	}
+}
+
 func TestDeleteImageRepository(t *testing.T) {
 	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
 	storage := REST{registry: mockRepositoryRegistry}
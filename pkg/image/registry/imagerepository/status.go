@@ -0,0 +1,48 @@
+package imagerepository
+
+import (
+	"github.com/golang/glog"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// UpdateStatus computes the status of repo from its DockerImageRepository, tags and tag history.
+// A tag is pulled by ID with the pull spec recorded when it was set if that names the image by ID,
+// and with the ID as tag of the Docker repository otherwise. Without a DockerImageRepository, only
+// pull specs by ID which were recorded are known.
+func UpdateStatus(repo *api.ImageRepository) {
+	status := api.ImageRepositoryStatus{}
+
+	var dockerRepo *api.DockerImageReference
+	if len(repo.DockerImageRepository) > 0 {
+		ref, err := api.ParseDockerImageReference(repo.DockerImageRepository)
+		if err != nil {
+			glog.V(4).Infof("Unable to parse the Docker repository of imageRepository %s: %v", repo.ID, err)
+		} else {
+			ref = ref.DockerClientDefaults()
+			ref.Tag, ref.ID = "", ""
+			status.DockerImageRepository = ref.String()
+			dockerRepo = &ref
+		}
+	}
+
+	if len(repo.Tags) > 0 {
+		status.Tags = make(map[string]api.TagStatus)
+	}
+	for tag, imageID := range repo.Tags {
+		tagStatus := api.TagStatus{ImageID: imageID}
+		if dockerRepo != nil {
+			byTag, byID := *dockerRepo, *dockerRepo
+			byTag.Tag, byID.Tag = tag, imageID
+			tagStatus.PullSpec, tagStatus.ImagePullSpec = byTag.String(), byID.String()
+		}
+		if history := repo.TagHistory[tag]; len(history) > 0 && history[0].ImageID == imageID {
+			if ref, err := api.ParseDockerImageReference(history[0].DockerImageReference); err == nil && len(ref.ID) > 0 {
+				tagStatus.ImagePullSpec = ref.String()
+			}
+		}
+		status.Tags[tag] = tagStatus
+	}
+
+	repo.Status = status
+}
@@ -0,0 +1,115 @@
+package imagerepository
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/test"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	testCases := map[string]struct {
+		repo     api.ImageRepository
+		expected api.ImageRepositoryStatus
+	}{
+		"registry with port": {
+			repo: api.ImageRepository{
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags:                  map[string]string{"latest": "abc123"},
+			},
+			expected: api.ImageRepositoryStatus{
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags: map[string]api.TagStatus{
+					"latest": {ImageID: "abc123", PullSpec: "registry:5000/app/frontend:latest", ImagePullSpec: "registry:5000/app/frontend:abc123"},
+				},
+			},
+		},
+		"default registry": {
+			repo: api.ImageRepository{
+				DockerImageRepository: "ruby",
+				Tags:                  map[string]string{"2.0": "def456"},
+			},
+			expected: api.ImageRepositoryStatus{
+				DockerImageRepository: "index.docker.io/library/ruby",
+				Tags: map[string]api.TagStatus{
+					"2.0": {ImageID: "def456", PullSpec: "index.docker.io/library/ruby:2.0", ImagePullSpec: "index.docker.io/library/ruby:def456"},
+				},
+			},
+		},
+		"recorded digest": {
+			repo: api.ImageRepository{
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags:                  map[string]string{"latest": "abc123", "v1": "def456"},
+				TagHistory: map[string][]api.TagEvent{
+					"latest": {{ImageID: "abc123", DockerImageReference: "registry:5000/app/frontend@sha256:1111"}},
+					"v1":     {{ImageID: "def456", DockerImageReference: "registry:5000/app/frontend:v1"}},
+				},
+			},
+			expected: api.ImageRepositoryStatus{
+				DockerImageRepository: "registry:5000/app/frontend",
+				Tags: map[string]api.TagStatus{
+					"latest": {ImageID: "abc123", PullSpec: "registry:5000/app/frontend:latest", ImagePullSpec: "registry:5000/app/frontend@sha256:1111"},
+					"v1":     {ImageID: "def456", PullSpec: "registry:5000/app/frontend:v1", ImagePullSpec: "registry:5000/app/frontend:def456"},
+				},
+			},
+		},
+		"no Docker repository": {
+			repo: api.ImageRepository{
+				Tags: map[string]string{"latest": "abc123", "v1": "def456"},
+				TagHistory: map[string][]api.TagEvent{
+					"latest": {{ImageID: "abc123", DockerImageReference: "registry:5000/app/frontend@abc123"}},
+				},
+			},
+			expected: api.ImageRepositoryStatus{
+				Tags: map[string]api.TagStatus{
+					"latest": {ImageID: "abc123", ImagePullSpec: "registry:5000/app/frontend@abc123"},
+					"v1":     {ImageID: "def456"},
+				},
+			},
+		},
+		"invalid Docker repository": {
+			repo: api.ImageRepository{
+				DockerImageRepository: "a/b/c",
+				Tags:                  map[string]string{"latest": "abc123"},
+			},
+			expected: api.ImageRepositoryStatus{
+				Tags: map[string]api.TagStatus{
+					"latest": {ImageID: "abc123"},
+				},
+			},
+		},
+		"no tags": {
+			repo:     api.ImageRepository{DockerImageRepository: "registry:5000/app/frontend"},
+			expected: api.ImageRepositoryStatus{DockerImageRepository: "registry:5000/app/frontend"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		UpdateStatus(&testCase.repo)
+		if !reflect.DeepEqual(testCase.expected, testCase.repo.Status) {
+			t.Errorf("%s: expected %#v, got %#v", name, testCase.expected, testCase.repo.Status)
+		}
+	}
+}
+
+func TestGetImageRepositoryComputesStatus(t *testing.T) {
+	mockRepositoryRegistry := test.NewImageRepositoryRegistry()
+	mockRepositoryRegistry.ImageRepository = &api.ImageRepository{
+		TypeMeta:              kapi.TypeMeta{ID: "frontend"},
+		DockerImageRepository: "registry:5000/app/frontend",
+		Tags:                  map[string]string{"latest": "abc123"},
+	}
+	storage := REST{registry: mockRepositoryRegistry}
+
+	obj, err := storage.Get(kapi.NewDefaultContext(), "frontend")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := obj.(*api.ImageRepository)
+	if e, a := "registry:5000/app/frontend:latest", repo.Status.Tags["latest"].PullSpec; e != a {
+		t.Errorf("Expected pull spec %s, got %s", e, a)
+	}
+}
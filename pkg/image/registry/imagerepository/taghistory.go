@@ -0,0 +1,50 @@
+package imagerepository
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// RecordTagEvent sets the tag of the repository to the image and, unless the tag already points to
+// the image, prepends the change to the history of the tag.
+func RecordTagEvent(repo *api.ImageRepository, tag string, event api.TagEvent) {
+	if repo.Tags == nil {
+		repo.Tags = make(map[string]string)
+	}
+	repo.Tags[tag] = event.ImageID
+
+	if history := repo.TagHistory[tag]; len(history) > 0 && history[0].ImageID == event.ImageID {
+		return
+	}
+	if repo.TagHistory == nil {
+		repo.TagHistory = make(map[string][]api.TagEvent)
+	}
+	repo.TagHistory[tag] = append([]api.TagEvent{event}, repo.TagHistory[tag]...)
+}
+
+// updateTagHistory carries the tag history of previous, the stored state of the repository, over to
+// repo and records every tag of repo which was set or changed since as set by source, or by an alias
+// if the tag is one. A nil previous means the repository is new.
+func updateTagHistory(repo, previous *api.ImageRepository, source api.TagEventSource) {
+	repo.TagHistory = nil
+	if previous != nil {
+		repo.TagHistory = previous.TagHistory
+	}
+
+	now := util.Now()
+	for tag, imageID := range repo.Tags {
+		if previous != nil && previous.Tags[tag] == imageID {
+			continue
+		}
+		setBy := source
+		if _, ok := repo.TagAliases[tag]; ok {
+			setBy = api.TagEventSourceAlias
+		}
+		RecordTagEvent(repo, tag, api.TagEvent{
+			Created: now,
+			ImageID: imageID,
+			SetBy:   setBy,
+		})
+	}
+}
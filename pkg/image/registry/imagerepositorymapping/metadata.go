@@ -0,0 +1,98 @@
+package imagerepositorymapping
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/fsouza/go-dockerclient"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// validateImageMetadata fails if the metadata of image describes another image.
+func validateImageMetadata(image *api.Image) errors.ErrorList {
+	result := errors.ErrorList{}
+	if len(image.Metadata.ID) > 0 && image.Metadata.ID != image.ID {
+		result = append(result, errors.NewFieldInvalid("image.metadata.Id", image.Metadata.ID))
+	}
+	return result
+}
+
+// mergeImage returns existing, a stored image, with the metadata it lacks filled in from image, which
+// has the same ID, and the labels of image added. Images are immutable, so metadata of image which
+// differs from metadata existing already has is a conflict. The second return value is false if the
+// merge didn't change existing.
+func mergeImage(existing, image *api.Image) (*api.Image, bool, error) {
+	merged := *existing
+	metadata := &merged.Metadata
+	from := &image.Metadata
+	conflicts := []string{}
+
+	mergeString := func(field string, dst *string, src string) {
+		switch {
+		case len(src) == 0 || *dst == src:
+		case len(*dst) == 0:
+			*dst = src
+		default:
+			conflicts = append(conflicts, field)
+		}
+	}
+	mergeString("Parent", &metadata.Parent, from.Parent)
+	mergeString("Comment", &metadata.Comment, from.Comment)
+	mergeString("Container", &metadata.Container, from.Container)
+	mergeString("DockerVersion", &metadata.DockerVersion, from.DockerVersion)
+	mergeString("Author", &metadata.Author, from.Author)
+	mergeString("Architecture", &metadata.Architecture, from.Architecture)
+
+	switch {
+	case from.Created.IsZero() || metadata.Created.Equal(from.Created):
+	case metadata.Created.IsZero():
+		metadata.Created = from.Created
+	default:
+		conflicts = append(conflicts, "Created")
+	}
+
+	switch {
+	case from.Size == 0 || metadata.Size == from.Size:
+	case metadata.Size == 0:
+		metadata.Size = from.Size
+	default:
+		conflicts = append(conflicts, "Size")
+	}
+
+	switch {
+	case from.Config == nil || reflect.DeepEqual(metadata.Config, from.Config):
+	case metadata.Config == nil:
+		metadata.Config = from.Config
+	default:
+		conflicts = append(conflicts, "Config")
+	}
+
+	switch {
+	case reflect.DeepEqual(from.ContainerConfig, docker.Config{}) || reflect.DeepEqual(metadata.ContainerConfig, from.ContainerConfig):
+	case reflect.DeepEqual(metadata.ContainerConfig, docker.Config{}):
+		metadata.ContainerConfig = from.ContainerConfig
+	default:
+		conflicts = append(conflicts, "ContainerConfig")
+	}
+
+	if len(conflicts) > 0 {
+		return nil, false, errors.NewConflict("image", existing.ID, fmt.Errorf("the metadata fields %v differ from those of the existing image", conflicts))
+	}
+
+	if len(merged.DockerImageReference) == 0 {
+		merged.DockerImageReference = image.DockerImageReference
+	}
+	if len(image.Labels) > 0 {
+		merged.Labels = make(map[string]string)
+		for key, value := range existing.Labels {
+			merged.Labels[key] = value
+		}
+		for key, value := range image.Labels {
+			merged.Labels[key] = value
+		}
+	}
+
+	return &merged, !reflect.DeepEqual(existing, &merged), nil
+}
@@ -72,19 +72,46 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	}
 
 	image := mapping.Image
+	// signatures are only attached through ImageSignatures, so pushing an image can't approve it
+	image.Signatures = nil
+	if errs := validateImageMetadata(&image); len(errs) > 0 {
+		return nil, errors.NewInvalid("imageRepositoryMapping", mapping.ID, errs)
+	}
+
+	// an image pushed before is completed with the metadata this mapping adds, and the mapping is
+	// rejected if it describes the image differently
+	existing, err := s.imageRegistry.GetImage(imageRepoCtx, image.ID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	var merged *api.Image
+	update := false
+	if existing != nil && err == nil {
+		if merged, update, err = mergeImage(existing, &image); err != nil {
+			return nil, err
+		}
+	}
 
 	image.CreationTimestamp = util.Now()
 
-	//TODO apply metadata overrides
-	if repo.Tags == nil {
-		repo.Tags = make(map[string]string)
-	}
-	repo.Tags[mapping.Tag] = image.ID
+	imagerepository.RecordTagEvent(repo, mapping.Tag, api.TagEvent{
+		Created:              image.CreationTimestamp,
+		ImageID:              image.ID,
+		DockerImageReference: image.DockerImageReference,
+		SetBy:                api.TagEventSourceMapping,
+	})
 
 	return apiserver.MakeAsync(func() (runtime.Object, error) {
-		err = s.imageRegistry.CreateImage(imageRepoCtx, &image)
-		if err != nil && !errors.IsAlreadyExists(err) {
-			return nil, err
+		switch {
+		case merged == nil:
+			err := s.imageRegistry.CreateImage(imageRepoCtx, &image)
+			if err != nil && !errors.IsAlreadyExists(err) {
+				return nil, err
+			}
+		case update:
+			if err := s.imageRegistry.UpdateImage(imageRepoCtx, merged); err != nil {
+				return nil, err
+			}
 		}
 
 		err = s.imageRepositoryRegistry.UpdateImageRepository(imageRepoCtx, repo)
@@ -96,25 +123,19 @@ func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Obje
 	}), nil
 }
 
-// findImageRepository retrieves an ImageRepository whose DockerImageRepository matches dockerRepo.
+// findImageRepository retrieves the ImageRepository whose DockerImageRepository matches dockerRepo in
+// the namespace of ctx. It returns nil if there is none.
 func (s *REST) findImageRepository(ctx kapi.Context, dockerRepo string) (*api.ImageRepository, error) {
-	//TODO make this more efficient
-	// you should not do this, but we have a bug right now that prevents us from trusting the ctx passed in
-	allNamespaces := kapi.NewContext()
-	list, err := s.imageRepositoryRegistry.ListImageRepositories(allNamespaces, labels.Everything())
-	if err != nil {
-		return nil, err
+	repo, err := s.imageRepositoryRegistry.FindImageRepository(ctx, dockerRepo)
+	if errors.IsNotFound(err) && kapi.Namespace(ctx) == kapi.NamespaceDefault {
+		// TODO: the Docker registry can't send the namespace of the repository yet, so mappings in
+		// the default namespace may refer to a repository in any namespace
+		repo, err = s.imageRepositoryRegistry.FindImageRepository(kapi.NewContext(), dockerRepo)
 	}
-
-	var repo *api.ImageRepository
-	for _, r := range list.Items {
-		if dockerRepo == r.DockerImageRepository {
-			repo = &r
-			break
-		}
+	if errors.IsNotFound(err) {
+		return nil, nil
 	}
-
-	return repo, nil
+	return repo, err
 }
 
 // Update is not supported.
@@ -230,6 +230,13 @@ func TestCreateImageRepositoryMapping(t *testing.T) {
 	if e, a := "imageID1", repo.Tags["latest"]; e != a {
 		t.Errorf("Expected %s, got %s", e, a)
 	}
+	history := repo.TagHistory["latest"]
+	if len(history) != 1 {
+		t.Fatalf("Expected one tag event, got %#v", history)
+	}
+	if history[0].ImageID != "imageID1" || history[0].DockerImageReference != mapping.Image.DockerImageReference || history[0].SetBy != api.TagEventSourceMapping {
+		t.Errorf("Unexpected tag event: %#v", history[0])
+	}
 }
 
 func TestCreateImageRepositoryConflictingNamespace(t *testing.T) {
@@ -296,3 +303,151 @@ func checkExpectedNamespaceError(t *testing.T, err error) {
 	}
 
 }
+
+func mappingFor(image api.Image) *api.ImageRepositoryMapping {
+	return &api.ImageRepositoryMapping{
+		DockerImageRepository: "localhost:5000/someproject/somerepo",
+		Image:                 image,
+		Tag:                   "latest",
+	}
+}
+
+func TestCreateImageRepositoryMappingMergesExistingImage(t *testing.T) {
+	existing := &api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "imageID1"},
+		Labels:               map[string]string{"a": "1"},
+		DockerImageReference: "localhost:5000/someproject/somerepo:imageID1",
+		Metadata: docker.Image{
+			ID:     "imageID1",
+			Parent: "parent",
+			Size:   1024,
+		},
+	}
+	imageRegistry := test.NewImageRegistry()
+	imageRegistry.Image = existing
+	imageRepositoryRegistry := test.NewImageRepositoryRegistry()
+	imageRepositoryRegistry.ImageRepositories = &api.ImageRepositoryList{
+		Items: []api.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "repo1"},
+				DockerImageRepository: "localhost:5000/someproject/somerepo",
+			},
+		},
+	}
+	storage := &REST{imageRegistry, imageRepositoryRegistry}
+
+	ch, err := storage.Create(kapi.NewDefaultContext(), mappingFor(api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "imageID1"},
+		Labels:               map[string]string{"b": "2"},
+		DockerImageReference: "localhost:5000/someproject/somerepo:latest",
+		Metadata: docker.Image{
+			Parent: "parent",
+			Config: &docker.Config{Cmd: []string{"ls", "/"}},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error creating mapping: %#v", err)
+	}
+	<-ch
+
+	image := imageRegistry.Image
+	if image == existing {
+		t.Fatalf("Expected the existing image to be updated")
+	}
+	if image.Metadata.Config == nil || !reflect.DeepEqual(image.Metadata.Config.Cmd, []string{"ls", "/"}) {
+		t.Errorf("Expected the missing config to be filled in, got %#v", image.Metadata.Config)
+	}
+	if image.Metadata.Size != 1024 || image.Metadata.Parent != "parent" {
+		t.Errorf("Expected the existing metadata to be kept, got %#v", image.Metadata)
+	}
+	if e, a := map[string]string{"a": "1", "b": "2"}, image.Labels; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected labels %v, got %v", e, a)
+	}
+	if e, a := existing.DockerImageReference, image.DockerImageReference; e != a {
+		t.Errorf("Expected reference %s, got %s", e, a)
+	}
+	if len(existing.Labels) != 1 || existing.Metadata.Config != nil {
+		t.Errorf("Unexpected change of the existing image: %#v", existing)
+	}
+}
+
+func TestCreateImageRepositoryMappingConflictingMetadata(t *testing.T) {
+	imageRegistry := test.NewImageRegistry()
+	imageRegistry.Image = &api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "imageID1"},
+		DockerImageReference: "localhost:5000/someproject/somerepo:imageID1",
+		Metadata:             docker.Image{Parent: "parent", Size: 1024},
+	}
+	imageRepositoryRegistry := test.NewImageRepositoryRegistry()
+	imageRepositoryRegistry.ImageRepositories = &api.ImageRepositoryList{
+		Items: []api.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "repo1"},
+				DockerImageRepository: "localhost:5000/someproject/somerepo",
+			},
+		},
+	}
+	storage := &REST{imageRegistry, imageRepositoryRegistry}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), mappingFor(api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "imageID1"},
+		DockerImageReference: "localhost:5000/someproject/somerepo:imageID1",
+		Metadata:             docker.Image{Parent: "other", Size: 1024},
+	}))
+	if !errors.IsConflict(err) {
+		t.Errorf("Expected 'conflict' error, got %#v", err)
+	}
+}
+
+func TestCreateImageRepositoryMappingMismatchedMetadataID(t *testing.T) {
+	imageRegistry := test.NewImageRegistry()
+	imageRepositoryRegistry := test.NewImageRepositoryRegistry()
+	imageRepositoryRegistry.ImageRepositories = &api.ImageRepositoryList{
+		Items: []api.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "repo1"},
+				DockerImageRepository: "localhost:5000/someproject/somerepo",
+			},
+		},
+	}
+	storage := &REST{imageRegistry, imageRepositoryRegistry}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), mappingFor(api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "imageID1"},
+		DockerImageReference: "localhost:5000/someproject/somerepo:imageID1",
+		Metadata:             docker.Image{ID: "imageID2"},
+	}))
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected 'invalid' error, got %#v", err)
+	}
+}
+
+func TestCreateImageRepositoryMappingUnchangedImage(t *testing.T) {
+	existing := &api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "imageID1"},
+		DockerImageReference: "localhost:5000/someproject/somerepo:imageID1",
+		Metadata:             docker.Image{Parent: "parent"},
+	}
+	imageRegistry := test.NewImageRegistry()
+	imageRegistry.Image = existing
+	imageRepositoryRegistry := test.NewImageRepositoryRegistry()
+	imageRepositoryRegistry.ImageRepositories = &api.ImageRepositoryList{
+		Items: []api.ImageRepository{
+			{
+				TypeMeta:              kapi.TypeMeta{ID: "repo1"},
+				DockerImageRepository: "localhost:5000/someproject/somerepo",
+			},
+		},
+	}
+	storage := &REST{imageRegistry, imageRepositoryRegistry}
+
+	ch, err := storage.Create(kapi.NewDefaultContext(), mappingFor(*existing))
+	if err != nil {
+		t.Fatalf("Unexpected error creating mapping: %#v", err)
+	}
+	<-ch
+
+	if imageRegistry.Image != existing {
+		t.Errorf("Unexpected write of the unchanged image: %#v", imageRegistry.Image)
+	}
+}
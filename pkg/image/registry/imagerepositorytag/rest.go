@@ -0,0 +1,131 @@
+package imagerepositorytag
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/api/validation"
+	"github.com/openshift/origin/pkg/image/registry/imagerepository"
+)
+
+// maxUpdateAttempts is the number of times a tag is set on the current state of an ImageRepository
+// before giving up when the update keeps conflicting with other changes of the repository.
+const maxUpdateAttempts = 3
+
+// REST implements the RESTStorage interface in terms of an imagerepository.Registry. It only supports
+// the Create method, which copies a tag of one ImageRepository to another.
+type REST struct {
+	registry imagerepository.Registry
+}
+
+// NewREST returns a new REST.
+func NewREST(registry imagerepository.Registry) apiserver.RESTStorage {
+	return &REST{registry}
+}
+
+// New returns a new ImageRepositoryTag for use with Create.
+func (s *REST) New() runtime.Object {
+	return &api.ImageRepositoryTag{}
+}
+
+// List is not supported.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.NewNotFound("imageRepositoryTag", "list")
+}
+
+// Get is not supported.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return nil, errors.NewNotFound("imageRepositoryTag", id)
+}
+
+// Create sets the tag of the ImageRepository with the ID of the ImageRepositoryTag to the image the
+// tag it is copied from points to, and returns the updated ImageRepository. An alias of the tag is
+// removed, so the copy isn't overwritten when the tag it aliased changes.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	tag, ok := obj.(*api.ImageRepositoryTag)
+	if !ok {
+		return nil, fmt.Errorf("not an image repository tag: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &tag.TypeMeta) {
+		return nil, errors.NewConflict("imageRepositoryTag", tag.Namespace, fmt.Errorf("ImageRepositoryTag.Namespace does not match the provided context"))
+	}
+	if errs := validation.ValidateImageRepositoryTag(tag); len(errs) > 0 {
+		return nil, errors.NewInvalid("imageRepositoryTag", tag.ID, errs)
+	}
+
+	fromCtx := ctx
+	if len(tag.From.Namespace) > 0 {
+		fromCtx = kapi.WithNamespace(ctx, tag.From.Namespace)
+	}
+	from, err := s.registry.GetImageRepository(fromCtx, tag.From.ImageRepository)
+	if err != nil {
+		return nil, err
+	}
+	imageID, ok := from.Tags[tag.From.Tag]
+	if !ok {
+		return nil, errors.NewInvalid("imageRepositoryTag", tag.ID, errors.ErrorList{
+			errors.NewFieldNotFound("From.Tag", tag.From.Tag),
+		})
+	}
+
+	if _, err := s.registry.GetImageRepository(ctx, tag.ID); err != nil {
+		return nil, err
+	}
+
+	event := api.TagEvent{
+		Created: util.Now(),
+		ImageID: imageID,
+		SetBy:   api.TagEventSourceTag,
+	}
+	if history := from.TagHistory[tag.From.Tag]; len(history) > 0 && history[0].ImageID == imageID {
+		event.DockerImageReference = history[0].DockerImageReference
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		for attempt := 1; ; attempt++ {
+			err := s.setTag(ctx, tag.ID, tag.Tag, event)
+			if err == nil {
+				break
+			}
+			if !errors.IsConflict(err) || attempt == maxUpdateAttempts {
+				return nil, err
+			}
+		}
+		updated, err := s.registry.GetImageRepository(ctx, tag.ID)
+		if err != nil {
+			return nil, err
+		}
+		imagerepository.UpdateStatus(updated)
+		return updated, nil
+	}), nil
+}
+
+// setTag records event as the new value of tag on the current state of the ImageRepository with the
+// given ID, so that concurrent changes of its other tags are kept. The update conflicts if the
+// repository changes after it is read.
+func (s *REST) setTag(ctx kapi.Context, id, tag string, event api.TagEvent) error {
+	repo, err := s.registry.GetImageRepository(ctx, id)
+	if err != nil {
+		return err
+	}
+	delete(repo.TagAliases, tag)
+	imagerepository.RecordTagEvent(repo, tag, event)
+	return s.registry.UpdateImageRepository(ctx, repo)
+}
+
+// Update is not supported.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return nil, fmt.Errorf("ImageRepositoryTags may not be changed.")
+}
+
+// Delete is not supported.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.NewNotFound("imageRepositoryTag", id)
+}
@@ -0,0 +1,163 @@
+package imagerepositorytag
+
+import (
+	"fmt"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/test"
+)
+
+// testRegistry serves the repositories it holds by namespace and ID, and records updates in the
+// embedded registry after failing the first conflicts of them.
+type testRegistry struct {
+	*test.ImageRepositoryRegistry
+	repositories map[string]*api.ImageRepository
+	conflicts    int
+}
+
+func (r *testRegistry) UpdateImageRepository(ctx kapi.Context, repo *api.ImageRepository) error {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return errors.NewConflict("imageRepository", repo.ID, fmt.Errorf("modified"))
+	}
+	return r.ImageRepositoryRegistry.UpdateImageRepository(ctx, repo)
+}
+
+func (r *testRegistry) GetImageRepository(ctx kapi.Context, id string) (*api.ImageRepository, error) {
+	repo, ok := r.repositories[kapi.Namespace(ctx)+"/"+id]
+	if !ok {
+		return nil, errors.NewNotFound("imageRepository", id)
+	}
+	return repo, nil
+}
+
+func newTestRegistry(repos ...*api.ImageRepository) *testRegistry {
+	registry := &testRegistry{test.NewImageRepositoryRegistry(), make(map[string]*api.ImageRepository), 0}
+	for _, repo := range repos {
+		registry.repositories[repo.Namespace+"/"+repo.ID] = repo
+	}
+	return registry
+}
+
+func devRepository() *api.ImageRepository {
+	return &api.ImageRepository{
+		TypeMeta: kapi.TypeMeta{ID: "dev", Namespace: "dev"},
+		Tags:     map[string]string{"v2.3": "image2"},
+		TagHistory: map[string][]api.TagEvent{
+			"v2.3": {{ImageID: "image2", DockerImageReference: "registry/dev/app:v2.3"}},
+		},
+	}
+}
+
+func prodRepository() *api.ImageRepository {
+	return &api.ImageRepository{
+		TypeMeta:   kapi.TypeMeta{ID: "prod", Namespace: kapi.NamespaceDefault},
+		Tags:       map[string]string{"latest": "image1"},
+		TagAliases: map[string]api.TagReference{"latest": {ImageRepository: "other", Tag: "latest"}},
+	}
+}
+
+func TestCreateImageRepositoryTag(t *testing.T) {
+	registry := newTestRegistry(devRepository(), prodRepository())
+	storage := &REST{registry}
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryTag{
+		TypeMeta: kapi.TypeMeta{ID: "prod"},
+		Tag:      "latest",
+		From:     api.TagReference{Namespace: "dev", ImageRepository: "dev", Tag: "v2.3"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := <-channel
+	repo, ok := result.(*api.ImageRepository)
+	if !ok {
+		t.Fatalf("Expected image repository, got %#v", result)
+	}
+
+	if e, a := "image2", repo.Tags["latest"]; e != a {
+		t.Errorf("Expected the tag to point to %s, got %s", e, a)
+	}
+	if _, ok := repo.TagAliases["latest"]; ok {
+		t.Errorf("Expected the alias of the copied tag to be removed")
+	}
+	history := repo.TagHistory["latest"]
+	if len(history) != 1 || history[0].SetBy != api.TagEventSourceTag || history[0].DockerImageReference != "registry/dev/app:v2.3" {
+		t.Errorf("Unexpected tag history: %#v", history)
+	}
+	if registry.ImageRepository != repo {
+		t.Errorf("Expected the repository to be updated")
+	}
+}
+
+func TestCreateImageRepositoryTagConflict(t *testing.T) {
+	testCases := map[string]struct {
+		conflicts int
+		updated   bool
+	}{
+		"retried":  {maxUpdateAttempts - 1, true},
+		"given up": {maxUpdateAttempts, false},
+	}
+
+	for name, testCase := range testCases {
+		registry := newTestRegistry(devRepository(), prodRepository())
+		registry.conflicts = testCase.conflicts
+		storage := &REST{registry}
+
+		channel, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryTag{
+			TypeMeta: kapi.TypeMeta{ID: "prod"},
+			Tag:      "latest",
+			From:     api.TagReference{Namespace: "dev", ImageRepository: "dev", Tag: "v2.3"},
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		result := <-channel
+		if _, ok := result.(*api.ImageRepository); ok != testCase.updated {
+			t.Errorf("%s: unexpected result %#v", name, result)
+		}
+		if updated := registry.ImageRepository != nil; updated != testCase.updated {
+			t.Errorf("%s: expected update %t, got %#v", name, testCase.updated, registry.ImageRepository)
+		}
+	}
+}
+
+func TestCreateImageRepositoryTagMissingTag(t *testing.T) {
+	storage := &REST{newTestRegistry(devRepository(), prodRepository())}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryTag{
+		TypeMeta: kapi.TypeMeta{ID: "prod"},
+		Tag:      "latest",
+		From:     api.TagReference{Namespace: "dev", ImageRepository: "dev", Tag: "v3"},
+	})
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected 'invalid' error, got %#v", err)
+	}
+}
+
+func TestCreateImageRepositoryTagMissingRepository(t *testing.T) {
+	storage := &REST{newTestRegistry(prodRepository())}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.ImageRepositoryTag{
+		TypeMeta: kapi.TypeMeta{ID: "prod"},
+		Tag:      "latest",
+		From:     api.TagReference{ImageRepository: "dev", Tag: "v2.3"},
+	})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected 'not found' error, got %#v", err)
+	}
+}
+
+func TestCreateImageRepositoryTagBadObject(t *testing.T) {
+	storage := &REST{newTestRegistry()}
+
+	var obj runtime.Object = &api.ImageList{}
+	if _, err := storage.Create(kapi.NewDefaultContext(), obj); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
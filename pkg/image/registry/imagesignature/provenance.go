@@ -0,0 +1,36 @@
+package imagesignature
+
+import (
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/api/validation"
+	"github.com/openshift/origin/pkg/image/registry/image"
+)
+
+// ProvenanceRecorder attaches the ImageSignatures which record the provenance of built images to the
+// Images they name. It isn't served by the API, so only the components of the master which are given
+// one, such as the build controller, can record provenance.
+type ProvenanceRecorder struct {
+	registry image.Registry
+}
+
+// NewProvenanceRecorder returns a new ProvenanceRecorder which updates the images of registry.
+func NewProvenanceRecorder(registry image.Registry) *ProvenanceRecorder {
+	return &ProvenanceRecorder{registry}
+}
+
+// CreateImageSignature attaches signature, signed by api.ImageProvenanceSigner, to the Image it names
+// and returns the updated Image. A signature of the image with the same ID is replaced.
+func (r *ProvenanceRecorder) CreateImageSignature(ctx kapi.Context, signature *api.ImageSignature) (*api.Image, error) {
+	signature.Signer = api.ImageProvenanceSigner
+	errs := validation.ValidateImageSignature(signature)
+	if signature.Provenance == nil {
+		errs = append(errs, errors.NewFieldRequired("Provenance", signature.Provenance))
+	}
+	if len(errs) > 0 {
+		return nil, errors.NewInvalid("imageSignature", signature.ID, errs)
+	}
+	return attachSignature(ctx, r.registry, nil, signature, false)
+}
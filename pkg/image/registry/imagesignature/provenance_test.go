@@ -0,0 +1,58 @@
+package imagesignature
+
+import (
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/test"
+)
+
+func TestProvenanceRecorderCreateImageSignature(t *testing.T) {
+	registry := test.NewImageRegistry()
+	registry.Image = signedImage()
+	recorder := NewProvenanceRecorder(registry)
+
+	image, err := recorder.CreateImageSignature(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta:   kapi.TypeMeta{ID: "build-build2"},
+		Image:      "abc123",
+		Provenance: &api.ImageProvenance{Build: "build2"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if e, a := 2, len(image.Signatures); e != a {
+		t.Fatalf("Expected %d signatures, got %#v", e, image.Signatures)
+	}
+	signature := image.Signatures[1]
+	if e, a := api.ImageProvenanceSigner, signature.Signer; e != a {
+		t.Errorf("Expected signer %s, got %s", e, a)
+	}
+	if signature.Provenance == nil || signature.Provenance.Build != "build2" {
+		t.Errorf("Unexpected provenance %#v", signature.Provenance)
+	}
+	if e, a := api.ImageSignatureUnverified, signature.Status; e != a {
+		t.Errorf("Expected status %s, got %s", e, a)
+	}
+}
+
+func TestProvenanceRecorderProvenanceRequired(t *testing.T) {
+	registry := test.NewImageRegistry()
+	registry.Image = signedImage()
+	recorder := NewProvenanceRecorder(registry)
+
+	_, err := recorder.CreateImageSignature(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "build-build2"},
+		Image:     "abc123",
+		Signature: []byte("signature"),
+	})
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected an invalid error, got %v", err)
+	}
+	if e, a := 1, len(registry.Image.Signatures); e != a {
+		t.Errorf("Expected the image to be unchanged, got %#v", registry.Image.Signatures)
+	}
+}
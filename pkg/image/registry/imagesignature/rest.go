@@ -0,0 +1,126 @@
+package imagesignature
+
+import (
+	"fmt"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/api/validation"
+	"github.com/openshift/origin/pkg/image/registry/image"
+)
+
+// REST implements the RESTStorage interface in terms of an image.Registry. It supports the Create
+// and Update methods, which attach an ImageSignature to the Image it names. The status of the
+// signature is determined by the server; any status sent by the client is ignored. Clients can't
+// record provenance, which only a ProvenanceRecorder attaches.
+type REST struct {
+	registry image.Registry
+	verifier Verifier
+}
+
+// NewREST returns a new REST which verifies signatures with verifier. If verifier is nil, every
+// signature remains unverified.
+func NewREST(registry image.Registry, verifier Verifier) apiserver.RESTStorage {
+	return &REST{registry, verifier}
+}
+
+// New returns a new ImageSignature for use with Create and Update.
+func (s *REST) New() runtime.Object {
+	return &api.ImageSignature{}
+}
+
+// List is not supported, the signatures of an Image are part of the Image.
+func (s *REST) List(ctx kapi.Context, selector, fields labels.Selector) (runtime.Object, error) {
+	return nil, errors.NewNotFound("imageSignature", "list")
+}
+
+// Get is not supported, the signatures of an Image are part of the Image.
+func (s *REST) Get(ctx kapi.Context, id string) (runtime.Object, error) {
+	return nil, errors.NewNotFound("imageSignature", id)
+}
+
+// Create attaches the ImageSignature to the Image it names and returns the updated Image. A
+// signature of the image with the same ID is replaced.
+func (s *REST) Create(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return s.attach(ctx, obj, false)
+}
+
+// Update replaces an existing signature of the Image the ImageSignature names and returns the updated
+// Image.
+func (s *REST) Update(ctx kapi.Context, obj runtime.Object) (<-chan runtime.Object, error) {
+	return s.attach(ctx, obj, true)
+}
+
+// Delete is not supported.
+func (s *REST) Delete(ctx kapi.Context, id string) (<-chan runtime.Object, error) {
+	return nil, errors.NewNotFound("imageSignature", id)
+}
+
+func (s *REST) attach(ctx kapi.Context, obj runtime.Object, mustExist bool) (<-chan runtime.Object, error) {
+	signature, ok := obj.(*api.ImageSignature)
+	if !ok {
+		return nil, fmt.Errorf("not an image signature: %#v", obj)
+	}
+	if !kapi.ValidNamespace(ctx, &signature.TypeMeta) {
+		return nil, errors.NewConflict("imageSignature", signature.Namespace, fmt.Errorf("ImageSignature.Namespace does not match the provided context"))
+	}
+	errs := validation.ValidateImageSignature(signature)
+	if signature.Provenance != nil {
+		errs = append(errs, errors.NewFieldForbidden("Provenance", signature.Provenance))
+	}
+	if signature.Signer == api.ImageProvenanceSigner {
+		errs = append(errs, errors.NewFieldForbidden("Signer", signature.Signer))
+	}
+	if len(errs) > 0 {
+		return nil, errors.NewInvalid("imageSignature", signature.ID, errs)
+	}
+
+	return apiserver.MakeAsync(func() (runtime.Object, error) {
+		return attachSignature(ctx, s.registry, s.verifier, signature, mustExist)
+	}), nil
+}
+
+// attachSignature attaches signature to the Image it names, replacing the signature of the image
+// with the same ID, and returns the updated Image. The status of the signature is determined by
+// verifier, if not nil. Signatures recording provenance can only be replaced by provenance.
+func attachSignature(ctx kapi.Context, registry image.Registry, verifier Verifier, signature *api.ImageSignature, mustExist bool) (*api.Image, error) {
+	signature.Status = api.ImageSignatureUnverified
+	signature.CreationTimestamp = util.Now()
+
+	image, err := registry.GetImage(ctx, signature.Image)
+	if err != nil {
+		return nil, err
+	}
+	if verifier != nil {
+		signature.Status = verifier.Verify(image, signature)
+	}
+
+	replaced := false
+	for i := range image.Signatures {
+		if image.Signatures[i].ID == signature.ID {
+			if image.Signatures[i].Signer == api.ImageProvenanceSigner && signature.Signer != api.ImageProvenanceSigner {
+				return nil, errors.NewInvalid("imageSignature", signature.ID, errors.ErrorList{errors.NewFieldForbidden("ID", signature.ID)})
+			}
+			image.Signatures[i] = *signature
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		if mustExist {
+			return nil, errors.NewNotFound("imageSignature", signature.ID)
+		}
+		image.Signatures = append(image.Signatures, *signature)
+	}
+
+	if err := registry.UpdateImage(ctx, image); err != nil {
+		return nil, err
+	}
+	return registry.GetImage(ctx, image.ID)
+}
@@ -0,0 +1,254 @@
+package imagesignature
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"net/http"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/test"
+)
+
+func signedImage() *api.Image {
+	return &api.Image{
+		TypeMeta:             kapi.TypeMeta{ID: "abc123", Namespace: kapi.NamespaceDefault},
+		DockerImageReference: "registry/default/app:abc123",
+		Signatures: []api.ImageSignature{
+			{
+				TypeMeta:   kapi.TypeMeta{ID: "build"},
+				Image:      "abc123",
+				Signer:     api.ImageProvenanceSigner,
+				Status:     api.ImageSignatureUnverified,
+				Provenance: &api.ImageProvenance{Build: "build1"},
+			},
+		},
+	}
+}
+
+func TestCreateImageSignature(t *testing.T) {
+	registry := test.NewImageRegistry()
+	registry.Image = signedImage()
+	storage := &REST{registry: registry}
+
+	channel, err := storage.Create(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "security"},
+		Image:     "abc123",
+		Signer:    "security@example.com",
+		Signature: []byte("signature"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := <-channel
+	image, ok := result.(*api.Image)
+	if !ok {
+		t.Fatalf("Expected image, got %#v", result)
+	}
+
+	if e, a := 2, len(image.Signatures); e != a {
+		t.Fatalf("Expected %d signatures, got %#v", e, image.Signatures)
+	}
+	signature := image.Signatures[1]
+	if signature.ID != "security" || string(signature.Signature) != "signature" {
+		t.Errorf("Unexpected signature %#v", signature)
+	}
+	if e, a := api.ImageSignatureUnverified, signature.Status; e != a {
+		t.Errorf("Expected status %s, got %s", e, a)
+	}
+	if signature.CreationTimestamp.IsZero() {
+		t.Errorf("Expected the creation timestamp to be set")
+	}
+}
+
+func TestUpdateImageSignatureReplacesSignature(t *testing.T) {
+	registry := test.NewImageRegistry()
+	registry.Image = signedImage()
+	registry.Image.Signatures = append(registry.Image.Signatures, api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "security"},
+		Image:     "abc123",
+		Signer:    "security@example.com",
+		Signature: []byte("signature"),
+		Status:    api.ImageSignatureUnverified,
+	})
+	storage := &REST{registry: registry}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "security"},
+		Image:     "abc123",
+		Signer:    "security@example.com",
+		Signature: []byte("new signature"),
+		Status:    api.ImageSignatureVerified,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := <-channel
+	image, ok := result.(*api.Image)
+	if !ok {
+		t.Fatalf("Expected image, got %#v", result)
+	}
+
+	if e, a := 2, len(image.Signatures); e != a {
+		t.Fatalf("Expected %d signatures, got %#v", e, image.Signatures)
+	}
+	if e, a := "new signature", string(image.Signatures[1].Signature); e != a {
+		t.Errorf("Expected signature %q, got %q", e, a)
+	}
+	// the status sent by the client is ignored
+	if e, a := api.ImageSignatureUnverified, image.Signatures[1].Status; e != a {
+		t.Errorf("Expected status %s, got %s", e, a)
+	}
+}
+
+func TestUpdateImageSignatureProvenanceNotReplaced(t *testing.T) {
+	registry := test.NewImageRegistry()
+	registry.Image = signedImage()
+	storage := &REST{registry: registry}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "build"},
+		Image:     "abc123",
+		Signer:    "security@example.com",
+		Signature: []byte("signature"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := <-channel
+	status, ok := result.(*kapi.Status)
+	if !ok {
+		t.Fatalf("Expected status, got %#v", result)
+	}
+	if e, a := 422, status.Code; e != a {
+		t.Errorf("Expected code %d, got %#v", e, status)
+	}
+	if signature := registry.Image.Signatures[0]; signature.Provenance == nil || signature.Signer != api.ImageProvenanceSigner {
+		t.Errorf("Expected the provenance to be kept, got %#v", signature)
+	}
+}
+
+func TestCreateImageSignatureVerified(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	digest := sha256.Sum256([]byte("abc123"))
+	valid, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verifier := &KeyringVerifier{Keys: map[string]*rsa.PublicKey{"security@example.com": &key.PublicKey}}
+
+	testCases := []struct {
+		signer    string
+		signature []byte
+		status    api.ImageSignatureStatus
+	}{
+		{signer: "security@example.com", signature: valid, status: api.ImageSignatureVerified},
+		{signer: "security@example.com", signature: []byte("forged"), status: api.ImageSignatureRejected},
+		{signer: "unknown@example.com", signature: valid, status: api.ImageSignatureUnverified},
+	}
+
+	for _, testCase := range testCases {
+		registry := test.NewImageRegistry()
+		registry.Image = signedImage()
+		storage := &REST{registry: registry, verifier: verifier}
+
+		channel, err := storage.Create(kapi.NewDefaultContext(), &api.ImageSignature{
+			TypeMeta:  kapi.TypeMeta{ID: "security"},
+			Image:     "abc123",
+			Signer:    testCase.signer,
+			Signature: testCase.signature,
+			Status:    api.ImageSignatureVerified,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		result := <-channel
+		image, ok := result.(*api.Image)
+		if !ok {
+			t.Fatalf("Expected image, got %#v", result)
+		}
+		if e, a := testCase.status, image.Signatures[1].Status; e != a {
+			t.Errorf("Expected status %s for a signature by %s, got %s", e, testCase.signer, a)
+		}
+	}
+}
+
+func TestUpdateImageSignatureNotFound(t *testing.T) {
+	registry := test.NewImageRegistry()
+	registry.Image = signedImage()
+	storage := &REST{registry: registry}
+
+	channel, err := storage.Update(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta:  kapi.TypeMeta{ID: "security"},
+		Image:     "abc123",
+		Signer:    "security@example.com",
+		Signature: []byte("signature"),
+		Status:    api.ImageSignatureVerified,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := <-channel
+	status, ok := result.(*kapi.Status)
+	if !ok {
+		t.Fatalf("Expected status, got %#v", result)
+	}
+	if e, a := http.StatusNotFound, status.Code; e != a {
+		t.Errorf("Expected code %d, got %#v", e, status)
+	}
+	if e, a := 1, len(registry.Image.Signatures); e != a {
+		t.Errorf("Expected the image to be unchanged, got %#v", registry.Image.Signatures)
+	}
+}
+
+func TestCreateImageSignatureInvalid(t *testing.T) {
+	storage := &REST{registry: test.NewImageRegistry()}
+
+	_, err := storage.Create(kapi.NewDefaultContext(), &api.ImageSignature{
+		TypeMeta: kapi.TypeMeta{ID: "security"},
+		Image:    "abc123",
+		Signer:   "security@example.com",
+	})
+	if !errors.IsInvalid(err) {
+		t.Errorf("Expected an invalid error, got %v", err)
+	}
+}
+
+func TestCreateImageSignatureProvenanceForbidden(t *testing.T) {
+	testCases := map[string]*api.ImageSignature{
+		"provenance": {
+			TypeMeta:   kapi.TypeMeta{ID: "build-build2"},
+			Image:      "abc123",
+			Signer:     "security@example.com",
+			Provenance: &api.ImageProvenance{Build: "build2"},
+		},
+		"provenance signer": {
+			TypeMeta:  kapi.TypeMeta{ID: "build-build2"},
+			Image:     "abc123",
+			Signer:    api.ImageProvenanceSigner,
+			Signature: []byte("signature"),
+		},
+	}
+
+	for name, signature := range testCases {
+		registry := test.NewImageRegistry()
+		registry.Image = signedImage()
+		storage := &REST{registry: registry}
+
+		_, err := storage.Create(kapi.NewDefaultContext(), signature)
+		if !errors.IsInvalid(err) {
+			t.Errorf("%s: expected an invalid error, got %v", name, err)
+		}
+		if e, a := 1, len(registry.Image.Signatures); e != a {
+			t.Errorf("%s: expected the image to be unchanged, got %#v", name, registry.Image.Signatures)
+		}
+	}
+}
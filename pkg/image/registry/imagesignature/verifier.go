@@ -0,0 +1,71 @@
+package imagesignature
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// Verifier checks the signature blob of an ImageSignature against the Image it names.
+type Verifier interface {
+	// Verify returns the verification status of signature, which signs image.
+	Verify(image *api.Image, signature *api.ImageSignature) api.ImageSignatureStatus
+}
+
+// KeyringVerifier verifies RSA PKCS #1 v1.5 signatures of the SHA-256 digest of an image ID with the
+// public key of the signer. Signatures of unknown signers remain unverified.
+type KeyringVerifier struct {
+	// Keys are the trusted public keys by signer.
+	Keys map[string]*rsa.PublicKey
+}
+
+// Verify implements Verifier.
+func (v *KeyringVerifier) Verify(image *api.Image, signature *api.ImageSignature) api.ImageSignatureStatus {
+	key, ok := v.Keys[signature.Signer]
+	if !ok || len(signature.Signature) == 0 {
+		return api.ImageSignatureUnverified
+	}
+	digest := sha256.Sum256([]byte(image.ID))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature.Signature); err != nil {
+		return api.ImageSignatureRejected
+	}
+	return api.ImageSignatureVerified
+}
+
+// LoadKeyring creates a KeyringVerifier trusting the PEM encoded RSA public keys in the files named
+// <signer>.pem in dir.
+func LoadKeyring(dir string) (*KeyringVerifier, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM data found in %s", file)
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the public key in %s: %v", file, err)
+		}
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("the public key in %s is not an RSA key", file)
+		}
+		keys[strings.TrimSuffix(filepath.Base(file), ".pem")] = key
+	}
+	return &KeyringVerifier{Keys: keys}, nil
+}
@@ -0,0 +1,51 @@
+package imagesignature
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imagesigners")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err := ioutil.WriteFile(filepath.Join(dir, "security@example.com.pem"), data, 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	keyring, err := LoadKeyring(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := 1, len(keyring.Keys); e != a {
+		t.Fatalf("Expected %d keys, got %#v", e, keyring.Keys)
+	}
+	if loaded, ok := keyring.Keys["security@example.com"]; !ok || loaded.N.Cmp(key.PublicKey.N) != 0 {
+		t.Errorf("Expected the key of security@example.com, got %#v", keyring.Keys)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := LoadKeyring(dir); err == nil {
+		t.Errorf("Expected an error for a file without a key")
+	}
+}
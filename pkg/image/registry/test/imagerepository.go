@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
@@ -35,6 +36,23 @@ func (r *ImageRepositoryRegistry) GetImageRepository(ctx kapi.Context, id string
 	return r.ImageRepository, r.Err
 }
 
+func (r *ImageRepositoryRegistry) FindImageRepository(ctx kapi.Context, dockerRepo string) (*api.ImageRepository, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if r.ImageRepositories != nil {
+		for i := range r.ImageRepositories.Items {
+			if r.ImageRepositories.Items[i].DockerImageRepository == dockerRepo {
+				return &r.ImageRepositories.Items[i], nil
+			}
+		}
+	}
+	return nil, errors.NewNotFound("imageRepository", dockerRepo)
+}
+
 func (r *ImageRepositoryRegistry) WatchImageRepositories(ctx kapi.Context, resourceVersion string, filter func(repo *api.ImageRepository) bool) (watch.Interface, error) {
 	return nil, r.Err
 }
@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	kclient "github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// PushAction is the action of the events of a push notification which report a pushed image.
+const PushAction = "push"
+
+// Notification is the body of a push notification, a batch of events of the registry.
+type Notification struct {
+	Events []Event `json:"events"`
+}
+
+// Event describes an action the registry performed on a Docker repository.
+type Event struct {
+	// ID identifies the event.
+	ID string `json:"id,omitempty"`
+	// Action is what happened, only PushAction events are handled.
+	Action string `json:"action"`
+	// Target is the image the action was performed on.
+	Target Target `json:"target"`
+	// Request describes the request to the registry which caused the event.
+	Request Request `json:"request,omitempty"`
+}
+
+// Target identifies a pushed image.
+type Target struct {
+	// Repository is the name of the Docker repository on the registry, like "namespace/name".
+	Repository string `json:"repository"`
+	// Tag is the tag which was pushed.
+	Tag string `json:"tag"`
+	// Image is the ID of the image the tag points to.
+	Image string `json:"image"`
+	// Metadata is the metadata of the image. If it is omitted, it is read from the registry.
+	Metadata *docker.Image `json:"metadata,omitempty"`
+}
+
+// Request describes the request to the registry which caused an event.
+type Request struct {
+	// Host is the name the registry was addressed by, which prefixes the Docker repositories it hosts.
+	Host string `json:"host,omitempty"`
+}
+
+// controller handles push notifications by creating an ImageRepositoryMapping for every pushed tag,
+// which registers the image and updates the tag of the ImageRepository of the Docker repository.
+type controller struct {
+	client   clientInterface
+	registry dockerRegistry
+	// secret is the path notifications must be POSTed to, which only the registry knows
+	secret string
+	// registries are the registries the metadata of pushed images may be read from
+	registries util.StringSet
+}
+
+type clientInterface interface {
+	ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (*api.ImageRepositoryList, error)
+	CreateImageRepositoryMapping(ctx kapi.Context, mapping *api.ImageRepositoryMapping) error
+}
+
+type dockerRegistry interface {
+	Image(dockerRepo, id string) (*docker.Image, error)
+}
+
+// NewController creates a handler of push notifications, which reads the metadata of images the
+// notifications omit from registry. Notifications are only accepted at the path secret, relative to
+// the prefix the handler is served at, and none are accepted if secret is empty. Metadata is only read
+// from the listed registries, since the registry an event names is supplied by the sender of the
+// notification.
+func NewController(client clientInterface, registry dockerRegistry, secret string, registries ...string) http.Handler {
+	return &controller{client: client, registry: registry, secret: secret, registries: util.NewStringSet(registries...)}
+}
+
+// ServeHTTP handles a POSTed Notification. Events of Docker repositories without an ImageRepository
+// are ignored. If recording any pushed image fails the response is an error, so the registry sends
+// the notification again.
+func (c *controller) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(w, "push notifications must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(c.secret) == 0 || subtle.ConstantTimeCompare([]byte(strings.Trim(req.URL.Path, "/")), []byte(c.secret)) != 1 {
+		http.Error(w, "push notifications must be POSTed to the secret of the registry", http.StatusForbidden)
+		return
+	}
+	notification := Notification{}
+	if err := json.NewDecoder(req.Body).Decode(&notification); err != nil {
+		http.Error(w, fmt.Sprintf("invalid push notification: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// the registry doesn't know the namespace of a repository, but it may be given like for build webhooks.
+	// Like for mappings, repositories in any namespace are found when the namespace is the default one.
+	ctx := kapi.NewContext()
+	if namespace := req.URL.Query().Get("namespace"); len(namespace) > 0 {
+		ctx = kapi.WithNamespace(ctx, namespace)
+	}
+	ctx = kapi.WithNamespaceDefaultIfNone(ctx)
+
+	failed := []string{}
+	for _, event := range notification.Events {
+		if event.Action != PushAction {
+			continue
+		}
+		if err := c.handlePush(ctx, &event); err != nil {
+			glog.V(2).Infof("Error handling push of %s:%s: %v", event.Target.Repository, event.Target.Tag, err)
+			failed = append(failed, fmt.Sprintf("%s:%s: %v", event.Target.Repository, event.Target.Tag, err))
+		}
+	}
+	if len(failed) > 0 {
+		http.Error(w, strings.Join(failed, "\n"), http.StatusInternalServerError)
+	}
+}
+
+// handlePush creates the mapping of the tag the event reports as pushed.
+func (c *controller) handlePush(ctx kapi.Context, event *Event) error {
+	target := event.Target
+	if len(target.Repository) == 0 || len(target.Tag) == 0 || len(target.Image) == 0 {
+		return fmt.Errorf("the repository, tag and image of the pushed image are required")
+	}
+	dockerRepo := target.Repository
+	if len(event.Request.Host) > 0 {
+		dockerRepo = event.Request.Host + "/" + dockerRepo
+	}
+
+	known, err := c.hasImageRepository(ctx, dockerRepo)
+	if err != nil {
+		return err
+	}
+	if !known {
+		glog.V(4).Infof("Ignoring push to %s, no image repository refers to it", dockerRepo)
+		return nil
+	}
+
+	metadata := target.Metadata
+	if metadata == nil {
+		// the tag matters most, the metadata is completed by the next mapping of the image
+		metadata = &docker.Image{}
+		if c.registries.Has(event.Request.Host) {
+			image, err := c.registry.Image(dockerRepo, target.Image)
+			if err != nil {
+				glog.V(2).Infof("Error getting image %s of %s from the registry: %v", target.Image, dockerRepo, err)
+			} else {
+				metadata = image
+			}
+		} else {
+			glog.V(4).Infof("Not reading image %s of %s, the registry %q is not configured", target.Image, dockerRepo, event.Request.Host)
+		}
+	}
+
+	glog.V(4).Infof("Recording push of image %s to %s:%s", target.Image, dockerRepo, target.Tag)
+	err = c.client.CreateImageRepositoryMapping(ctx, &api.ImageRepositoryMapping{
+		DockerImageRepository: dockerRepo,
+		Image: api.Image{
+			TypeMeta:             kapi.TypeMeta{ID: target.Image},
+			DockerImageReference: dockerRepo + ":" + target.Tag,
+			Metadata:             *metadata,
+		},
+		Tag: target.Tag,
+	})
+	if isUnknownRepository(err) {
+		glog.V(4).Infof("Ignoring push to %s, no image repository refers to it", dockerRepo)
+		return nil
+	}
+	return err
+}
+
+// hasImageRepository returns true if an ImageRepository the mapping of a push to dockerRepo would
+// update exists: one in the namespace of ctx or, if that is the default namespace, in any namespace.
+func (c *controller) hasImageRepository(ctx kapi.Context, dockerRepo string) (bool, error) {
+	found, err := c.findImageRepository(ctx, dockerRepo)
+	if err != nil || found || kapi.Namespace(ctx) != kapi.NamespaceDefault {
+		return found, err
+	}
+	return c.findImageRepository(kapi.NewContext(), dockerRepo)
+}
+
+// findImageRepository returns true if an ImageRepository in the namespace of ctx, or in any namespace
+// if ctx has none, refers to dockerRepo.
+func (c *controller) findImageRepository(ctx kapi.Context, dockerRepo string) (bool, error) {
+	field := labels.SelectorFromSet(labels.Set{"dockerImageRepository": dockerRepo})
+	repos, err := c.client.ListImageRepositories(ctx, field, labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, repo := range repos.Items {
+		if repo.DockerImageRepository == dockerRepo {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isUnknownRepository returns true if a mapping failed because no ImageRepository refers to its
+// Docker repository.
+func isUnknownRepository(err error) bool {
+	if !errors.IsInvalid(err) {
+		return false
+	}
+	status, ok := err.(kclient.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Field == "DockerImageRepository" && cause.Type == kapi.CauseTypeFieldValueNotFound {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kerrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/fsouza/go-dockerclient"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+type fakeMappings struct {
+	repos      []api.ImageRepository
+	listErr    error
+	mappings   []*api.ImageRepositoryMapping
+	namespaces []string
+	err        error
+}
+
+func (f *fakeMappings) ListImageRepositories(ctx kapi.Context, field, label labels.Selector) (*api.ImageRepositoryList, error) {
+	list := &api.ImageRepositoryList{}
+	for _, repo := range f.repos {
+		if namespace := kapi.Namespace(ctx); (len(namespace) == 0 || repo.Namespace == namespace) && field.Matches(labels.Set{"dockerImageRepository": repo.DockerImageRepository}) {
+			list.Items = append(list.Items, repo)
+		}
+	}
+	return list, f.listErr
+}
+
+func (f *fakeMappings) CreateImageRepositoryMapping(ctx kapi.Context, mapping *api.ImageRepositoryMapping) error {
+	f.mappings = append(f.mappings, mapping)
+	f.namespaces = append(f.namespaces, kapi.Namespace(ctx))
+	return f.err
+}
+
+type fakeRegistry struct {
+	images    map[string]*docker.Image
+	requested []string
+}
+
+func (f *fakeRegistry) Image(dockerRepo, id string) (*docker.Image, error) {
+	f.requested = append(f.requested, dockerRepo+"@"+id)
+	image, ok := f.images[dockerRepo+"@"+id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return image, nil
+}
+
+func repository(namespace, dockerRepo string) api.ImageRepository {
+	return api.ImageRepository{
+		TypeMeta:              kapi.TypeMeta{ID: "repo", Namespace: namespace},
+		DockerImageRepository: dockerRepo,
+	}
+}
+
+func post(handler http.Handler, url, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/secret"+url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestPushCreatesMappings(t *testing.T) {
+	mappings := &fakeMappings{repos: []api.ImageRepository{
+		repository("app", "registry:5000/app/frontend"),
+		repository("app", "app/backend"),
+	}}
+	registry := &fakeRegistry{images: map[string]*docker.Image{
+		"registry:5000/app/frontend@abc123": {ID: "abc123", Author: "fetched"},
+	}}
+	handler := NewController(mappings, registry, "secret", "registry:5000")
+
+	w := post(handler, "/?namespace=app", `{"events": [
+		{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}, "request": {"host": "registry:5000"}},
+		{"action": "pull", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}, "request": {"host": "registry:5000"}},
+		{"action": "push", "target": {"repository": "app/backend", "tag": "v1", "image": "def456", "metadata": {"id": "def456", "author": "pushed"}}}
+	]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	if len(mappings.mappings) != 2 {
+		t.Fatalf("Expected two mappings, got %#v", mappings.mappings)
+	}
+	mapping := mappings.mappings[0]
+	if e, a := "registry:5000/app/frontend", mapping.DockerImageRepository; e != a {
+		t.Errorf("Expected repository %s, got %s", e, a)
+	}
+	if mapping.Tag != "latest" || mapping.Image.ID != "abc123" || mapping.Image.DockerImageReference != "registry:5000/app/frontend:latest" {
+		t.Errorf("Unexpected mapping %#v", mapping)
+	}
+	if e, a := "fetched", mapping.Image.Metadata.Author; e != a {
+		t.Errorf("Expected the metadata read from the registry, got %#v", mapping.Image.Metadata)
+	}
+	if e, a := "app", mappings.namespaces[0]; e != a {
+		t.Errorf("Expected the mapping in namespace %s, got %s", e, a)
+	}
+
+	mapping = mappings.mappings[1]
+	if mapping.DockerImageRepository != "app/backend" || mapping.Tag != "v1" {
+		t.Errorf("Unexpected mapping %#v", mapping)
+	}
+	if e, a := "pushed", mapping.Image.Metadata.Author; e != a {
+		t.Errorf("Expected the metadata of the notification, got %#v", mapping.Image.Metadata)
+	}
+}
+
+func TestPushDefaultNamespace(t *testing.T) {
+	mappings := &fakeMappings{repos: []api.ImageRepository{repository(kapi.NamespaceDefault, "app/frontend")}}
+	handler := NewController(mappings, &fakeRegistry{}, "secret")
+
+	w := post(handler, "/", `{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	if len(mappings.mappings) != 1 {
+		t.Fatalf("Expected one mapping, got %#v", mappings.mappings)
+	}
+	if e, a := kapi.NamespaceDefault, mappings.namespaces[0]; e != a {
+		t.Errorf("Expected the mapping in namespace %s, got %s", e, a)
+	}
+}
+
+func TestPushDefaultNamespaceFindsAnyNamespace(t *testing.T) {
+	mappings := &fakeMappings{repos: []api.ImageRepository{repository("app", "app/frontend")}}
+	handler := NewController(mappings, &fakeRegistry{}, "secret")
+
+	w := post(handler, "/", `{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	if len(mappings.mappings) != 1 {
+		t.Errorf("Expected the push to a repository outside the default namespace to be mapped, got %#v", mappings.mappings)
+	}
+}
+
+func TestPushSecretRequired(t *testing.T) {
+	testCases := map[string]struct {
+		secret string
+		path   string
+	}{
+		"no secret":                  {"secret", "/"},
+		"wrong secret":               {"secret", "/other"},
+		"secret not set":             {"", "/"},
+		"secret not set, path given": {"", "/secret"},
+	}
+
+	for name, testCase := range testCases {
+		mappings := &fakeMappings{repos: []api.ImageRepository{repository(kapi.NamespaceDefault, "app/frontend")}}
+		handler := NewController(mappings, &fakeRegistry{}, testCase.secret)
+		req, _ := http.NewRequest("POST", testCase.path, strings.NewReader(`{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}}]}`))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: unexpected response %d: %s", name, w.Code, w.Body.String())
+		}
+		if len(mappings.mappings) != 0 {
+			t.Errorf("%s: unexpected mappings %#v", name, mappings.mappings)
+		}
+	}
+}
+
+func TestPushUnknownRepositoryIgnored(t *testing.T) {
+	mappings := &fakeMappings{repos: []api.ImageRepository{repository("other", "registry:5000/app/frontend")}}
+	registry := &fakeRegistry{}
+	handler := NewController(mappings, registry, "secret", "registry:5000")
+
+	w := post(handler, "/?namespace=app", `{"events": [
+		{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}, "request": {"host": "registry:5000"}},
+		{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}, "request": {"host": "internal:8080"}}
+	]}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	if len(mappings.mappings) != 0 {
+		t.Errorf("Unexpected mappings %#v", mappings.mappings)
+	}
+	if len(registry.requested) != 0 {
+		t.Errorf("Expected no registry to be contacted, got %v", registry.requested)
+	}
+}
+
+func TestPushUnconfiguredRegistryNotContacted(t *testing.T) {
+	mappings := &fakeMappings{repos: []api.ImageRepository{repository(kapi.NamespaceDefault, "internal:8080/app/frontend")}}
+	registry := &fakeRegistry{}
+	handler := NewController(mappings, registry, "secret", "registry:5000")
+
+	w := post(handler, "/", `{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}, "request": {"host": "internal:8080"}}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	if len(registry.requested) != 0 {
+		t.Errorf("Expected no registry to be contacted, got %v", registry.requested)
+	}
+	if len(mappings.mappings) != 1 || mappings.mappings[0].Image.Metadata.ID != "" {
+		t.Errorf("Expected one mapping without metadata, got %#v", mappings.mappings)
+	}
+}
+
+func TestPushRepositoryRemovedIgnored(t *testing.T) {
+	mappings := &fakeMappings{
+		repos: []api.ImageRepository{repository(kapi.NamespaceDefault, "app/frontend")},
+		err: kerrors.NewInvalid("imageRepositoryMapping", "", kerrors.ErrorList{
+			kerrors.NewFieldNotFound("DockerImageRepository", "app/frontend"),
+		}),
+	}
+	handler := NewController(mappings, &fakeRegistry{}, "secret")
+
+	w := post(handler, "/", `{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}}]}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPushErrors(t *testing.T) {
+	testCases := map[string]struct {
+		method string
+		body   string
+		err    error
+		code   int
+	}{
+		"not POSTed":       {"GET", "", nil, http.StatusMethodNotAllowed},
+		"invalid body":     {"POST", "{", nil, http.StatusBadRequest},
+		"incomplete event": {"POST", `{"events": [{"action": "push", "target": {"repository": "app/frontend"}}]}`, nil, http.StatusInternalServerError},
+		"mapping error":    {"POST", `{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}}]}`, errors.New("etcd down"), http.StatusInternalServerError},
+	}
+
+	for name, testCase := range testCases {
+		mappings := &fakeMappings{repos: []api.ImageRepository{repository(kapi.NamespaceDefault, "app/frontend")}, err: testCase.err}
+		handler := NewController(mappings, &fakeRegistry{}, "secret")
+		req, _ := http.NewRequest(testCase.method, "/secret", strings.NewReader(testCase.body))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if e, a := testCase.code, w.Code; e != a {
+			t.Errorf("%s: expected response %d, got %d: %s", name, e, a, w.Body.String())
+		}
+	}
+}
+
+func TestPushLookupError(t *testing.T) {
+	registry := &fakeRegistry{}
+	handler := NewController(&fakeMappings{listErr: errors.New("etcd down")}, registry, "secret", "registry:5000")
+
+	w := post(handler, "/", `{"events": [{"action": "push", "target": {"repository": "app/frontend", "tag": "latest", "image": "abc123"}, "request": {"host": "registry:5000"}}]}`)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	if len(registry.requested) != 0 {
+		t.Errorf("Expected no registry to be contacted, got %v", registry.requested)
+	}
+}
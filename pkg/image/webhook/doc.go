@@ -0,0 +1,3 @@
+// Package webhook serves the notifications Docker registries send when an image is pushed, and
+// records the pushed images and tags on the ImageRepositories of the pushed Docker repositories.
+package webhook
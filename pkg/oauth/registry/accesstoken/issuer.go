@@ -0,0 +1,49 @@
+package accesstoken
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	kapi "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/oauth/api"
+)
+
+// NamespaceScope returns the scope of access tokens which are limited to namespace.
+func NamespaceScope(namespace string) string {
+	return "namespace:" + namespace
+}
+
+// NamespaceTokenIssuer issues access tokens on behalf of UserName which are scoped to a single
+// namespace and expire after ExpiresIn seconds.
+type NamespaceTokenIssuer struct {
+	Registry  Registry
+	UserName  string
+	ExpiresIn int64
+}
+
+// IssueToken stores a new access token scoped to namespace and returns its secret.
+func (i *NamespaceTokenIssuer) IssueToken(namespace string) (string, error) {
+	if len(namespace) == 0 {
+		return "", errors.New("a namespace is required to issue a namespace scoped token")
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := &api.AccessToken{
+		TypeMeta: kapi.TypeMeta{CreationTimestamp: util.Now()},
+		Name:     base64.URLEncoding.EncodeToString(secret),
+		AuthorizeToken: api.AuthorizeToken{
+			UserName:  i.UserName,
+			ExpiresIn: i.ExpiresIn,
+			Scopes:    []string{NamespaceScope(namespace)},
+		},
+	}
+	if err := i.Registry.CreateAccessToken(token); err != nil {
+		return "", err
+	}
+	return token.Name, nil
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/openshift/origin/pkg/build/webhook"
 	"github.com/openshift/origin/pkg/build/webhook/github"
 	osclient "github.com/openshift/origin/pkg/client"
+	imageetcd "github.com/openshift/origin/pkg/image/registry/etcd"
+	"github.com/openshift/origin/pkg/image/registry/imagesignature"
 )
 
 func init() {
@@ -193,6 +195,7 @@ func NewTestBuildOpenshift(t *testing.T) *testBuildOpenshift {
 	factory := buildcontrollerfactory.BuildControllerFactory{
 		Client:     osClient,
 		KubeClient: kubeClient,
+		Builds:     buildcontrollerfactory.NewBuildCache(osClient),
 		DockerBuildStrategy: &buildstrategy.DockerBuildStrategy{
 			BuilderImage:   "test-docker-builder",
 			UseLocalImages: false,
@@ -202,6 +205,7 @@ func NewTestBuildOpenshift(t *testing.T) *testBuildOpenshift {
 			TempDirectoryCreator: buildstrategy.STITempDirectoryCreator,
 			UseLocalImages:       false,
 		},
+		ImageSignatures: imagesignature.NewProvenanceRecorder(imageetcd.New(etcdHelper)),
 	}
 
 	factory.Create().Run()
@@ -239,13 +239,20 @@ func NewTestOpenshift(t *testing.T) *testOpenshift {
 		DeploymentInterface:       deployEtcd,
 		DeploymentConfigInterface: deployEtcd,
 		ImageRepositoryInterface:  imageEtcd,
+		ImageInterface:            imageEtcd,
+	}
+
+	deployCanceller := &deployregistry.DeploymentCanceller{
+		Registry:                    deployEtcd,
+		PodClient:                   kubeClient,
+		ReplicationControllerClient: kubeClient,
 	}
 
 	storage := map[string]apiserver.RESTStorage{
 		"images":                    image.NewREST(imageEtcd),
 		"imageRepositories":         imagerepository.NewREST(imageEtcd),
 		"imageRepositoryMappings":   imagerepositorymapping.NewREST(imageEtcd, imageEtcd),
-		"deployments":               deployregistry.NewREST(deployEtcd),
+		"deployments":               deployregistry.NewREST(deployEtcd, deployCanceller, nil),
 		"deploymentConfigs":         deployconfigregistry.NewREST(deployEtcd),
 		"generateDeploymentConfigs": deployconfiggenerator.NewREST(deployConfigGenerator, v1beta1.Codec),
 	}
@@ -263,13 +270,15 @@ func NewTestOpenshift(t *testing.T) *testOpenshift {
 		t.Errorf("Expected %#v, got %#v", e, a)
 	}
 
-	dccFactory := deploycontrollerfactory.DeploymentConfigControllerFactory{osClient}
+	caches := deploycontrollerfactory.NewSharedCaches(osClient)
+
+	dccFactory := deploycontrollerfactory.DeploymentConfigControllerFactory{Client: osClient, Caches: caches}
 	dccFactory.Create().Run()
 
-	cccFactory := deploycontrollerfactory.DeploymentConfigChangeControllerFactory{osClient}
+	cccFactory := deploycontrollerfactory.DeploymentConfigChangeControllerFactory{Client: osClient, Caches: caches}
 	cccFactory.Create().Run()
 
-	iccFactory := deploycontrollerfactory.ImageChangeControllerFactory{osClient}
+	iccFactory := deploycontrollerfactory.ImageChangeControllerFactory{Client: osClient, Caches: caches}
 	iccFactory.Create().Run()
 
 	return openshift